@@ -2,17 +2,27 @@ package http
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo"
 	"github.com/sirupsen/logrus"
 	validator "gopkg.in/go-playground/validator.v9"
 
-	"github.com/bxcodec/go-clean-arch/article"
-	"github.com/bxcodec/go-clean-arch/models"
+	"github.com/article"
+	"github.com/mergepatch"
+	"github.com/middleware"
+	"github.com/models"
 )
 
+// errPreconditionRequired will throw if an update does not say which version of the article it applies to
+var errPreconditionRequired = errors.New("The If-Match header must carry the ETag of the article")
+
 // ResponseError represent the reseponse error struct
 type ResponseError struct {
 	Message string `json:"message"`
@@ -24,18 +34,64 @@ type ArticleHandler struct {
 }
 
 // NewArticleHandler will initialize the articles/ resources endpoint
-func NewArticleHandler(e *echo.Echo, us article.Usecase) {
+func NewArticleHandler(e *echo.Echo, us article.Usecase, mw *middleware.GoMiddleware) {
 	handler := &ArticleHandler{
 		AUsecase: us,
 	}
+	adminOnly := mw.Authorize(middleware.Policy{Roles: []string{models.RoleAdmin}})
+	userOrAdmin := mw.Authorize(middleware.Policy{Roles: []string{models.RoleUser, models.RoleAdmin}})
 	e.GET("/articles", handler.FetchArticle)
-	e.POST("/articles", handler.Store)
+	e.GET("/articles/search", handler.SearchArticle)
+	e.POST("/articles", handler.Store, mw.Authenticate, userOrAdmin)
 	e.GET("/articles/:id", handler.GetByID)
-	e.DELETE("/articles/:id", handler.Delete)
+	e.PUT("/articles/:id", handler.Update, mw.Authenticate, adminOnly)
+	e.PATCH("/articles/:id", handler.Patch, mw.Authenticate, adminOnly)
+	e.DELETE("/articles/:id", handler.Delete, mw.Authenticate, adminOnly)
+	e.GET("/authors/:id/articles", handler.FetchAuthorArticle)
 }
 
-// FetchArticle will fetch the article based on given params
+// FetchArticle will fetch the article based on given params, optionally only those of the category tag
 func (a *ArticleHandler) FetchArticle(c echo.Context) error {
+	return a.fetch(c, &models.ArticleFilter{Category: c.QueryParam("category")})
+}
+
+// FetchAuthorArticle will fetch the articles of the author by given id, with the same params as FetchArticle
+func (a *ArticleHandler) FetchAuthorArticle(c echo.Context) error {
+	authorID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: models.ErrNotFound.Error()})
+	}
+	return a.fetch(c, &models.ArticleFilter{Category: c.QueryParam("category"), AuthorID: authorID})
+}
+
+// SearchArticle will search the articles for the words of the q param, the most relevant first,
+// optionally only those of the author id and the category tag
+func (a *ArticleHandler) SearchArticle(c echo.Context) error {
+	filter := &models.ArticleFilter{Category: c.QueryParam("category")}
+	if authorS := c.QueryParam("author"); authorS != "" {
+		authorID, err := strconv.ParseInt(authorS, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ResponseError{Message: models.ErrBadParamInput.Error()})
+		}
+		filter.AuthorID = authorID
+	}
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	hits, nextCursor, err := a.AUsecase.Search(ctx, c.QueryParam("q"), filter, cursor, int64(num))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, hits)
+}
+
+func (a *ArticleHandler) fetch(c echo.Context, filter *models.ArticleFilter) error {
 	numS := c.QueryParam("num")
 	num, _ := strconv.Atoi(numS)
 	cursor := c.QueryParam("cursor")
@@ -43,7 +99,7 @@ func (a *ArticleHandler) FetchArticle(c echo.Context) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num))
+	listAr, nextCursor, err := a.AUsecase.Fetch(ctx, filter, cursor, int64(num))
 
 	if err != nil {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
@@ -69,6 +125,7 @@ func (a *ArticleHandler) GetByID(c echo.Context) error {
 	if err != nil {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
+	c.Response().Header().Set("ETag", etag(art))
 	return c.JSON(http.StatusOK, art)
 }
 
@@ -81,7 +138,7 @@ func isRequestValid(m *models.Article) (bool, error) {
 	return true, nil
 }
 
-// Store will store the article by given request body
+// Store will store the article by given request body, written by the given author or by the author of the user
 func (a *ArticleHandler) Store(c echo.Context) error {
 	var article models.Article
 	err := c.Bind(&article)
@@ -97,14 +154,143 @@ func (a *ArticleHandler) Store(c echo.Context) error {
 		ctx = context.Background()
 	}
 
-	err = a.AUsecase.Store(ctx, &article)
+	// a user who is not an admin publishes as the author linked to the account
+	if principal := middleware.GetPrincipal(c); principal != nil && !principal.HasRole(models.RoleAdmin) {
+		err = a.AUsecase.Publish(ctx, &article, principal.Id)
+	} else {
+		err = a.AUsecase.Store(ctx, &article)
+	}
 
 	if err != nil {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
+	c.Response().Header().Set("ETag", etag(&article))
 	return c.JSON(http.StatusCreated, article)
 }
 
+// Update will replace the article by given id with the request body.
+// The If-Match header must carry the ETag of the article as it was read, an article changed since is answered with 412.
+func (a *ArticleHandler) Update(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: models.ErrNotFound.Error()})
+	}
+	version, err := ifMatch(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	var article models.Article
+	if err := c.Bind(&article); err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+	}
+	article.ID = id
+	article.Version = version
+
+	return a.update(c, &article)
+}
+
+// Patch will apply the JSON Merge Patch request body to the article by given id, a null member removes the categories.
+// The If-Match header must carry the ETag of the article as it was read, an article changed since is answered with 412.
+func (a *ArticleHandler) Patch(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: models.ErrNotFound.Error()})
+	}
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mediaType != mergepatch.MediaType && mediaType != echo.MIMEApplicationJSON {
+		return c.JSON(http.StatusUnsupportedMediaType, ResponseError{Message: "The patch must be sent as " + mergepatch.MediaType})
+	}
+	version, err := ifMatch(c)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	patch, err := ioutil.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	current, err := a.AUsecase.GetByID(ctx, id)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if current.Version != version {
+		return c.JSON(http.StatusPreconditionFailed, ResponseError{Message: models.ErrPreconditionFailed.Error()})
+	}
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ResponseError{Message: err.Error()})
+	}
+	merged, err := mergepatch.Apply(doc, patch)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	var article models.Article
+	if err := json.Unmarshal(merged, &article); err != nil {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	article.ID = id
+	article.Version = version
+	article.CreatedAt = current.CreatedAt
+
+	// the categories are only written when the patch changes them
+	var members map[string]json.RawMessage
+	_ = json.Unmarshal(patch, &members)
+	switch raw, ok := members["categories"]; {
+	case !ok:
+		article.Categories = nil
+	case string(raw) == "null":
+		article.Categories = []models.Category{}
+	}
+
+	return a.update(c, &article)
+}
+
+func (a *ArticleHandler) update(c echo.Context, article *models.Article) error {
+	if ok, err := isRequestValid(article); !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := a.AUsecase.Update(ctx, article); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	res, err := a.AUsecase.GetByID(ctx, article.ID)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	c.Response().Header().Set("ETag", etag(res))
+	return c.JSON(http.StatusOK, res)
+}
+
+// etag will return the ETag of the article, its quoted version
+func etag(ar *models.Article) string {
+	return `"` + strconv.FormatInt(ar.Version, 10) + `"`
+}
+
+// ifMatch will return the version of the article carried by the If-Match header.
+// Only the strong ETag of a single version is accepted, any other value never matches.
+func ifMatch(c echo.Context) (int64, error) {
+	header := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if header == "" {
+		return 0, errPreconditionRequired
+	}
+	if len(header) < 2 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		return 0, models.ErrPreconditionFailed
+	}
+	version, err := strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil {
+		return 0, models.ErrPreconditionFailed
+	}
+	return version, nil
+}
+
 // Delete will delete article by given param
 func (a *ArticleHandler) Delete(c echo.Context) error {
 	idP, err := strconv.Atoi(c.Param("id"))
@@ -130,13 +316,23 @@ func getStatusCode(err error) int {
 		return http.StatusOK
 	}
 	logrus.Error(err)
-	switch err {
-	case models.ErrInternalServerError:
+	switch {
+	case errors.Is(err, models.ErrInternalServerError):
 		return http.StatusInternalServerError
-	case models.ErrNotFound:
+	case errors.Is(err, models.ErrNotFound):
 		return http.StatusNotFound
-	case models.ErrConflict:
+	case errors.Is(err, models.ErrConflict):
 		return http.StatusConflict
+	case errors.Is(err, models.ErrUnAuthorize):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, errPreconditionRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, models.ErrBadParamInput):
+		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
 	}
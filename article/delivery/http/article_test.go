@@ -15,9 +15,11 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
-	articleHttp "github.com/bxcodec/go-clean-arch/article/delivery/http"
-	"github.com/bxcodec/go-clean-arch/article/mocks"
-	"github.com/bxcodec/go-clean-arch/models"
+	articleHttp "github.com/article/delivery/http"
+	"github.com/article/mocks"
+	"github.com/author"
+	"github.com/category"
+	"github.com/models"
 )
 
 func TestFetch(t *testing.T) {
@@ -29,7 +31,7 @@ func TestFetch(t *testing.T) {
 	mockListArticle = append(mockListArticle, &mockArticle)
 	num := 1
 	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(mockListArticle, "10", nil)
+	mockUCase.On("Fetch", mock.Anything, &models.ArticleFilter{}, cursor, int64(num)).Return(mockListArticle, "10", nil)
 
 	e := echo.New()
 	req, err := http.NewRequest(echo.GET, "/article?num=1&cursor="+cursor, strings.NewReader(""))
@@ -53,7 +55,7 @@ func TestFetchError(t *testing.T) {
 	mockUCase := new(mocks.Usecase)
 	num := 1
 	cursor := "2"
-	mockUCase.On("Fetch", mock.Anything, cursor, int64(num)).Return(nil, "", models.ErrInternalServerError)
+	mockUCase.On("Fetch", mock.Anything, &models.ArticleFilter{}, cursor, int64(num)).Return(nil, "", models.ErrInternalServerError)
 
 	e := echo.New()
 	req, err := http.NewRequest(echo.GET, "/article?num=1&cursor="+cursor, strings.NewReader(""))
@@ -73,6 +75,28 @@ func TestFetchError(t *testing.T) {
 	mockUCase.AssertExpectations(t)
 }
 
+func TestFetchByCategory(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	mockUCase.On("Fetch", mock.Anything, &models.ArticleFilter{Category: "travel"}, "", int64(0)).
+		Return([]*models.Article{{ID: 1, Categories: []models.Category{{ID: 1, Name: "Travel", Tag: "travel"}}}}, "", nil)
+
+	e := echo.New()
+	req, err := http.NewRequest(echo.GET, "/articles?category=travel", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := articleHttp.ArticleHandler{
+		AUsecase: mockUCase,
+	}
+	err = handler.FetchArticle(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"categories":[{"id":1,"name":"Travel","tag":"travel"`)
+	mockUCase.AssertExpectations(t)
+}
+
 func TestGetByID(t *testing.T) {
 	var mockArticle models.Article
 	err := faker.FakeData(&mockArticle)
@@ -139,6 +163,28 @@ func TestStore(t *testing.T) {
 	mockUCase.AssertExpectations(t)
 }
 
+func TestStoreUnknownCategory(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	mockUCase.On("Store", mock.Anything, mock.AnythingOfType("*models.Article")).Return(category.ErrUnknownCategory)
+
+	e := echo.New()
+	body := `{"title": "Title", "content": "Content", "categories": [{"tag": "nope"}]}`
+	req, err := http.NewRequest(echo.POST, "/article", strings.NewReader(body))
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	handler := articleHttp.ArticleHandler{
+		AUsecase: mockUCase,
+	}
+	err = handler.Store(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
 func TestDelete(t *testing.T) {
 	var mockArticle models.Article
 	err := faker.FakeData(&mockArticle)
@@ -169,3 +215,195 @@ func TestDelete(t *testing.T) {
 	mockUCase.AssertExpectations(t)
 
 }
+
+func serveUpdate(handler echo.HandlerFunc, method string, body string, contentType string, ifMatch string) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(method, "/articles/5", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, contentType)
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/articles/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("5")
+	_ = handler(c)
+	return rec
+}
+
+func TestUpdate(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	handler := articleHttp.ArticleHandler{
+		AUsecase: mockUCase,
+	}
+	body := `{"title": "Title", "content": "Content"}`
+	mockUCase.On("Update", mock.Anything, &models.Article{ID: 5, Title: "Title", Content: "Content", Version: 3}).Return(nil).Once()
+	mockUCase.On("GetByID", mock.Anything, int64(5)).Return(&models.Article{ID: 5, Title: "Title", Content: "Content", Version: 4}, nil).Once()
+
+	rec := serveUpdate(handler.Update, echo.PUT, body, echo.MIMEApplicationJSON, `"3"`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+
+	rec = serveUpdate(handler.Update, echo.PUT, body, echo.MIMEApplicationJSON, "")
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+
+	rec = serveUpdate(handler.Update, echo.PUT, body, echo.MIMEApplicationJSON, `W/"3"`)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	mockUCase.On("Update", mock.Anything, &models.Article{ID: 5, Title: "Title", Content: "Content", Version: 2}).Return(models.ErrPreconditionFailed).Once()
+	rec = serveUpdate(handler.Update, echo.PUT, body, echo.MIMEApplicationJSON, `"2"`)
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	mockUCase.On("Update", mock.Anything, &models.Article{ID: 5, Title: "Title", Content: "Content", Version: 4}).Return(models.ErrConflict).Once()
+	rec = serveUpdate(handler.Update, echo.PUT, body, echo.MIMEApplicationJSON, `"4"`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestPatch(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	current := func() *models.Article {
+		return &models.Article{ID: 5, Title: "Old", Content: "Body", Author: models.Author{ID: 1, Name: "Iman"}, CreatedAt: created,
+			Version: 3, Categories: []models.Category{{ID: 1, Name: "Travel", Tag: "travel"}}}
+	}
+
+	t.Run("title", func(t *testing.T) {
+		mockUCase := new(mocks.Usecase)
+		handler := articleHttp.ArticleHandler{AUsecase: mockUCase}
+		mockUCase.On("GetByID", mock.Anything, int64(5)).Return(current(), nil).Once()
+		mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *models.Article) bool {
+			return ar.ID == 5 && ar.Title == "New" && ar.Content == "Body" && ar.Author.ID == 1 && ar.Version == 3 &&
+				ar.CreatedAt.Equal(created) && ar.Categories == nil
+		})).Return(nil).Once()
+		updated := current()
+		updated.Title, updated.Version = "New", 4
+		mockUCase.On("GetByID", mock.Anything, int64(5)).Return(updated, nil).Once()
+
+		rec := serveUpdate(handler.Patch, echo.PATCH, `{"title": "New"}`, "application/merge-patch+json", `"3"`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+		mockUCase.AssertExpectations(t)
+	})
+
+	t.Run("categories", func(t *testing.T) {
+		mockUCase := new(mocks.Usecase)
+		handler := articleHttp.ArticleHandler{AUsecase: mockUCase}
+		mockUCase.On("GetByID", mock.Anything, int64(5)).Return(current(), nil)
+		mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *models.Article) bool {
+			return ar.Categories != nil && len(ar.Categories) == 0
+		})).Return(nil).Once()
+		mockUCase.On("Update", mock.Anything, mock.MatchedBy(func(ar *models.Article) bool {
+			return len(ar.Categories) == 1 && ar.Categories[0].Tag == "food"
+		})).Return(nil).Once()
+
+		rec := serveUpdate(handler.Patch, echo.PATCH, `{"categories": null}`, "application/merge-patch+json", `"3"`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		rec = serveUpdate(handler.Patch, echo.PATCH, `{"categories": [{"tag": "food"}]}`, "application/merge-patch+json", `"3"`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUCase.AssertNumberOfCalls(t, "Update", 2)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		mockUCase := new(mocks.Usecase)
+		handler := articleHttp.ArticleHandler{AUsecase: mockUCase}
+		mockUCase.On("GetByID", mock.Anything, int64(5)).Return(current(), nil)
+
+		rec := serveUpdate(handler.Patch, echo.PATCH, `{"title": "New"}`, "application/merge-patch+json", `"2"`)
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+		rec = serveUpdate(handler.Patch, echo.PATCH, `{"title": null}`, "application/merge-patch+json", `"3"`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = serveUpdate(handler.Patch, echo.PATCH, `{"title": 1}`, "application/merge-patch+json", `"3"`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = serveUpdate(handler.Patch, echo.PATCH, `title=New`, echo.MIMEApplicationForm, `"3"`)
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchAuthorArticle(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	mockUCase.On("Fetch", mock.Anything, &models.ArticleFilter{AuthorID: 3, Category: "travel"}, "", int64(5)).
+		Return([]*models.Article{{ID: 1, Author: models.Author{ID: 3}}}, "", nil).Once()
+	mockUCase.On("Fetch", mock.Anything, &models.ArticleFilter{AuthorID: 4}, "", int64(0)).
+		Return(nil, "", models.ErrNotFound).Once()
+
+	e := echo.New()
+	handler := articleHttp.ArticleHandler{
+		AUsecase: mockUCase,
+	}
+	e.GET("/authors/:id/articles", handler.FetchAuthorArticle)
+
+	req := httptest.NewRequest(echo.GET, "/authors/3/articles?num=5&category=travel", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(echo.GET, "/authors/4/articles", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestStoreAsUser(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	mockUCase.On("Publish", mock.Anything, mock.AnythingOfType("*models.Article"), "u1").Return(nil).Once()
+	mockUCase.On("Publish", mock.Anything, mock.AnythingOfType("*models.Article"), "u2").Return(author.ErrNotLinked).Once()
+
+	handler := articleHttp.ArticleHandler{
+		AUsecase: mockUCase,
+	}
+	for userId, code := range map[string]int{"u1": http.StatusCreated, "u2": http.StatusForbidden} {
+		e := echo.New()
+		req := httptest.NewRequest(echo.POST, "/articles", strings.NewReader(`{"title": "Title", "content": "Content"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("principal", &models.Principal{Id: userId, Roles: []string{models.RoleUser}})
+
+		require.NoError(t, handler.Store(c))
+		assert.Equal(t, code, rec.Code, userId)
+	}
+	mockUCase.AssertExpectations(t)
+}
+
+func TestSearchArticle(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	mockUCase.On("Search", mock.Anything, "street food", &models.ArticleFilter{Category: "travel", AuthorID: 2}, "c1", int64(5)).
+		Return([]*models.ArticleHit{{Article: models.Article{ID: 1, Title: "Street food"}, Score: 1.5, Snippet: "<mark>food</mark>"}}, "c2", nil).Once()
+	mockUCase.On("Search", mock.Anything, "", &models.ArticleFilter{}, "", int64(0)).Return(nil, "", models.ErrBadParamInput).Once()
+
+	e := echo.New()
+	handler := articleHttp.ArticleHandler{
+		AUsecase: mockUCase,
+	}
+	e.GET("/articles/search", handler.SearchArticle)
+	e.GET("/articles/:id", handler.GetByID)
+
+	req := httptest.NewRequest(echo.GET, "/articles/search?q=street+food&category=travel&author=2&num=5&cursor=c1", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "c2", rec.Header().Get("X-Cursor"))
+	var hits []map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &hits))
+	require.Len(t, hits, 1)
+	assert.Equal(t, "Street food", hits[0]["title"])
+	assert.Equal(t, 1.5, hits[0]["score"])
+	assert.Equal(t, "<mark>food</mark>", hits[0]["snippet"])
+
+	req = httptest.NewRequest(echo.GET, "/articles/search", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = httptest.NewRequest(echo.GET, "/articles/search?q=food&author=me", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
@@ -3,7 +3,7 @@ package mocks
 
 import context "context"
 import mock "github.com/stretchr/testify/mock"
-import models "github.com/bxcodec/go-clean-arch/models"
+import models "github.com/models"
 
 // Repository is an autogenerated mock type for the Repository type
 type Repository struct {
@@ -24,13 +24,13 @@ func (_m *Repository) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *Repository) Fetch(ctx context.Context, cursor string, num int64) ([]*models.Article, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *Repository) Fetch(ctx context.Context, filter *models.ArticleFilter, cursor string, num int64) ([]*models.Article, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
 
 	var r0 []*models.Article
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []*models.Article); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ArticleFilter, string, int64) []*models.Article); ok {
+		r0 = rf(ctx, filter, cursor, num)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*models.Article)
@@ -38,15 +38,15 @@ func (_m *Repository) Fetch(ctx context.Context, cursor string, num int64) ([]*m
 	}
 
 	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(1).(func(context.Context, *models.ArticleFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, *models.ArticleFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -54,6 +54,29 @@ func (_m *Repository) Fetch(ctx context.Context, cursor string, num int64) ([]*m
 	return r0, r1, r2
 }
 
+// FetchAll provides a mock function with given fields: ctx
+func (_m *Repository) FetchAll(ctx context.Context) ([]*models.Article, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*models.Article
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.Article); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Article)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *Repository) GetByID(ctx context.Context, id int64) (*models.Article, error) {
 	ret := _m.Called(ctx, id)
@@ -0,0 +1,69 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Searcher is an autogenerated mock type for the Searcher type
+type Searcher struct {
+	mock.Mock
+}
+
+// Index provides a mock function with given fields: ctx, ar
+func (_m *Searcher) Index(ctx context.Context, ar *models.Article) error {
+	ret := _m.Called(ctx, ar)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Article) error); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Remove provides a mock function with given fields: ctx, id
+func (_m *Searcher) Remove(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, q, cursor, num
+func (_m *Searcher) Search(ctx context.Context, q *models.ArticleQuery, cursor string, num int64) ([]*models.ArticleHit, string, error) {
+	ret := _m.Called(ctx, q, cursor, num)
+
+	var r0 []*models.ArticleHit
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ArticleQuery, string, int64) []*models.ArticleHit); ok {
+		r0 = rf(ctx, q, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ArticleHit)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, *models.ArticleQuery, string, int64) string); ok {
+		r1 = rf(ctx, q, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *models.ArticleQuery, string, int64) error); ok {
+		r2 = rf(ctx, q, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
@@ -3,7 +3,7 @@ package mocks
 
 import context "context"
 import mock "github.com/stretchr/testify/mock"
-import models "github.com/bxcodec/go-clean-arch/models"
+import models "github.com/models"
 
 // Usecase is an autogenerated mock type for the Usecase type
 type Usecase struct {
@@ -24,13 +24,13 @@ func (_m *Usecase) Delete(ctx context.Context, id int64) error {
 	return r0
 }
 
-// Fetch provides a mock function with given fields: ctx, cursor, num
-func (_m *Usecase) Fetch(ctx context.Context, cursor string, num int64) ([]*models.Article, string, error) {
-	ret := _m.Called(ctx, cursor, num)
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *Usecase) Fetch(ctx context.Context, filter *models.ArticleFilter, cursor string, num int64) ([]*models.Article, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
 
 	var r0 []*models.Article
-	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []*models.Article); ok {
-		r0 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ArticleFilter, string, int64) []*models.Article); ok {
+		r0 = rf(ctx, filter, cursor, num)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*models.Article)
@@ -38,15 +38,15 @@ func (_m *Usecase) Fetch(ctx context.Context, cursor string, num int64) ([]*mode
 	}
 
 	var r1 string
-	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
-		r1 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(1).(func(context.Context, *models.ArticleFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
-		r2 = rf(ctx, cursor, num)
+	if rf, ok := ret.Get(2).(func(context.Context, *models.ArticleFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -100,6 +100,64 @@ func (_m *Usecase) GetByTitle(ctx context.Context, title string) (*models.Articl
 	return r0, r1
 }
 
+// Publish provides a mock function with given fields: ctx, ar, userId
+func (_m *Usecase) Publish(ctx context.Context, ar *models.Article, userId string) error {
+	ret := _m.Called(ctx, ar, userId)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Article, string) error); ok {
+		r0 = rf(ctx, ar, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reindex provides a mock function with given fields: ctx
+func (_m *Usecase) Reindex(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, q, filter, cursor, num
+func (_m *Usecase) Search(ctx context.Context, q string, filter *models.ArticleFilter, cursor string, num int64) ([]*models.ArticleHit, string, error) {
+	ret := _m.Called(ctx, q, filter, cursor, num)
+
+	var r0 []*models.ArticleHit
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.ArticleFilter, string, int64) []*models.ArticleHit); ok {
+		r0 = rf(ctx, q, filter, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.ArticleHit)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, *models.ArticleFilter, string, int64) string); ok {
+		r1 = rf(ctx, q, filter, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, *models.ArticleFilter, string, int64) error); ok {
+		r2 = rf(ctx, q, filter, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Store provides a mock function with given fields: _a0, _a1
 func (_m *Usecase) Store(_a0 context.Context, _a1 *models.Article) error {
 	ret := _m.Called(_a0, _a1)
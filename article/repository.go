@@ -3,12 +3,14 @@ package article
 import (
 	"context"
 
-	"github.com/bxcodec/go-clean-arch/models"
+	"github.com/models"
 )
 
-// Repository represent the article's repository contract
+// Repository represent the article's repository contract.
+// FetchAll returns every article by id, it feeds the search index held in memory.
 type Repository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []*models.Article, nextCursor string, err error)
+	Fetch(ctx context.Context, filter *models.ArticleFilter, cursor string, num int64) (res []*models.Article, nextCursor string, err error)
+	FetchAll(ctx context.Context) ([]*models.Article, error)
 	GetByID(ctx context.Context, id int64) (*models.Article, error)
 	GetByTitle(ctx context.Context, title string) (*models.Article, error)
 	Update(ctx context.Context, ar *models.Article) error
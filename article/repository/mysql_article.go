@@ -4,13 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/sirupsen/logrus"
 
-	"github.com/bxcodec/go-clean-arch/article"
-	"github.com/bxcodec/go-clean-arch/models"
+	"github.com/article"
+	"github.com/models"
+	"github.com/mysqlerr"
+	"github.com/transaction"
 )
 
 const (
@@ -26,8 +30,13 @@ func NewMysqlArticleRepository(Conn *sql.DB) article.Repository {
 	return &mysqlArticleRepository{Conn}
 }
 
+// conn will return the transaction of the unit of work ctx belongs to, or the connection pool
+func (m *mysqlArticleRepository) conn(ctx context.Context) transaction.DBTX {
+	return transaction.Conn(ctx, m.Conn)
+}
+
 func (m *mysqlArticleRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*models.Article, error) {
-	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
@@ -51,6 +60,7 @@ func (m *mysqlArticleRepository) fetch(ctx context.Context, query string, args .
 			&authorID,
 			&t.UpdatedAt,
 			&t.CreatedAt,
+			&t.Version,
 		)
 
 		if err != nil {
@@ -66,16 +76,28 @@ func (m *mysqlArticleRepository) fetch(ctx context.Context, query string, args .
 	return result, nil
 }
 
-func (m *mysqlArticleRepository) Fetch(ctx context.Context, cursor string, num int64) ([]*models.Article, string, error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
-  						FROM article WHERE created_at > ? ORDER BY created_at LIMIT ? `
+func (m *mysqlArticleRepository) Fetch(ctx context.Context, filter *models.ArticleFilter, cursor string, num int64) ([]*models.Article, string, error) {
+	query := `SELECT id,title,content, author_id, updated_at, created_at, version
+  						FROM article WHERE created_at > ?`
 
 	decodedCursor, err := DecodeCursor(cursor)
 	if err != nil && cursor != "" {
 		return nil, "", models.ErrBadParamInput
 	}
+	args := []interface{}{decodedCursor}
+	if filter != nil && filter.Category != "" {
+		query += ` AND id IN (SELECT ac.article_id FROM article_category ac
+  						JOIN category c ON c.id = ac.category_id WHERE c.tag = ?)`
+		args = append(args, filter.Category)
+	}
+	if filter != nil && filter.AuthorID != 0 {
+		query += ` AND author_id = ?`
+		args = append(args, filter.AuthorID)
+	}
+	query += ` ORDER BY created_at LIMIT ? `
+	args = append(args, num)
 
-	res, err := m.fetch(ctx, query, decodedCursor, num)
+	res, err := m.fetch(ctx, query, args...)
 	if err != nil {
 		return nil, "", err
 	}
@@ -87,8 +109,14 @@ func (m *mysqlArticleRepository) Fetch(ctx context.Context, cursor string, num i
 
 	return res, nextCursor, err
 }
+func (m *mysqlArticleRepository) FetchAll(ctx context.Context) ([]*models.Article, error) {
+	query := `SELECT id,title,content, author_id, updated_at, created_at, version
+  						FROM article ORDER BY id`
+	return m.fetch(ctx, query)
+}
+
 func (m *mysqlArticleRepository) GetByID(ctx context.Context, id int64) (res *models.Article, err error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
+	query := `SELECT id,title,content, author_id, updated_at, created_at, version
   						FROM article WHERE ID = ?`
 
 	list, err := m.fetch(ctx, query, id)
@@ -106,7 +134,7 @@ func (m *mysqlArticleRepository) GetByID(ctx context.Context, id int64) (res *mo
 }
 
 func (m *mysqlArticleRepository) GetByTitle(ctx context.Context, title string) (res *models.Article, err error) {
-	query := `SELECT id,title,content, author_id, updated_at, created_at
+	query := `SELECT id,title,content, author_id, updated_at, created_at, version
   						FROM article WHERE title = ?`
 
 	list, err := m.fetch(ctx, query, title)
@@ -124,7 +152,7 @@ func (m *mysqlArticleRepository) GetByTitle(ctx context.Context, title string) (
 
 func (m *mysqlArticleRepository) Store(ctx context.Context, a *models.Article) error {
 	query := `INSERT  article SET title=? , content=? , author_id=?, updated_at=? , created_at=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -140,13 +168,14 @@ func (m *mysqlArticleRepository) Store(ctx context.Context, a *models.Article) e
 	}
 
 	a.ID = lastID
+	a.Version = 1
 	return nil
 }
 
 func (m *mysqlArticleRepository) Delete(ctx context.Context, id int64) error {
 	query := "DELETE FROM article WHERE id = ?"
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
@@ -169,16 +198,23 @@ func (m *mysqlArticleRepository) Delete(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+// Update will write the article only if it is still at ar.Version, which is then incremented.
+// A stale version is a models.ErrPreconditionFailed and a title already in use a models.ErrConflict.
 func (m *mysqlArticleRepository) Update(ctx context.Context, ar *models.Article) error {
-	query := `UPDATE article set title=?, content=?, author_id=?, updated_at=? WHERE ID = ?`
+	query := `UPDATE article set title=?, content=?, author_id=?, updated_at=?, version=version+1 WHERE ID = ? AND version = ?`
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	res, err := stmt.ExecContext(ctx, ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID)
+	res, err := stmt.ExecContext(ctx, ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID, ar.Version)
 	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlerr.DuplicateEntry {
+			return models.ErrConflict
+		}
 		return err
 	}
 	affect, err := res.RowsAffected()
@@ -186,11 +222,19 @@ func (m *mysqlArticleRepository) Update(ctx context.Context, ar *models.Article)
 		return err
 	}
 	if affect != 1 {
-		err = fmt.Errorf("Weird  Behaviour. Total Affected: %d", affect)
-
-		return err
+		// tell a missing article from one that was changed in between
+		var exists bool
+		err := m.conn(ctx).QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM article WHERE ID = ?)`, ar.ID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return models.ErrNotFound
+		}
+		return models.ErrPreconditionFailed
 	}
 
+	ar.Version++
 	return nil
 }
 
@@ -5,12 +5,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
 
-	articleRepo "github.com/bxcodec/go-clean-arch/article/repository"
-	"github.com/bxcodec/go-clean-arch/models"
+	articleRepo "github.com/article/repository"
+	"github.com/models"
 )
 
 func TestFetch(t *testing.T) {
@@ -20,6 +21,7 @@ func TestFetch(t *testing.T) {
 	}
 
 	defer func() {
+		mock.ExpectClose()
 		err = db.Close()
 		require.NoError(t, err)
 	}()
@@ -35,24 +37,62 @@ func TestFetch(t *testing.T) {
 		},
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "version"}).
 		AddRow(mockArticles[0].ID, mockArticles[0].Title, mockArticles[0].Content,
-			mockArticles[0].Author.ID, mockArticles[0].UpdatedAt, mockArticles[0].CreatedAt).
+			mockArticles[0].Author.ID, mockArticles[0].UpdatedAt, mockArticles[0].CreatedAt, 1).
 		AddRow(mockArticles[1].ID, mockArticles[1].Title, mockArticles[1].Content,
-			mockArticles[1].Author.ID, mockArticles[1].UpdatedAt, mockArticles[1].CreatedAt)
+			mockArticles[1].Author.ID, mockArticles[1].UpdatedAt, mockArticles[1].CreatedAt, 1)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE created_at > \\? ORDER BY created_at LIMIT \\?"
+	query := "SELECT id,title,content, author_id, updated_at, created_at, version FROM article WHERE created_at > \\? ORDER BY created_at LIMIT \\?"
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := articleRepo.NewMysqlArticleRepository(db)
 	cursor := articleRepo.EncodeCursor(mockArticles[1].CreatedAt)
 	num := int64(2)
-	list, nextCursor, err := a.Fetch(context.TODO(), cursor, num)
+	list, nextCursor, err := a.Fetch(context.TODO(), nil, cursor, num)
 	assert.NotEmpty(t, nextCursor)
 	assert.NoError(t, err)
 	assert.Len(t, list, 2)
 }
 
+func TestFetchByCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "version"}).
+		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now(), 1)
+	query := "SELECT id,title,content, author_id, updated_at, created_at, version FROM article WHERE created_at > \\? " +
+		"AND id IN \\(SELECT ac.article_id FROM article_category ac JOIN category c ON c.id = ac.category_id WHERE c.tag = \\?\\) " +
+		"ORDER BY created_at LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), "travel", int64(10)).WillReturnRows(rows)
+
+	a := articleRepo.NewMysqlArticleRepository(db)
+	list, nextCursor, err := a.Fetch(context.TODO(), &models.ArticleFilter{Category: "travel"}, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, nextCursor)
+	assert.Len(t, list, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "version"}).
+		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now(), 1).
+		AddRow(2, "title 2", "Content 2", 1, time.Now(), time.Now(), 3)
+	mock.ExpectQuery("SELECT id,title,content, author_id, updated_at, created_at, version FROM article ORDER BY id").WillReturnRows(rows)
+
+	a := articleRepo.NewMysqlArticleRepository(db)
+	list, err := a.FetchAll(context.TODO())
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, int64(3), list[1].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -60,14 +100,15 @@ func TestGetByID(t *testing.T) {
 	}
 
 	defer func() {
+		mock.ExpectClose()
 		err = db.Close()
 		require.NoError(t, err)
 	}()
 
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "version"}).
+		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now(), 1)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE ID = \\?"
+	query := "SELECT id,title,content, author_id, updated_at, created_at, version FROM article WHERE ID = \\?"
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := articleRepo.NewMysqlArticleRepository(db)
@@ -95,6 +136,7 @@ func TestStore(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	defer func() {
+		mock.ExpectClose()
 		err = db.Close()
 		require.NoError(t, err)
 	}()
@@ -116,13 +158,14 @@ func TestGetByTitle(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	defer func() {
+		mock.ExpectClose()
 		err = db.Close()
 		require.NoError(t, err)
 	}()
-	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at"}).
-		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "version"}).
+		AddRow(1, "title 1", "Content 1", 1, time.Now(), time.Now(), 1)
 
-	query := "SELECT id,title,content, author_id, updated_at, created_at FROM article WHERE title = \\?"
+	query := "SELECT id,title,content, author_id, updated_at, created_at, version FROM article WHERE title = \\?"
 
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	a := articleRepo.NewMysqlArticleRepository(db)
@@ -139,6 +182,7 @@ func TestDelete(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	defer func() {
+		mock.ExpectClose()
 		err = db.Close()
 		require.NoError(t, err)
 	}()
@@ -163,6 +207,7 @@ func TestUpdate(t *testing.T) {
 		Content:   "Content",
 		CreatedAt: now,
 		UpdatedAt: now,
+		Version:   3,
 		Author: models.Author{
 			ID:   1,
 			Name: "Iman Tumorang",
@@ -174,17 +219,61 @@ func TestUpdate(t *testing.T) {
 		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 	defer func() {
+		mock.ExpectClose()
 		err = db.Close()
 		require.NoError(t, err)
 	}()
 
-	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, updated_at=\\? WHERE ID = \\?"
+	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, updated_at=\\?, version=version\\+1 WHERE ID = \\? AND version = \\?"
 
 	prep := mock.ExpectPrepare(query)
-	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID).WillReturnResult(sqlmock.NewResult(12, 1))
+	prep.ExpectExec().WithArgs(ar.Title, ar.Content, ar.Author.ID, ar.UpdatedAt, ar.ID, 3).WillReturnResult(sqlmock.NewResult(12, 1))
 
 	a := articleRepo.NewMysqlArticleRepository(db)
 
 	err = a.Update(context.TODO(), ar)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(4), ar.Version)
+}
+
+func TestUpdateStale(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	query := "UPDATE article set title=\\?, content=\\?, author_id=\\?, updated_at=\\?, version=version\\+1 WHERE ID = \\? AND version = \\?"
+	exists := "SELECT EXISTS\\(SELECT 1 FROM article WHERE ID = \\?\\)"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("Judul", "Content", 0, sqlmock.AnyArg(), 12, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(exists).WithArgs(12).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("Judul", "Content", 0, sqlmock.AnyArg(), 13, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(exists).WithArgs(13).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("Taken", "Content", 0, sqlmock.AnyArg(), 12, 2).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'Taken' for key 'uq_article_title'"})
+
+	a := articleRepo.NewMysqlArticleRepository(db)
+	ar := &models.Article{ID: 12, Title: "Judul", Content: "Content", Version: 2}
+	assert.Equal(t, models.ErrPreconditionFailed, a.Update(context.TODO(), ar))
+	assert.Equal(t, int64(2), ar.Version)
+	assert.Equal(t, models.ErrNotFound, a.Update(context.TODO(), &models.Article{ID: 13, Title: "Judul", Content: "Content", Version: 2}))
+	assert.Equal(t, models.ErrConflict, a.Update(context.TODO(), &models.Article{ID: 12, Title: "Taken", Content: "Content", Version: 2}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchByAuthor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "title", "content", "author_id", "updated_at", "created_at", "version"}).
+		AddRow(1, "title 1", "Content 1", 3, time.Now(), time.Now(), 1)
+	query := "SELECT id,title,content, author_id, updated_at, created_at, version FROM article WHERE created_at > \\? " +
+		"AND author_id = \\? ORDER BY created_at LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), int64(3), int64(10)).WillReturnRows(rows)
+
+	a := articleRepo.NewMysqlArticleRepository(db)
+	list, _, err := a.Fetch(context.TODO(), &models.ArticleFilter{AuthorID: 3}, "", 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, int64(3), list[0].Author.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
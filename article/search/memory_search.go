@@ -0,0 +1,179 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/article"
+	"github.com/models"
+)
+
+const (
+	// titleBoost is how many times a word of the title counts as much as a word of the content
+	titleBoost = 2
+	// k1 and b are the usual Okapi BM25 parameters, k1 limits the weight of a repeated word
+	// and b how much a long article is penalized
+	k1 = 1.2
+	b  = 0.75
+)
+
+// document is an article as indexed, the copy of the article leaves its categories out
+type document struct {
+	article    models.Article
+	categories map[int64]bool
+	terms      map[string]int
+	length     int
+}
+
+type memorySearcher struct {
+	mu sync.RWMutex
+	// postings holds the documents each word is found in, with its weighted count in the document
+	postings    map[string]map[int64]int
+	docs        map[int64]*document
+	totalLength int
+}
+
+// NewMemorySearcher will create an article.Searcher keeping its index in memory and ranking the articles with BM25.
+// It only knows the articles handed to Index.
+func NewMemorySearcher() article.Searcher {
+	return &memorySearcher{
+		postings: make(map[string]map[int64]int),
+		docs:     make(map[int64]*document),
+	}
+}
+
+// Index will add the article or replace it, an article without categories keeps those it had
+func (m *memorySearcher) Index(ctx context.Context, ar *models.Article) error {
+	doc := &document{
+		article: *ar,
+		terms:   make(map[string]int),
+	}
+	doc.article.Categories = nil
+	for _, t := range Tokenize(ar.Title) {
+		doc.terms[t] += titleBoost
+		doc.length += titleBoost
+	}
+	for _, t := range Tokenize(ar.Content) {
+		doc.terms[t]++
+		doc.length++
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if ar.Categories != nil {
+		doc.categories = make(map[int64]bool, len(ar.Categories))
+		for _, c := range ar.Categories {
+			doc.categories[c.ID] = true
+		}
+	} else if old, ok := m.docs[ar.ID]; ok {
+		doc.categories = old.categories
+	}
+
+	m.remove(ar.ID)
+	m.docs[ar.ID] = doc
+	m.totalLength += doc.length
+	for t, n := range doc.terms {
+		if m.postings[t] == nil {
+			m.postings[t] = make(map[int64]int)
+		}
+		m.postings[t][ar.ID] = n
+	}
+	return nil
+}
+
+func (m *memorySearcher) Remove(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(id)
+	return nil
+}
+
+// remove will drop the document from the index, m.mu must be held
+func (m *memorySearcher) remove(id int64) {
+	doc, ok := m.docs[id]
+	if !ok {
+		return
+	}
+	for t := range doc.terms {
+		delete(m.postings[t], id)
+		if len(m.postings[t]) == 0 {
+			delete(m.postings, t)
+		}
+	}
+	m.totalLength -= doc.length
+	delete(m.docs, id)
+}
+
+func (m *memorySearcher) Search(ctx context.Context, q *models.ArticleQuery, cursor string, num int64) ([]*models.ArticleHit, string, error) {
+	offset, err := DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", models.ErrBadParamInput
+	}
+	terms := Terms(q.Text)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.docs) == 0 {
+		return make([]*models.ArticleHit, 0), "", nil
+	}
+	total := float64(len(m.docs))
+	avgLength := float64(m.totalLength) / total
+
+	scores := make(map[int64]float64)
+	for _, t := range terms {
+		posting := m.postings[t]
+		df := float64(len(posting))
+		idf := math.Log(1 + (total-df+0.5)/(df+0.5))
+		for id, n := range posting {
+			doc := m.docs[id]
+			if !matches(doc, q) {
+				continue
+			}
+			tf := float64(n)
+			scores[id] += idf * tf * (k1 + 1) / (tf + k1*(1-b+b*float64(doc.length)/avgLength))
+		}
+	}
+
+	ids := make([]int64, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	result := make([]*models.ArticleHit, 0)
+	for i := offset; i < int64(len(ids)) && i < offset+num; i++ {
+		doc := m.docs[ids[i]]
+		result = append(result, &models.ArticleHit{
+			Article: doc.article,
+			Score:   scores[ids[i]],
+			Snippet: Snippet(doc.article.Content, terms),
+		})
+	}
+
+	nextCursor := ""
+	if offset+num < int64(len(ids)) {
+		nextCursor = EncodeCursor(offset + num)
+	}
+	return result, nextCursor, nil
+}
+
+// matches will tell whether the document passes the filters of the search
+func matches(doc *document, q *models.ArticleQuery) bool {
+	if q.AuthorID != 0 && doc.article.Author.ID != q.AuthorID {
+		return false
+	}
+	if q.CategoryID != 0 && !doc.categories[q.CategoryID] {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package search_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/article"
+	"github.com/article/search"
+	"github.com/models"
+)
+
+func memoryIndex(t *testing.T) article.Searcher {
+	s := search.NewMemorySearcher()
+	travel := models.Category{ID: 1, Tag: "travel"}
+	food := models.Category{ID: 2, Tag: "food"}
+	for _, ar := range []*models.Article{
+		{ID: 1, Title: "Street food of Jakarta", Content: "Carts selling nasi goreng along the roads.",
+			Author: models.Author{ID: 1}, Categories: []models.Category{food}},
+		{ID: 2, Title: "A week in Bali", Content: "Beaches, temples and some food by the sea.",
+			Author: models.Author{ID: 2}, Categories: []models.Category{travel, food}},
+		{ID: 3, Title: "Trains of Java", Content: "From Jakarta to Surabaya by train.",
+			Author: models.Author{ID: 1}, Categories: []models.Category{travel}},
+		{ID: 4, Title: "Coffee", Content: "Kopi tubruk is brewed without a filter.", Author: models.Author{ID: 2}},
+	} {
+		require.NoError(t, s.Index(context.TODO(), ar))
+	}
+	return s
+}
+
+func ids(hits []*models.ArticleHit) []int64 {
+	res := make([]int64, len(hits))
+	for i, h := range hits {
+		res[i] = h.ID
+	}
+	return res
+}
+
+func TestMemorySearch(t *testing.T) {
+	s := memoryIndex(t)
+
+	// a word of the title counts more than one of the content
+	hits, nextCursor, err := s.Search(context.TODO(), &models.ArticleQuery{Text: "food"}, "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, ids(hits))
+	assert.Empty(t, nextCursor)
+	assert.True(t, hits[0].Score > hits[1].Score)
+	assert.Equal(t, "Beaches, temples and some <mark>food</mark> by the sea.", hits[1].Snippet)
+
+	// any of the words matches, the articles with more of them first
+	hits, _, err = s.Search(context.TODO(), &models.ArticleQuery{Text: "Jakarta street"}, "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, ids(hits))
+
+	hits, _, err = s.Search(context.TODO(), &models.ArticleQuery{Text: "durian"}, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+}
+
+func TestMemorySearchFilters(t *testing.T) {
+	s := memoryIndex(t)
+
+	hits, _, err := s.Search(context.TODO(), &models.ArticleQuery{Text: "food jakarta", AuthorID: 1}, "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3}, ids(hits))
+
+	hits, _, err = s.Search(context.TODO(), &models.ArticleQuery{Text: "food jakarta", CategoryID: 1}, "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 2}, ids(hits))
+}
+
+func TestMemorySearchPages(t *testing.T) {
+	s := memoryIndex(t)
+	q := &models.ArticleQuery{Text: "food jakarta kopi"}
+
+	first, nextCursor, err := s.Search(context.TODO(), q, "", 2)
+	require.NoError(t, err)
+	assert.Len(t, first, 2)
+	require.NotEmpty(t, nextCursor)
+
+	second, nextCursor, err := s.Search(context.TODO(), q, nextCursor, 2)
+	require.NoError(t, err)
+	assert.Len(t, second, 2)
+	assert.Empty(t, nextCursor)
+	assert.ElementsMatch(t, []int64{1, 2, 3, 4}, append(ids(first), ids(second)...))
+
+	_, _, err = s.Search(context.TODO(), q, "not a cursor", 2)
+	assert.Equal(t, models.ErrBadParamInput, err)
+}
+
+func TestMemoryIndexUpdate(t *testing.T) {
+	s := memoryIndex(t)
+
+	// an update without categories keeps them, the words are replaced
+	require.NoError(t, s.Index(context.TODO(), &models.Article{ID: 3, Title: "Ferries of Java", Content: "From Bali to Lombok.", Author: models.Author{ID: 1}}))
+	hits, _, err := s.Search(context.TODO(), &models.ArticleQuery{Text: "ferries", CategoryID: 1}, "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3}, ids(hits))
+	hits, _, err = s.Search(context.TODO(), &models.ArticleQuery{Text: "trains"}, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+
+	require.NoError(t, s.Remove(context.TODO(), 3))
+	hits, _, err = s.Search(context.TODO(), &models.ArticleQuery{Text: "ferries bali"}, "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, ids(hits))
+}
@@ -0,0 +1,100 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/article"
+	"github.com/models"
+)
+
+// match is the relevance of an article to the search, the FULLTEXT index ft_article_title_content covers its columns
+const match = `MATCH(title, content) AGAINST (? IN NATURAL LANGUAGE MODE)`
+
+type mysqlSearcher struct {
+	Conn *sql.DB
+}
+
+// NewMysqlSearcher will create an article.Searcher querying the FULLTEXT index of the article table
+func NewMysqlSearcher(Conn *sql.DB) article.Searcher {
+	return &mysqlSearcher{Conn}
+}
+
+func (m *mysqlSearcher) Search(ctx context.Context, q *models.ArticleQuery, cursor string, num int64) ([]*models.ArticleHit, string, error) {
+	offset, err := DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", models.ErrBadParamInput
+	}
+
+	query := `SELECT id, title, content, author_id, updated_at, created_at, version, ` + match + ` AS score
+  						FROM article WHERE ` + match
+	args := []interface{}{q.Text, q.Text}
+	if q.AuthorID != 0 {
+		query += ` AND author_id = ?`
+		args = append(args, q.AuthorID)
+	}
+	if q.CategoryID != 0 {
+		query += ` AND id IN (SELECT article_id FROM article_category WHERE category_id = ?)`
+		args = append(args, q.CategoryID)
+	}
+	// one hit more tells whether there is a next page
+	query += ` ORDER BY score DESC, id LIMIT ? OFFSET ?`
+	args = append(args, num+1, offset)
+
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, "", err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}()
+
+	terms := Terms(q.Text)
+	result := make([]*models.ArticleHit, 0)
+	for rows.Next() {
+		h := new(models.ArticleHit)
+		err = rows.Scan(
+			&h.ID,
+			&h.Title,
+			&h.Content,
+			&h.Author.ID,
+			&h.UpdatedAt,
+			&h.CreatedAt,
+			&h.Version,
+			&h.Score,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, "", err
+		}
+		h.Snippet = Snippet(h.Content, terms)
+		result = append(result, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if int64(len(result)) > num {
+		result = result[:num]
+		nextCursor = EncodeCursor(offset + num)
+	}
+	return result, nextCursor, nil
+}
+
+// Index has nothing to do, MySQL keeps the FULLTEXT index up to date
+func (m *mysqlSearcher) Index(ctx context.Context, ar *models.Article) error {
+	return nil
+}
+
+// Remove has nothing to do, MySQL keeps the FULLTEXT index up to date
+func (m *mysqlSearcher) Remove(ctx context.Context, id int64) error {
+	return nil
+}
@@ -0,0 +1,55 @@
+package search_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/article/search"
+	"github.com/models"
+)
+
+func TestMysqlSearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"id", "title", "content", "author_id", "updated_at", "created_at", "version", "score"}
+	match := "MATCH\\(title, content\\) AGAINST \\(\\? IN NATURAL LANGUAGE MODE\\)"
+	query := "SELECT id, title, content, author_id, updated_at, created_at, version, " + match + " AS score " +
+		"FROM article WHERE " + match + " AND author_id = \\? " +
+		"AND id IN \\(SELECT article_id FROM article_category WHERE category_id = \\?\\) " +
+		"ORDER BY score DESC, id LIMIT \\? OFFSET \\?"
+	mock.ExpectQuery(query).WithArgs("street food", "street food", int64(1), int64(2), int64(3), int64(0)).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "Street food of Jakarta", "Carts selling food.", 1, time.Now(), time.Now(), 2, 1.5).
+			AddRow(5, "Food", "Sate & more", 1, time.Now(), time.Now(), 1, 0.75).
+			AddRow(7, "Markets", "Fresh food", 1, time.Now(), time.Now(), 1, 0.5))
+
+	s := search.NewMysqlSearcher(db)
+	hits, nextCursor, err := s.Search(context.TODO(), &models.ArticleQuery{Text: "street food", AuthorID: 1, CategoryID: 2}, "", 2)
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+	assert.Equal(t, search.EncodeCursor(2), nextCursor)
+	assert.Equal(t, 1.5, hits[0].Score)
+	assert.Equal(t, int64(1), hits[0].Author.ID)
+	assert.Equal(t, int64(2), hits[0].Version)
+	assert.Equal(t, "Carts selling <mark>food</mark>.", hits[0].Snippet)
+	assert.Equal(t, "Sate &amp; more", hits[1].Snippet)
+
+	mock.ExpectQuery("SELECT (.+) FROM article WHERE "+match+" ORDER BY score DESC, id LIMIT \\? OFFSET \\?").
+		WithArgs("food", "food", int64(3), int64(2)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(7, "Markets", "Fresh food", 1, time.Now(), time.Now(), 1, 0.5))
+	hits, nextCursor, err = s.Search(context.TODO(), &models.ArticleQuery{Text: "food"}, nextCursor, 2)
+	require.NoError(t, err)
+	assert.Len(t, hits, 1)
+	assert.Empty(t, nextCursor)
+
+	_, _, err = s.Search(context.TODO(), &models.ArticleQuery{Text: "food"}, "not a cursor", 2)
+	assert.Equal(t, models.ErrBadParamInput, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,156 @@
+// Package search implements article.Searcher on the FULLTEXT index of MySQL and on an index held in memory.
+//
+// Both rank the articles matching any of the words searched for, the most relevant first, and cut the snippets
+// of the hits the same way. Relevance has no stable order to resume from, so a cursor carries the number of hits
+// already returned.
+package search
+
+import (
+	"encoding/base64"
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// snippetLength is the number of characters of content in a snippet at most
+	snippetLength = 200
+	// snippetLead is the number of characters kept before the first word found
+	snippetLead = 60
+
+	markOpen  = "<mark>"
+	markClose = "</mark>"
+)
+
+// span is the position of a word in a text, in runes
+type span struct {
+	start, end int
+}
+
+// words will return the position of every word of text, a word being a run of letters and digits
+func words(text []rune) []span {
+	var res []span
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			res = append(res, span{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		res = append(res, span{start, len(text)})
+	}
+	return res
+}
+
+// Tokenize will return the lowercase words of text in order
+func Tokenize(text string) []string {
+	runes := []rune(strings.ToLower(text))
+	spans := words(runes)
+	res := make([]string, len(spans))
+	for i, s := range spans {
+		res[i] = string(runes[s.start:s.end])
+	}
+	return res
+}
+
+// Terms will return the distinct words of a search
+func Terms(q string) []string {
+	seen := map[string]bool{}
+	var res []string
+	for _, t := range Tokenize(q) {
+		if !seen[t] {
+			seen[t] = true
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// Snippet will cut the part of content starting a little before the first of the terms it contains,
+// or its beginning when it contains none. The snippet is HTML escaped and the terms are wrapped in <mark>.
+func Snippet(content string, terms []string) string {
+	text := []rune(content)
+	lower := []rune(strings.ToLower(content))
+	if len(lower) != len(text) {
+		// a character changing length in lowercase, compare the original text
+		lower = text
+	}
+	wanted := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		wanted[t] = true
+	}
+
+	var found []span
+	for _, s := range words(text) {
+		if wanted[string(lower[s.start:s.end])] {
+			found = append(found, s)
+		}
+	}
+
+	begin := 0
+	if len(found) > 0 && found[0].start > snippetLead {
+		begin = found[0].start - snippetLead
+		// start at a word rather than in the middle of one
+		for begin < found[0].start && !unicode.IsSpace(text[begin-1]) {
+			begin++
+		}
+	}
+	end := begin + snippetLength
+	if end > len(text) {
+		end = len(text)
+	}
+
+	var b strings.Builder
+	if begin > 0 {
+		b.WriteString("…")
+	}
+	pos := begin
+	for _, s := range found {
+		if s.start < begin {
+			continue
+		}
+		if s.end > end {
+			break
+		}
+		b.WriteString(html.EscapeString(string(text[pos:s.start])))
+		b.WriteString(markOpen)
+		b.WriteString(html.EscapeString(string(text[s.start:s.end])))
+		b.WriteString(markClose)
+		pos = s.end
+	}
+	b.WriteString(html.EscapeString(string(text[pos:end])))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// DecodeCursor will return the number of hits returned before the cursor
+func DecodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	byt, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.ParseInt(string(byt), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if offset < 0 {
+		return 0, strconv.ErrRange
+	}
+	return offset, nil
+}
+
+// EncodeCursor will encode the number of hits returned so far
+func EncodeCursor(offset int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.FormatInt(offset, 10)))
+}
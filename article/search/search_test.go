@@ -0,0 +1,48 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/article/search"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"nasi", "goreng", "is", "rp", "15", "000", "café"}, search.Tokenize("Nasi-Goreng is Rp 15.000, Café!"))
+	assert.Empty(t, search.Tokenize(" ... "))
+	assert.Equal(t, []string{"street", "food"}, search.Terms("Street food STREET"))
+}
+
+func TestSnippet(t *testing.T) {
+	terms := search.Terms("food jakarta")
+
+	assert.Equal(t, "The best <mark>food</mark> in <mark>Jakarta</mark> &lt;3", search.Snippet("The best food in Jakarta <3", terms))
+	assert.Equal(t, "Nothing matches here", search.Snippet("Nothing matches here", terms))
+
+	long := "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore " +
+		"et dolore magna aliqua. Street food of Jakarta is sold from carts along the main roads until late at night " +
+		"and the best stalls are found near the markets, where the vendors cook every dish in front of the buyers " +
+		"who wait patiently for their turn."
+	snippet := search.Snippet(long, terms)
+	assert.Regexp(t, `^…[A-Za-z]`, snippet)
+	assert.Regexp(t, `…$`, snippet)
+	assert.Contains(t, snippet, "Street <mark>food</mark> of <mark>Jakarta</mark>")
+	assert.NotContains(t, snippet, "Lorem")
+}
+
+func TestCursor(t *testing.T) {
+	offset, err := search.DecodeCursor(search.EncodeCursor(20))
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), offset)
+
+	offset, err = search.DecodeCursor("")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), offset)
+
+	_, err = search.DecodeCursor("not a cursor")
+	assert.Error(t, err)
+	_, err = search.DecodeCursor(search.EncodeCursor(-1))
+	assert.Error(t, err)
+}
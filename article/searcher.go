@@ -0,0 +1,16 @@
+package article
+
+import (
+	"context"
+
+	"github.com/models"
+)
+
+// Searcher represent the full-text search over the articles, the most relevant articles first.
+// Index and Remove keep an index held by the service in line with the repository,
+// a search running on the database itself has nothing to do for them.
+type Searcher interface {
+	Search(ctx context.Context, q *models.ArticleQuery, cursor string, num int64) (res []*models.ArticleHit, nextCursor string, err error)
+	Index(ctx context.Context, ar *models.Article) error
+	Remove(ctx context.Context, id int64) error
+}
@@ -3,15 +3,18 @@ package article
 import (
 	"context"
 
-	"github.com/bxcodec/go-clean-arch/models"
+	"github.com/models"
 )
 
 // Usecase represent the article's usecases
 type Usecase interface {
-	Fetch(ctx context.Context, cursor string, num int64) ([]*models.Article, string, error)
+	Fetch(ctx context.Context, filter *models.ArticleFilter, cursor string, num int64) ([]*models.Article, string, error)
 	GetByID(ctx context.Context, id int64) (*models.Article, error)
 	Update(ctx context.Context, ar *models.Article) error
 	GetByTitle(ctx context.Context, title string) (*models.Article, error)
 	Store(context.Context, *models.Article) error
+	Publish(ctx context.Context, ar *models.Article, userId string) error
 	Delete(ctx context.Context, id int64) error
+	Search(ctx context.Context, q string, filter *models.ArticleFilter, cursor string, num int64) ([]*models.ArticleHit, string, error)
+	Reindex(ctx context.Context) error
 }
@@ -2,89 +2,151 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
-	"golang.org/x/sync/errgroup"
 
-	"github.com/bxcodec/go-clean-arch/article"
-	"github.com/bxcodec/go-clean-arch/author"
-	"github.com/bxcodec/go-clean-arch/models"
+	"github.com/article"
+	"github.com/author"
+	"github.com/author/loader"
+	"github.com/category"
+	"github.com/models"
+	"github.com/transaction"
 )
 
 type articleUsecase struct {
 	articleRepo    article.Repository
 	authorRepo     author.Repository
+	categoryRepo   category.Repository
+	searcher       article.Searcher
+	txManager      transaction.Manager
 	contextTimeout time.Duration
 }
 
 // NewArticleUsecase will create new an articleUsecase object representation of article.Usecase interface
-func NewArticleUsecase(a article.Repository, ar author.Repository, timeout time.Duration) article.Usecase {
+func NewArticleUsecase(a article.Repository, ar author.Repository, cr category.Repository, s article.Searcher, txManager transaction.Manager, timeout time.Duration) article.Usecase {
 	return &articleUsecase{
 		articleRepo:    a,
 		authorRepo:     ar,
+		categoryRepo:   cr,
+		searcher:       s,
+		txManager:      txManager,
 		contextTimeout: timeout,
 	}
 }
 
-/*
-* In this function below, I'm using errgroup with the pipeline pattern
-* Look how this works in this package explanation
-* in godoc: https://godoc.org/golang.org/x/sync/errgroup#ex-Group--Pipeline
- */
-func (a *articleUsecase) fillAuthorDetails(c context.Context, data []*models.Article) ([]*models.Article, error) {
-
-	g, ctx := errgroup.WithContext(c)
-
-	// Get the author's id
-	mapAuthors := map[int64]models.Author{}
+// authors will return the author loader of the request, or one for this call when the request has none
+func (a *articleUsecase) authors(ctx context.Context) *loader.Loader {
+	if l := loader.FromContext(ctx); l != nil {
+		return l
+	}
+	return loader.New(ctx, a.authorRepo, 0)
+}
 
-	for _, article := range data {
-		mapAuthors[article.Author.ID] = models.Author{}
+// fillAuthorDetails will load the distinct authors of the articles with a single query, shared with the
+// other lookups of the request
+func (a *articleUsecase) fillAuthorDetails(ctx context.Context, data []*models.Article) ([]*models.Article, error) {
+	ids := make([]int64, len(data))
+	for i, item := range data {
+		ids[i] = item.Author.ID
 	}
-	// Using goroutine to fetch the author's detail
-	chanAuthor := make(chan *models.Author)
-	for authorID := range mapAuthors {
-		authorID := authorID
-		g.Go(func() error {
-			res, err := a.authorRepo.GetByID(ctx, authorID)
-			if err != nil {
-				return err
-			}
-			chanAuthor <- res
-			return nil
-		})
+	authors, err := a.authors(ctx).LoadMany(ctx, ids)
+	if err != nil {
+		return nil, err
 	}
 
-	go func() {
-		err := g.Wait()
-		if err != nil {
-			logrus.Error(err)
-			return
+	// merge the author's data
+	for _, item := range data {
+		if res, ok := authors[item.Author.ID]; ok {
+			item.Author = *res
 		}
-		close(chanAuthor)
-	}()
+	}
+	return data, nil
+}
 
-	for author := range chanAuthor {
-		if author != nil {
-			mapAuthors[author.ID] = *author
+// fillCategories will load the categories of the articles with a single query for the whole page
+func (a *articleUsecase) fillCategories(ctx context.Context, data []*models.Article) error {
+	if len(data) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(data))
+	for i, item := range data {
+		ids[i] = item.ID
+	}
+	categories, err := a.categoryRepo.FetchByArticleIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, item := range data {
+		item.Categories = categories[item.ID]
+		if item.Categories == nil {
+			item.Categories = []models.Category{}
 		}
 	}
+	return nil
+}
 
-	if err := g.Wait(); err != nil {
+// resolveCategories will look up the categories given to an article by their tag
+func (a *articleUsecase) resolveCategories(ctx context.Context, given []models.Category) ([]models.Category, error) {
+	if len(given) == 0 {
+		return []models.Category{}, nil
+	}
+	tags := make([]string, 0, len(given))
+	seen := map[string]bool{}
+	for _, c := range given {
+		if !seen[c.Tag] {
+			seen[c.Tag] = true
+			tags = append(tags, c.Tag)
+		}
+	}
+	found, err := a.categoryRepo.GetByTags(ctx, tags)
+	if err != nil {
 		return nil, err
 	}
+	if len(found) != len(tags) {
+		return nil, category.ErrUnknownCategory
+	}
+	res := make([]models.Category, len(found))
+	for i, c := range found {
+		res[i] = *c
+	}
+	return res, nil
+}
 
-	// merge the author's data
-	for index, item := range data {
-		if a, ok := mapAuthors[item.Author.ID]; ok {
-			data[index].Author = a
+// resolveAuthor will keep the stored author of an article given none, a given author must exist
+func (a *articleUsecase) resolveAuthor(ctx context.Context, ar *models.Article) error {
+	if ar.Author.ID == 0 {
+		stored, err := a.articleRepo.GetByID(ctx, ar.ID)
+		if err != nil {
+			return err
 		}
+		ar.Author.ID = stored.Author.ID
+		return nil
 	}
-	return data, nil
+	return a.checkAuthor(ctx, ar.Author.ID)
 }
 
-func (a *articleUsecase) Fetch(c context.Context, cursor string, num int64) ([]*models.Article, string, error) {
+// checkAuthor will refuse an author that does not exist
+func (a *articleUsecase) checkAuthor(ctx context.Context, id int64) error {
+	_, err := a.authors(ctx).Load(ctx, id)
+	if errors.Is(err, models.ErrNotFound) {
+		return author.ErrUnknownAuthor
+	}
+	return err
+}
+
+// categoryIDs will return the ids of the categories
+func categoryIDs(categories []models.Category) []int64 {
+	ids := make([]int64, len(categories))
+	for i, c := range categories {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+func (a *articleUsecase) Fetch(c context.Context, filter *models.ArticleFilter, cursor string, num int64) ([]*models.Article, string, error) {
 	if num == 0 {
 		num = 10
 	}
@@ -92,7 +154,13 @@ func (a *articleUsecase) Fetch(c context.Context, cursor string, num int64) ([]*
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
-	listArticle, nextCursor, err := a.articleRepo.Fetch(ctx, cursor, num)
+	if filter != nil && filter.AuthorID != 0 {
+		if _, err := a.authors(ctx).Load(ctx, filter.AuthorID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	listArticle, nextCursor, err := a.articleRepo.Fetch(ctx, filter, cursor, num)
 	if err != nil {
 		return nil, "", err
 	}
@@ -101,6 +169,9 @@ func (a *articleUsecase) Fetch(c context.Context, cursor string, num int64) ([]*
 	if err != nil {
 		return nil, "", err
 	}
+	if err := a.fillCategories(ctx, listArticle); err != nil {
+		return nil, "", err
+	}
 
 	return listArticle, nextCursor, nil
 }
@@ -115,21 +186,60 @@ func (a *articleUsecase) GetByID(c context.Context, id int64) (*models.Article,
 		return nil, err
 	}
 
-	resAuthor, err := a.authorRepo.GetByID(ctx, res.Author.ID)
+	resAuthor, err := a.authors(ctx).Load(ctx, res.Author.ID)
 	if err != nil {
 		return nil, err
 	}
 	res.Author = *resAuthor
+	if err := a.fillCategories(ctx, []*models.Article{res}); err != nil {
+		return nil, err
+	}
 	return res, nil
 }
 
+// Update will write the article if it is still at ar.Version, the title must stay unique like in Store.
+// An article given no author keeps its author.
 func (a *articleUsecase) Update(c context.Context, ar *models.Article) error {
 
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
 
+	existedArticle, _ := a.articleRepo.GetByTitle(ctx, ar.Title)
+	if existedArticle != nil && existedArticle.ID != ar.ID {
+		return models.ErrConflict
+	}
+	if err := a.resolveAuthor(ctx, ar); err != nil {
+		return err
+	}
+
 	ar.UpdatedAt = time.Now()
-	return a.articleRepo.Update(ctx, ar)
+	if ar.Categories == nil {
+		if err := a.articleRepo.Update(ctx, ar); err != nil {
+			return err
+		}
+		a.index(ctx, ar)
+		return nil
+	}
+
+	categories, err := a.resolveCategories(ctx, ar.Categories)
+	if err != nil {
+		return err
+	}
+	err = a.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := a.articleRepo.Update(ctx, ar); err != nil {
+			return err
+		}
+		if err := a.categoryRepo.SetArticleCategories(ctx, ar.ID, categoryIDs(categories)); err != nil {
+			return err
+		}
+		ar.Categories = categories
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	a.index(ctx, ar)
+	return nil
 }
 
 func (a *articleUsecase) GetByTitle(c context.Context, title string) (*models.Article, error) {
@@ -141,11 +251,14 @@ func (a *articleUsecase) GetByTitle(c context.Context, title string) (*models.Ar
 		return nil, err
 	}
 
-	resAuthor, err := a.authorRepo.GetByID(ctx, res.Author.ID)
+	resAuthor, err := a.authors(ctx).Load(ctx, res.Author.ID)
 	if err != nil {
 		return nil, err
 	}
 	res.Author = *resAuthor
+	if err := a.fillCategories(ctx, []*models.Article{res}); err != nil {
+		return nil, err
+	}
 
 	return res, nil
 }
@@ -154,18 +267,58 @@ func (a *articleUsecase) Store(c context.Context, m *models.Article) error {
 
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
+	if err := a.checkAuthor(ctx, m.Author.ID); err != nil {
+		return err
+	}
+	return a.store(ctx, m)
+}
+
+// store will write an article of an author known to exist, the title must be unique
+func (a *articleUsecase) store(ctx context.Context, m *models.Article) error {
 	existedArticle, _ := a.GetByTitle(ctx, m.Title)
 	if existedArticle != nil {
 		return models.ErrConflict
 	}
 
-	err := a.articleRepo.Store(ctx, m)
+	categories, err := a.resolveCategories(ctx, m.Categories)
+	if err != nil {
+		return err
+	}
+	err = a.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := a.articleRepo.Store(ctx, m); err != nil {
+			return err
+		}
+		if len(categories) > 0 {
+			if err := a.categoryRepo.SetArticleCategories(ctx, m.ID, categoryIDs(categories)); err != nil {
+				return err
+			}
+		}
+		m.Categories = categories
+		return nil
+	})
 	if err != nil {
 		return err
 	}
+	a.index(ctx, m)
 	return nil
 }
 
+// Publish will store the article as written by the author linked to the users account
+func (a *articleUsecase) Publish(c context.Context, m *models.Article, userId string) error {
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	resAuthor, err := a.authorRepo.GetByUserID(ctx, userId)
+	if errors.Is(err, models.ErrNotFound) {
+		return author.ErrNotLinked
+	}
+	if err != nil {
+		return err
+	}
+	m.Author = *resAuthor
+	return a.store(ctx, m)
+}
+
 func (a *articleUsecase) Delete(c context.Context, id int64) error {
 	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
 	defer cancel()
@@ -176,5 +329,82 @@ func (a *articleUsecase) Delete(c context.Context, id int64) error {
 	if existedArticle == nil {
 		return models.ErrNotFound
 	}
-	return a.articleRepo.Delete(ctx, id)
+	if err := a.articleRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := a.searcher.Remove(ctx, id); err != nil {
+		logrus.Error(err)
+	}
+	return nil
+}
+
+// Search will return the articles matching the text, the most relevant first. The hits carry their author and
+// categories like the listing; an unknown category matches no article.
+func (a *articleUsecase) Search(c context.Context, q string, filter *models.ArticleFilter, cursor string, num int64) ([]*models.ArticleHit, string, error) {
+	if strings.TrimSpace(q) == "" {
+		return nil, "", models.ErrBadParamInput
+	}
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	query := &models.ArticleQuery{Text: q}
+	if filter != nil {
+		query.AuthorID = filter.AuthorID
+		if filter.Category != "" {
+			found, err := a.categoryRepo.GetByTags(ctx, []string{filter.Category})
+			if err != nil {
+				return nil, "", err
+			}
+			if len(found) == 0 {
+				return make([]*models.ArticleHit, 0), "", nil
+			}
+			query.CategoryID = found[0].ID
+		}
+	}
+
+	hits, nextCursor, err := a.searcher.Search(ctx, query, cursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	list := make([]*models.Article, len(hits))
+	for i, h := range hits {
+		list[i] = &h.Article
+	}
+	if _, err := a.fillAuthorDetails(ctx, list); err != nil {
+		return nil, "", err
+	}
+	if err := a.fillCategories(ctx, list); err != nil {
+		return nil, "", err
+	}
+	return hits, nextCursor, nil
+}
+
+// Reindex will hand every article to the search, an index held in memory is filled this way at startup.
+// It runs as long as ctx allows rather than within the timeout of the usecase.
+func (a *articleUsecase) Reindex(ctx context.Context) error {
+	list, err := a.articleRepo.FetchAll(ctx)
+	if err != nil {
+		return err
+	}
+	if err := a.fillCategories(ctx, list); err != nil {
+		return err
+	}
+	for _, ar := range list {
+		if err := a.searcher.Index(ctx, ar); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// index will hand the stored article to the search, a failure leaves the search behind and is only logged
+func (a *articleUsecase) index(ctx context.Context, ar *models.Article) {
+	if err := a.searcher.Index(ctx, ar); err != nil {
+		logrus.Error(err)
+	}
 }
@@ -8,13 +8,37 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
-
-	"github.com/bxcodec/go-clean-arch/article/mocks"
-	ucase "github.com/bxcodec/go-clean-arch/article/usecase"
-	_authorMock "github.com/bxcodec/go-clean-arch/author/mocks"
-	"github.com/bxcodec/go-clean-arch/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/article/mocks"
+	ucase "github.com/article/usecase"
+	"github.com/author"
+	"github.com/author/loader"
+	_authorMock "github.com/author/mocks"
+	"github.com/category"
+	_categoryMock "github.com/category/mocks"
+	"github.com/models"
+	_txMock "github.com/transaction/mocks"
 )
 
+// inTransaction will run the units of work directly
+func inTransaction() *_txMock.Manager {
+	mockTx := new(_txMock.Manager)
+	mockTx.On("Do", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+	return mockTx
+}
+
+// indexed will accept every article handed to the search
+func indexed() *mocks.Searcher {
+	mockSearcher := new(mocks.Searcher)
+	mockSearcher.On("Index", mock.Anything, mock.Anything).Return(nil)
+	mockSearcher.On("Remove", mock.Anything, mock.Anything).Return(nil)
+	return mockSearcher
+}
+
 func TestFetch(t *testing.T) {
 	mockArticleRepo := new(mocks.Repository)
 	mockArticle := &models.Article{
@@ -26,18 +50,21 @@ func TestFetch(t *testing.T) {
 	mockListArtilce = append(mockListArtilce, mockArticle)
 
 	t.Run("success", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
+		mockArticleRepo.On("Fetch", mock.Anything, mock.Anything, mock.AnythingOfType("string"),
 			mock.AnythingOfType("int64")).Return(mockListArtilce, "next-cursor", nil).Once()
 		mockAuthor := &models.Author{
 			ID:   1,
 			Name: "Iman Tumorang",
 		}
 		mockAuthorrepo := new(_authorMock.Repository)
-		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		mockCategoryRepo.On("FetchByArticleIDs", mock.Anything, mock.Anything).Return(map[int64][]models.Category{}, nil).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{mockArticle.Author.ID}).
+			Return(map[int64]*models.Author{mockArticle.Author.ID: mockAuthor}, nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
 		num := int64(1)
 		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		list, nextCursor, err := u.Fetch(context.TODO(), nil, cursor, num)
 		cursorExpected := "next-cursor"
 		assert.Equal(t, cursorExpected, nextCursor)
 		assert.NotEmpty(t, nextCursor)
@@ -49,14 +76,15 @@ func TestFetch(t *testing.T) {
 	})
 
 	t.Run("error-failed", func(t *testing.T) {
-		mockArticleRepo.On("Fetch", mock.Anything, mock.AnythingOfType("string"),
+		mockArticleRepo.On("Fetch", mock.Anything, mock.Anything, mock.AnythingOfType("string"),
 			mock.AnythingOfType("int64")).Return(nil, "", errors.New("Unexpexted Error")).Once()
 
 		mockAuthorrepo := new(_authorMock.Repository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
 		num := int64(1)
 		cursor := "12"
-		list, nextCursor, err := u.Fetch(context.TODO(), cursor, num)
+		list, nextCursor, err := u.Fetch(context.TODO(), nil, cursor, num)
 
 		assert.Empty(t, nextCursor)
 		assert.Error(t, err)
@@ -81,8 +109,11 @@ func TestGetByID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(&mockArticle, nil).Once()
 		mockAuthorrepo := new(_authorMock.Repository)
-		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		mockCategoryRepo.On("FetchByArticleIDs", mock.Anything, mock.Anything).Return(map[int64][]models.Category{}, nil).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{mockArticle.Author.ID}).
+			Return(map[int64]*models.Author{mockArticle.Author.ID: mockAuthor}, nil).Once()
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
 
 		a, err := u.GetByID(context.TODO(), mockArticle.ID)
 
@@ -96,7 +127,8 @@ func TestGetByID(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(nil, errors.New("Unexpected")).Once()
 
 		mockAuthorrepo := new(_authorMock.Repository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
 
 		a, err := u.GetByID(context.TODO(), mockArticle.ID)
 
@@ -114,22 +146,39 @@ func TestStore(t *testing.T) {
 	mockArticle := models.Article{
 		Title:   "Hello",
 		Content: "Content",
+		Author:  models.Author{ID: 1},
 	}
 
 	t.Run("success", func(t *testing.T) {
 		tempMockArticle := mockArticle
 		tempMockArticle.ID = 0
+		mockAuthorrepo := new(_authorMock.Repository)
 		mockArticleRepo.On("GetByTitle", mock.Anything, mock.AnythingOfType("string")).Return(nil, models.ErrNotFound).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]*models.Author{1: {ID: 1}}, nil).Once()
 		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.Article")).Return(nil).Once()
 
-		mockAuthorrepo := new(_authorMock.Repository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
 
 		err := u.Store(context.TODO(), &tempMockArticle)
 
 		assert.NoError(t, err)
 		assert.Equal(t, mockArticle.Title, tempMockArticle.Title)
 		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+	t.Run("unknown-author", func(t *testing.T) {
+		mockAuthorrepo := new(_authorMock.Repository)
+		ar := models.Article{Title: "Hello", Content: "Content", Author: models.Author{ID: 9}}
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{9}).Return(map[int64]*models.Author{}, nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, new(_categoryMock.Repository), indexed(), inTransaction(), time.Second*2)
+
+		err := u.Store(context.TODO(), &ar)
+		assert.Equal(t, author.ErrUnknownAuthor, err)
+		assert.True(t, errors.Is(err, models.ErrBadParamInput))
+		mockArticleRepo.AssertNotCalled(t, "Store", mock.Anything, &ar)
+		mockAuthorrepo.AssertExpectations(t)
 	})
 	t.Run("existing-title", func(t *testing.T) {
 		existingArticle := mockArticle
@@ -139,9 +188,12 @@ func TestStore(t *testing.T) {
 			Name: "Iman Tumorang",
 		}
 		mockAuthorrepo := new(_authorMock.Repository)
-		mockAuthorrepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(mockAuthor, nil)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		mockCategoryRepo.On("FetchByArticleIDs", mock.Anything, mock.Anything).Return(map[int64][]models.Category{}, nil).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{mockArticle.Author.ID}).
+			Return(map[int64]*models.Author{mockArticle.Author.ID: mockAuthor}, nil).Twice()
 
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
 
 		err := u.Store(context.TODO(), &mockArticle)
 
@@ -165,7 +217,8 @@ func TestDelete(t *testing.T) {
 		mockArticleRepo.On("Delete", mock.Anything, mock.AnythingOfType("int64")).Return(nil).Once()
 
 		mockAuthorrepo := new(_authorMock.Repository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
 
 		err := u.Delete(context.TODO(), mockArticle.ID)
 
@@ -177,7 +230,8 @@ func TestDelete(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(nil, nil).Once()
 
 		mockAuthorrepo := new(_authorMock.Repository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
 
 		err := u.Delete(context.TODO(), mockArticle.ID)
 
@@ -189,7 +243,8 @@ func TestDelete(t *testing.T) {
 		mockArticleRepo.On("GetByID", mock.Anything, mock.AnythingOfType("int64")).Return(nil, errors.New("Unexpected Error")).Once()
 
 		mockAuthorrepo := new(_authorMock.Repository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
 
 		err := u.Delete(context.TODO(), mockArticle.ID)
 
@@ -206,16 +261,339 @@ func TestUpdate(t *testing.T) {
 		Title:   "Hello",
 		Content: "Content",
 		ID:      23,
+		Author:  models.Author{ID: 1},
 	}
 
 	t.Run("success", func(t *testing.T) {
+		mockAuthorrepo := new(_authorMock.Repository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(&models.Article{ID: 23, Title: "Hello"}, nil).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]*models.Author{1: {ID: 1}}, nil).Once()
 		mockArticleRepo.On("Update", mock.Anything, &mockArticle).Once().Return(nil)
 
-		mockAuthorrepo := new(_authorMock.Repository)
-		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, time.Second*2)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
 
 		err := u.Update(context.TODO(), &mockArticle)
 		assert.NoError(t, err)
 		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+	t.Run("keeps-author", func(t *testing.T) {
+		ar := models.Article{ID: 23, Title: "Hello", Content: "Content"}
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(nil, models.ErrNotFound).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(&models.Article{ID: 23, Author: models.Author{ID: 3}}, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, mock.MatchedBy(func(ar *models.Article) bool {
+			return ar.Author.ID == 3
+		})).Return(nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(_authorMock.Repository), new(_categoryMock.Repository), indexed(), inTransaction(), time.Second*2)
+
+		require.NoError(t, u.Update(context.TODO(), &ar))
+		assert.Equal(t, int64(3), ar.Author.ID)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("unknown-author", func(t *testing.T) {
+		mockAuthorrepo := new(_authorMock.Repository)
+		ar := models.Article{ID: 23, Title: "Hello", Content: "Content", Author: models.Author{ID: 9}}
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(nil, models.ErrNotFound).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{9}).Return(map[int64]*models.Author{}, nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, new(_categoryMock.Repository), indexed(), inTransaction(), time.Second*2)
+
+		err := u.Update(context.TODO(), &ar)
+		assert.Equal(t, author.ErrUnknownAuthor, err)
+		assert.True(t, errors.Is(err, models.ErrBadParamInput))
+		mockArticleRepo.AssertNotCalled(t, "Update", mock.Anything, &ar)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+	t.Run("existing-title", func(t *testing.T) {
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(&models.Article{ID: 7, Title: "Hello"}, nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(_authorMock.Repository), new(_categoryMock.Repository), indexed(), inTransaction(), time.Second*2)
+
+		err := u.Update(context.TODO(), &mockArticle)
+		assert.Equal(t, models.ErrConflict, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+	t.Run("stale", func(t *testing.T) {
+		mockAuthorrepo := new(_authorMock.Repository)
+		stale := models.Article{ID: 23, Title: "Renamed", Content: "Content", Version: 2, Author: models.Author{ID: 1}}
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Renamed").Return(nil, models.ErrNotFound).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]*models.Author{1: {ID: 1}}, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, &stale).Return(models.ErrPreconditionFailed).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, new(_categoryMock.Repository), indexed(), inTransaction(), time.Second*2)
+
+		err := u.Update(context.TODO(), &stale)
+		assert.Equal(t, models.ErrPreconditionFailed, err)
+		mockArticleRepo.AssertExpectations(t)
+	})
+}
+
+func TestCategories(t *testing.T) {
+	travel := &models.Category{ID: 1, Name: "Travel", Tag: "travel"}
+	food := &models.Category{ID: 2, Name: "Food", Tag: "food"}
+
+	t.Run("fetch", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockAuthorrepo := new(_authorMock.Repository)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		filter := &models.ArticleFilter{Category: "travel"}
+		mockArticleRepo.On("Fetch", mock.Anything, filter, "", int64(10)).
+			Return([]*models.Article{{ID: 1, Author: models.Author{ID: 1}}, {ID: 2, Author: models.Author{ID: 1}}}, "", nil).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]*models.Author{1: {ID: 1}}, nil).Once()
+		mockCategoryRepo.On("FetchByArticleIDs", mock.Anything, []int64{1, 2}).
+			Return(map[int64][]models.Category{1: {*travel, *food}}, nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
+		list, _, err := u.Fetch(context.TODO(), filter, "", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []models.Category{*travel, *food}, list[0].Categories)
+		assert.Equal(t, []models.Category{}, list[1].Categories)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("store", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		mockAuthorrepo := new(_authorMock.Repository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(nil, models.ErrNotFound).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]*models.Author{1: {ID: 1}}, nil).Once()
+		mockCategoryRepo.On("GetByTags", mock.Anything, []string{"travel", "food"}).Return([]*models.Category{food, travel}, nil).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.Article")).
+			Run(func(args mock.Arguments) { args.Get(1).(*models.Article).ID = 5 }).Return(nil).Once()
+		mockCategoryRepo.On("SetArticleCategories", mock.Anything, int64(5), []int64{2, 1}).Return(nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
+		ar := &models.Article{Title: "Hello", Content: "Content", Author: models.Author{ID: 1}, Categories: []models.Category{{Tag: "travel"}, {Tag: "food"}, {Tag: "travel"}}}
+		require.NoError(t, u.Store(context.TODO(), ar))
+		assert.Equal(t, []models.Category{*food, *travel}, ar.Categories)
+		mockArticleRepo.AssertExpectations(t)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("store-unknown-category", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		mockAuthorrepo := new(_authorMock.Repository)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(nil, models.ErrNotFound).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]*models.Author{1: {ID: 1}}, nil).Once()
+		mockCategoryRepo.On("GetByTags", mock.Anything, []string{"travel", "nope"}).Return([]*models.Category{travel}, nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
+		err := u.Store(context.TODO(), &models.Article{Title: "Hello", Author: models.Author{ID: 1}, Categories: []models.Category{{Tag: "travel"}, {Tag: "nope"}}})
+		assert.Equal(t, category.ErrUnknownCategory, err)
+		assert.True(t, errors.Is(err, models.ErrBadParamInput))
+		mockArticleRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("update-clears", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		ar := &models.Article{ID: 23, Title: "Hello", Categories: []models.Category{}}
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(nil, models.ErrNotFound).Once()
+		mockArticleRepo.On("GetByID", mock.Anything, int64(23)).Return(&models.Article{ID: 23, Author: models.Author{ID: 1}}, nil).Once()
+		mockArticleRepo.On("Update", mock.Anything, ar).Return(nil).Once()
+		mockCategoryRepo.On("SetArticleCategories", mock.Anything, int64(23), []int64{}).Return(nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(_authorMock.Repository), mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
+		require.NoError(t, u.Update(context.TODO(), ar))
+		mockArticleRepo.AssertExpectations(t)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+}
+
+func TestPublish(t *testing.T) {
+	t.Run("linked", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockAuthorrepo := new(_authorMock.Repository)
+		linked := &models.Author{ID: 3, Name: "Iman Tumorang", UserId: "u1"}
+		mockAuthorrepo.On("GetByUserID", mock.Anything, "u1").Return(linked, nil).Once()
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(nil, models.ErrNotFound).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.Article")).Return(nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, new(_categoryMock.Repository), indexed(), inTransaction(), time.Second*2)
+		ar := &models.Article{Title: "Hello", Content: "Content", Author: models.Author{ID: 9}}
+		require.NoError(t, u.Publish(context.TODO(), ar, "u1"))
+		assert.Equal(t, int64(3), ar.Author.ID)
+		mockArticleRepo.AssertExpectations(t)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+
+	t.Run("not-linked", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockAuthorrepo := new(_authorMock.Repository)
+		mockAuthorrepo.On("GetByUserID", mock.Anything, "u2").Return(nil, models.ErrNotFound).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, new(_categoryMock.Repository), indexed(), inTransaction(), time.Second*2)
+		err := u.Publish(context.TODO(), &models.Article{Title: "Hello", Content: "Content"}, "u2")
+		assert.Equal(t, author.ErrNotLinked, err)
+		assert.True(t, errors.Is(err, models.ErrForbidden))
+		mockArticleRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchByAuthor(t *testing.T) {
+	mockArticleRepo := new(mocks.Repository)
+	mockAuthorrepo := new(_authorMock.Repository)
+	mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{7}).Return(map[int64]*models.Author{}, nil).Once()
+
+	u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, new(_categoryMock.Repository), indexed(), inTransaction(), time.Second*2)
+	_, _, err := u.Fetch(context.TODO(), &models.ArticleFilter{AuthorID: 7}, "", 10)
+	assert.Equal(t, models.ErrNotFound, err)
+	mockArticleRepo.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockAuthorrepo.AssertExpectations(t)
+}
+
+func TestFillAuthorDetails(t *testing.T) {
+	page := func() []*models.Article {
+		return []*models.Article{{ID: 1, Author: models.Author{ID: 1}}, {ID: 2, Author: models.Author{ID: 2}}, {ID: 3, Author: models.Author{ID: 1}}}
+	}
+
+	t.Run("single-query", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockAuthorrepo := new(_authorMock.Repository)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		mockArticleRepo.On("Fetch", mock.Anything, mock.Anything, "", int64(10)).Return(page(), "", nil).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1, 2}).
+			Return(map[int64]*models.Author{1: {ID: 1, Name: "Iman"}, 2: {ID: 2, Name: "Rinaldy"}}, nil).Once()
+		mockCategoryRepo.On("FetchByArticleIDs", mock.Anything, mock.Anything).Return(map[int64][]models.Category{}, nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
+		list, _, err := u.Fetch(context.TODO(), nil, "", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "Iman", list[0].Author.Name)
+		assert.Equal(t, "Rinaldy", list[1].Author.Name)
+		assert.Equal(t, "Iman", list[2].Author.Name)
+		mockAuthorrepo.AssertExpectations(t)
+	})
+
+	t.Run("request-loader", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockAuthorrepo := new(_authorMock.Repository)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		filter := &models.ArticleFilter{AuthorID: 1}
+		mockArticleRepo.On("Fetch", mock.Anything, filter, "", int64(10)).
+			Return([]*models.Article{{ID: 1, Author: models.Author{ID: 1}}}, "", nil).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]*models.Author{1: {ID: 1, Name: "Iman"}}, nil).Once()
+		mockCategoryRepo.On("FetchByArticleIDs", mock.Anything, mock.Anything).Return(map[int64][]models.Category{}, nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, mockCategoryRepo, indexed(), inTransaction(), time.Second*2)
+		ctx := loader.NewContext(context.TODO(), loader.New(context.TODO(), mockAuthorrepo, 0))
+		list, _, err := u.Fetch(ctx, filter, "", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "Iman", list[0].Author.Name)
+		mockAuthorrepo.AssertNumberOfCalls(t, "GetByIDs", 1)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockAuthorrepo := new(_authorMock.Repository)
+		mockArticleRepo.On("Fetch", mock.Anything, mock.Anything, "", int64(10)).Return(page(), "", nil).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1, 2}).Return(nil, errors.New("Unexpected Error")).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, new(_categoryMock.Repository), indexed(), inTransaction(), time.Second*2)
+		_, _, err := u.Fetch(context.TODO(), nil, "", 0)
+		assert.Error(t, err)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	travel := &models.Category{ID: 1, Name: "Travel", Tag: "travel"}
+
+	t.Run("success", func(t *testing.T) {
+		mockAuthorrepo := new(_authorMock.Repository)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		mockSearcher := new(mocks.Searcher)
+		mockCategoryRepo.On("GetByTags", mock.Anything, []string{"travel"}).Return([]*models.Category{travel}, nil).Once()
+		mockSearcher.On("Search", mock.Anything, &models.ArticleQuery{Text: "bali", AuthorID: 2, CategoryID: 1}, "", int64(10)).
+			Return([]*models.ArticleHit{{Article: models.Article{ID: 4, Author: models.Author{ID: 2}}, Score: 1.2}}, "next", nil).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{2}).Return(map[int64]*models.Author{2: {ID: 2, Name: "Iman"}}, nil).Once()
+		mockCategoryRepo.On("FetchByArticleIDs", mock.Anything, []int64{4}).Return(map[int64][]models.Category{4: {*travel}}, nil).Once()
+
+		u := ucase.NewArticleUsecase(new(mocks.Repository), mockAuthorrepo, mockCategoryRepo, mockSearcher, inTransaction(), time.Second*2)
+		hits, nextCursor, err := u.Search(context.TODO(), "bali", &models.ArticleFilter{Category: "travel", AuthorID: 2}, "", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "next", nextCursor)
+		require.Len(t, hits, 1)
+		assert.Equal(t, "Iman", hits[0].Author.Name)
+		assert.Equal(t, []models.Category{*travel}, hits[0].Categories)
+		mockSearcher.AssertExpectations(t)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-category", func(t *testing.T) {
+		mockCategoryRepo := new(_categoryMock.Repository)
+		mockSearcher := new(mocks.Searcher)
+		mockCategoryRepo.On("GetByTags", mock.Anything, []string{"nope"}).Return([]*models.Category{}, nil).Once()
+
+		u := ucase.NewArticleUsecase(new(mocks.Repository), new(_authorMock.Repository), mockCategoryRepo, mockSearcher, inTransaction(), time.Second*2)
+		hits, _, err := u.Search(context.TODO(), "bali", &models.ArticleFilter{Category: "nope"}, "", 10)
+		require.NoError(t, err)
+		assert.Empty(t, hits)
+		mockSearcher.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("empty-query", func(t *testing.T) {
+		u := ucase.NewArticleUsecase(new(mocks.Repository), new(_authorMock.Repository), new(_categoryMock.Repository), new(mocks.Searcher), inTransaction(), time.Second*2)
+		_, _, err := u.Search(context.TODO(), "  ", nil, "", 10)
+		assert.Equal(t, models.ErrBadParamInput, err)
+	})
+}
+
+func TestIndex(t *testing.T) {
+	t.Run("store", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockAuthorrepo := new(_authorMock.Repository)
+		mockSearcher := new(mocks.Searcher)
+		ar := &models.Article{Title: "Hello", Content: "Content", Author: models.Author{ID: 1}}
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(nil, models.ErrNotFound).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]*models.Author{1: {ID: 1}}, nil).Once()
+		mockArticleRepo.On("Store", mock.Anything, ar).Return(nil).Once()
+		mockSearcher.On("Index", mock.Anything, ar).Return(nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, new(_categoryMock.Repository), mockSearcher, inTransaction(), time.Second*2)
+		require.NoError(t, u.Store(context.TODO(), ar))
+		mockSearcher.AssertExpectations(t)
+	})
+
+	t.Run("failed-store", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockAuthorrepo := new(_authorMock.Repository)
+		mockSearcher := new(mocks.Searcher)
+		mockArticleRepo.On("GetByTitle", mock.Anything, "Hello").Return(nil, models.ErrNotFound).Once()
+		mockAuthorrepo.On("GetByIDs", mock.Anything, []int64{1}).Return(map[int64]*models.Author{1: {ID: 1}}, nil).Once()
+		mockArticleRepo.On("Store", mock.Anything, mock.Anything).Return(errors.New("Unexpected Error")).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, mockAuthorrepo, new(_categoryMock.Repository), mockSearcher, inTransaction(), time.Second*2)
+		assert.Error(t, u.Store(context.TODO(), &models.Article{Title: "Hello", Content: "Content", Author: models.Author{ID: 1}}))
+		mockSearcher.AssertNotCalled(t, "Index", mock.Anything, mock.Anything)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockSearcher := new(mocks.Searcher)
+		mockArticleRepo.On("GetByID", mock.Anything, int64(3)).Return(&models.Article{ID: 3}, nil).Once()
+		mockArticleRepo.On("Delete", mock.Anything, int64(3)).Return(nil).Once()
+		mockSearcher.On("Remove", mock.Anything, int64(3)).Return(nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(_authorMock.Repository), new(_categoryMock.Repository), mockSearcher, inTransaction(), time.Second*2)
+		require.NoError(t, u.Delete(context.TODO(), 3))
+		mockSearcher.AssertExpectations(t)
+	})
+
+	t.Run("reindex", func(t *testing.T) {
+		mockArticleRepo := new(mocks.Repository)
+		mockCategoryRepo := new(_categoryMock.Repository)
+		mockSearcher := new(mocks.Searcher)
+		travel := models.Category{ID: 1, Tag: "travel"}
+		mockArticleRepo.On("FetchAll", mock.Anything).Return([]*models.Article{{ID: 1}, {ID: 2}}, nil).Once()
+		mockCategoryRepo.On("FetchByArticleIDs", mock.Anything, []int64{1, 2}).Return(map[int64][]models.Category{2: {travel}}, nil).Once()
+		mockSearcher.On("Index", mock.Anything, &models.Article{ID: 1, Categories: []models.Category{}}).Return(nil).Once()
+		mockSearcher.On("Index", mock.Anything, &models.Article{ID: 2, Categories: []models.Category{travel}}).Return(nil).Once()
+
+		u := ucase.NewArticleUsecase(mockArticleRepo, new(_authorMock.Repository), mockCategoryRepo, mockSearcher, inTransaction(), time.Second*2)
+		require.NoError(t, u.Reindex(context.TODO()))
+		mockSearcher.AssertExpectations(t)
 	})
 }
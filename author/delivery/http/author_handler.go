@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/author"
+	"github.com/middleware"
+	"github.com/models"
+	"github.com/validation"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// AuthorHandler  represent the httphandler for author
+type AuthorHandler struct {
+	AUsecase author.Usecase
+}
+
+// NewAuthorHandler will initialize the authors/ resources endpoint
+func NewAuthorHandler(e *echo.Echo, us author.Usecase, mw *middleware.GoMiddleware) {
+	handler := &AuthorHandler{
+		AUsecase: us,
+	}
+	adminOnly := mw.Authorize(middleware.Policy{Roles: []string{models.RoleAdmin}})
+	e.GET("/authors", handler.FetchAuthor)
+	e.POST("/authors", handler.Store, mw.Authenticate, adminOnly)
+	e.GET("/authors/:id", handler.GetByID)
+	e.PUT("/authors/:id", handler.Update, mw.Authenticate, adminOnly)
+	e.DELETE("/authors/:id", handler.Delete, mw.Authenticate, adminOnly)
+}
+
+// FetchAuthor will list the authors by id, a page of num authors after the cursor
+func (a *AuthorHandler) FetchAuthor(c echo.Context) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	list, nextCursor, err := a.AUsecase.Fetch(ctx, cursor, int64(num))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, list)
+}
+
+// GetByID will get author by given id
+func (a *AuthorHandler) GetByID(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: models.ErrNotFound.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := a.AUsecase.GetByID(ctx, id)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// Store will create the author sent as a JSON or form request body
+func (a *AuthorHandler) Store(c echo.Context) error {
+	body, err := validation.Decode(c.Request())
+	if err != nil {
+		return c.JSON(validation.StatusCode(err), err)
+	}
+	var command models.AuthorAdminDto
+	if err := body.Bind(&command); err != nil {
+		return c.JSON(validation.StatusCode(err), err)
+	}
+	au := models.Author{Name: command.Name, UserId: command.UserId}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := a.AUsecase.Store(ctx, &au); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusCreated, toAdminDto(&au))
+}
+
+// Update will change the name and the linked user of the author by given id
+func (a *AuthorHandler) Update(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: models.ErrNotFound.Error()})
+	}
+	body, err := validation.Decode(c.Request())
+	if err != nil {
+		return c.JSON(validation.StatusCode(err), err)
+	}
+	var command models.AuthorAdminDto
+	if err := body.Bind(&command); err != nil {
+		return c.JSON(validation.StatusCode(err), err)
+	}
+	au := models.Author{ID: id, Name: command.Name, UserId: command.UserId}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := a.AUsecase.Update(ctx, &au); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, toAdminDto(&au))
+}
+
+// Delete will delete author by given param, an author who still has articles is answered with 409
+func (a *AuthorHandler) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: models.ErrNotFound.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := a.AUsecase.Delete(ctx, id); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// toAdminDto will show the author with the users account linked to it, for the admin endpoints only
+func toAdminDto(a *models.Author) *models.AuthorAdminDto {
+	return &models.AuthorAdminDto{
+		ID:        a.ID,
+		Name:      a.Name,
+		UserId:    a.UserId,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+	}
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	logrus.Error(err)
+	switch {
+	case errors.Is(err, models.ErrInternalServerError):
+		return http.StatusInternalServerError
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrUnAuthorize):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrBadParamInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
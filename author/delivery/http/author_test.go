@@ -0,0 +1,96 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/author"
+	authorHttp "github.com/author/delivery/http"
+	"github.com/author/mocks"
+	"github.com/middleware/middlewaretest"
+	"github.com/models"
+)
+
+func newServer(mockUCase *mocks.Usecase) *echo.Echo {
+	e := echo.New()
+	authorHttp.NewAuthorHandler(e, mockUCase, middlewaretest.New())
+	return e
+}
+
+func serve(e *echo.Echo, method string, target string, body string, token string) *httptest.ResponseRecorder {
+	return middlewaretest.Serve(e, middlewaretest.NewRequest(method, target, body), token)
+}
+
+func TestFetch(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Fetch", mock.Anything, "cursor-1", int64(2)).Return([]*models.Author{{ID: 3, UserId: "u1"}, {ID: 4}}, "cursor-2", nil).Once()
+
+	rec := serve(e, echo.GET, "/authors?num=2&cursor=cursor-1", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "cursor-2", rec.Header().Get("X-Cursor"))
+	var list []models.Author
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Len(t, list, 2)
+	assert.NotContains(t, rec.Body.String(), "user_id")
+	mockUCase.AssertExpectations(t)
+}
+
+func TestStore(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Store", mock.Anything, &models.Author{Name: "Iman", UserId: "u1"}).Return(nil).Once()
+	mockUCase.On("Store", mock.Anything, &models.Author{Name: "Iman", UserId: "u2"}).Return(author.ErrUserLinked).Once()
+
+	rec := serve(e, echo.POST, "/authors", `{"name": "Iman", "user_id": "u1"}`, middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var created models.AuthorAdminDto
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "u1", created.UserId)
+
+	rec = serve(e, echo.POST, "/authors", `{"name": "Iman", "user_id": "u2"}`, middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = serve(e, echo.POST, "/authors", `{"name": "Iman"}`, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(e, echo.POST, "/authors", `{"user_id": "u1"}`, middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestUpdate(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Update", mock.Anything, &models.Author{ID: 1, Name: "Iman Tumorang"}).Return(nil).Once()
+	mockUCase.On("Update", mock.Anything, &models.Author{ID: 2, Name: "Iman", UserId: "u9"}).Return(author.ErrUnknownUser).Once()
+
+	rec := serve(e, echo.PUT, "/authors/1", `{"name": "Iman Tumorang"}`, middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(e, echo.PUT, "/authors/2", `{"name": "Iman", "user_id": "u9"}`, middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDelete(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+	mockUCase.On("Delete", mock.Anything, int64(2)).Return(author.ErrHasArticles).Once()
+
+	rec := serve(e, echo.DELETE, "/authors/1", "", middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = serve(e, echo.DELETE, "/authors/2", "", middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "still has articles")
+	mockUCase.AssertExpectations(t)
+}
@@ -0,0 +1,18 @@
+package author
+
+import (
+	"github.com/models"
+)
+
+var (
+	// ErrHasArticles will throw if an author who still has articles is deleted
+	ErrHasArticles = models.NewConflict("The author still has articles")
+	// ErrUserLinked will throw if the users account is already linked to another author
+	ErrUserLinked = models.NewConflict("The user is already linked to another author")
+	// ErrUnknownUser will throw if an author is linked to a users account that does not exist
+	ErrUnknownUser = models.NewBadParam("The author is linked to an unknown user")
+	// ErrUnknownAuthor will throw if an article is given an author that does not exist
+	ErrUnknownAuthor = models.NewBadParam("The article is given an unknown author")
+	// ErrNotLinked will throw if a user publishes an article without being linked to an author
+	ErrNotLinked = models.NewForbidden("The user is not linked to an author")
+)
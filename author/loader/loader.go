@@ -0,0 +1,198 @@
+// Package loader coalesces the author lookups of a request into batched author.Repository.GetByIDs queries.
+//
+// A Loader collects the ids asked for by LoadMany during a short wait, or until a batch is full, and reads them
+// with a single query; the callers asking in the meantime share the result. Load reads right away, taking along
+// the ids collected so far. Every author is read at most once
+// per Loader, so a Loader lives as long as the request it is stored in with NewContext.
+package loader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/author"
+	"github.com/models"
+)
+
+const (
+	// DefaultWait is how long a batch collects ids before it is read
+	DefaultWait = time.Millisecond
+	// DefaultMaxBatch is the number of ids read by a batch at most
+	DefaultMaxBatch = 100
+)
+
+type loaderKey struct{}
+
+// Loader represent the author lookups of a request
+type Loader struct {
+	ctx      context.Context
+	repo     author.Repository
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[int64]*result
+	batch *batch
+}
+
+// result is the outcome of reading one author, done is closed once it is known
+type result struct {
+	done   chan struct{}
+	author *models.Author
+	err    error
+}
+
+// batch holds the ids to read with one query
+type batch struct {
+	ids     []int64
+	results []*result
+}
+
+// New will create a Loader reading the authors from repo with the context ctx, the context of the request for the
+// Loader of a request, so a caller giving up does not fail the other callers of its batch.
+// A wait of zero reads the ids of each call right away, so only the calls overlapping a query share it.
+func New(ctx context.Context, repo author.Repository, wait time.Duration) *Loader {
+	return &Loader{
+		ctx:      ctx,
+		repo:     repo,
+		wait:     wait,
+		maxBatch: DefaultMaxBatch,
+		cache:    make(map[int64]*result),
+	}
+}
+
+// NewContext will return a copy of ctx carrying l
+func NewContext(ctx context.Context, l *Loader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, l)
+}
+
+// FromContext will return the Loader carried by ctx, or nil
+func FromContext(ctx context.Context) *Loader {
+	l, _ := ctx.Value(loaderKey{}).(*Loader)
+	return l
+}
+
+// Load will return the author by given id, models.ErrNotFound when there is none.
+// It does not wait for other ids, a single author is asked for when a single article is shown.
+func (l *Loader) Load(ctx context.Context, id int64) (*models.Author, error) {
+	res, err := l.load(ctx, []int64{id}, true)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := res[id]
+	if !ok {
+		return nil, models.ErrNotFound
+	}
+	return a, nil
+}
+
+// LoadMany will return the authors by given ids, an id without an author is left out of the result
+func (l *Loader) LoadMany(ctx context.Context, ids []int64) (map[int64]*models.Author, error) {
+	return l.load(ctx, ids, false)
+}
+
+// load will wait for the authors by given ids, the open batch is read right away when now is set
+func (l *Loader) load(ctx context.Context, ids []int64, now bool) (map[int64]*models.Author, error) {
+	pending := make(map[int64]*result, len(ids))
+	var ready []*batch
+
+	l.mu.Lock()
+	for _, id := range ids {
+		if _, ok := pending[id]; ok {
+			continue
+		}
+		r, ok := l.cache[id]
+		if !ok {
+			r = &result{done: make(chan struct{})}
+			l.cache[id] = r
+			if b := l.enqueue(id, r); b != nil {
+				ready = append(ready, b)
+			}
+		}
+		pending[id] = r
+	}
+	if (now || l.wait <= 0) && l.batch != nil {
+		ready = append(ready, l.batch)
+		l.batch = nil
+	}
+	l.mu.Unlock()
+
+	for _, b := range ready {
+		l.run(b)
+	}
+
+	res := make(map[int64]*models.Author, len(pending))
+	for id, r := range pending {
+		select {
+		case <-r.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		if r.author != nil {
+			res[id] = r.author
+		}
+	}
+	return res, nil
+}
+
+// enqueue will add the id to the open batch, opening one when there is none.
+// It returns the batch once it is full, the caller has to run it. l.mu must be held.
+func (l *Loader) enqueue(id int64, r *result) *batch {
+	if l.batch == nil {
+		b := &batch{}
+		l.batch = b
+		if l.wait > 0 {
+			time.AfterFunc(l.wait, func() {
+				if l.take(b) {
+					l.run(b)
+				}
+			})
+		}
+	}
+
+	b := l.batch
+	b.ids = append(b.ids, id)
+	b.results = append(b.results, r)
+	if len(b.ids) < l.maxBatch {
+		return nil
+	}
+	l.batch = nil
+	return b
+}
+
+// take will close the batch when it is still the open one, telling whether the caller has to run it
+func (l *Loader) take(b *batch) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.batch != b {
+		return false
+	}
+	l.batch = nil
+	return true
+}
+
+// run will read the authors of the batch. A failed read is not kept, the next call asks for the ids again.
+func (l *Loader) run(b *batch) {
+	authors, err := l.repo.GetByIDs(l.ctx, b.ids)
+	if err != nil {
+		l.mu.Lock()
+		for _, id := range b.ids {
+			delete(l.cache, id)
+		}
+		l.mu.Unlock()
+	}
+
+	for i, id := range b.ids {
+		r := b.results[i]
+		if err != nil {
+			r.err = err
+		} else {
+			r.author = authors[id]
+		}
+		close(r.done)
+	}
+}
@@ -0,0 +1,229 @@
+package loader_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/author"
+	"github.com/author/loader"
+	"github.com/author/mocks"
+	"github.com/author/repository"
+	"github.com/models"
+)
+
+// byIDs will answer GetByIDs with an author for every id but the missing ones
+func byIDs(missing ...int64) func(context.Context, []int64) map[int64]*models.Author {
+	return func(_ context.Context, ids []int64) map[int64]*models.Author {
+		res := make(map[int64]*models.Author, len(ids))
+		for _, id := range ids {
+			res[id] = &models.Author{ID: id, Name: fmt.Sprintf("author %d", id)}
+		}
+		for _, id := range missing {
+			delete(res, id)
+		}
+		return res
+	}
+}
+
+func TestLoadMany(t *testing.T) {
+	mockAuthorRepo := new(mocks.Repository)
+	mockAuthorRepo.On("GetByIDs", mock.Anything, []int64{1, 2, 3}).Return(byIDs(2), nil).Once()
+
+	l := loader.New(context.TODO(), mockAuthorRepo, 0)
+	res, err := l.LoadMany(context.TODO(), []int64{1, 2, 1, 3})
+	require.NoError(t, err)
+	assert.Len(t, res, 2)
+	assert.Equal(t, "author 3", res[3].Name)
+
+	// the authors are read once, an id without an author included
+	a, err := l.Load(context.TODO(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), a.ID)
+	_, err = l.Load(context.TODO(), 2)
+	assert.Equal(t, models.ErrNotFound, err)
+	mockAuthorRepo.AssertExpectations(t)
+}
+
+func TestCoalesce(t *testing.T) {
+	mockAuthorRepo := new(mocks.Repository)
+	var asked []int64
+	mockAuthorRepo.On("GetByIDs", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			asked = append(asked, args.Get(1).([]int64)...)
+		}).
+		Return(byIDs(), nil).Once()
+
+	l := loader.New(context.TODO(), mockAuthorRepo, 20*time.Millisecond)
+	var wg sync.WaitGroup
+	for _, ids := range [][]int64{{1, 2}, {2, 3}, {4}} {
+		wg.Add(1)
+		go func(ids []int64) {
+			defer wg.Done()
+			res, err := l.LoadMany(context.TODO(), ids)
+			assert.NoError(t, err)
+			assert.Len(t, res, len(ids))
+		}(ids)
+	}
+	wg.Wait()
+
+	sort.Slice(asked, func(i, j int) bool { return asked[i] < asked[j] })
+	assert.Equal(t, []int64{1, 2, 3, 4}, asked)
+	mockAuthorRepo.AssertExpectations(t)
+}
+
+func TestLoadRightAway(t *testing.T) {
+	mockAuthorRepo := new(mocks.Repository)
+	mockAuthorRepo.On("GetByIDs", mock.Anything, []int64{1}).Return(byIDs(), nil).Once()
+
+	l := loader.New(context.TODO(), mockAuthorRepo, time.Hour)
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+	a, err := l.Load(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), a.ID)
+	mockAuthorRepo.AssertExpectations(t)
+}
+
+func TestCallerGivesUp(t *testing.T) {
+	mockAuthorRepo := new(mocks.Repository)
+	mockAuthorRepo.On("GetByIDs", mock.Anything, []int64{1, 2}).
+		Return(func(ctx context.Context, ids []int64) map[int64]*models.Author {
+			return byIDs()(ctx, ids)
+		}, func(ctx context.Context, _ []int64) error {
+			return ctx.Err()
+		}).Once()
+
+	l := loader.New(context.TODO(), mockAuthorRepo, 20*time.Millisecond)
+	first, cancel := context.WithCancel(context.TODO())
+	cancel()
+	_, err := l.LoadMany(first, []int64{1})
+	assert.Equal(t, context.Canceled, err)
+
+	// the batch opened by the first caller still reads the author of the second one
+	res, err := l.LoadMany(context.TODO(), []int64{2})
+	require.NoError(t, err)
+	assert.Len(t, res, 1)
+	mockAuthorRepo.AssertExpectations(t)
+}
+
+func TestLoadError(t *testing.T) {
+	mockAuthorRepo := new(mocks.Repository)
+	mockAuthorRepo.On("GetByIDs", mock.Anything, []int64{1}).Return(nil, errors.New("Unexpected Error")).Once()
+	mockAuthorRepo.On("GetByIDs", mock.Anything, []int64{1}).Return(byIDs(), nil).Once()
+
+	l := loader.New(context.TODO(), mockAuthorRepo, 0)
+	_, err := l.Load(context.TODO(), 1)
+	assert.Error(t, err)
+
+	// a failed read is asked again
+	a, err := l.Load(context.TODO(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), a.ID)
+	mockAuthorRepo.AssertExpectations(t)
+}
+
+func TestContext(t *testing.T) {
+	assert.Nil(t, loader.FromContext(context.TODO()))
+
+	l := loader.New(context.TODO(), new(mocks.Repository), 0)
+	assert.Equal(t, l, loader.FromContext(loader.NewContext(context.TODO(), l)))
+}
+
+// fanOut will read the authors with one goroutine and one query per author,
+// the way the articles were given their authors before the loader
+func fanOut(c context.Context, repo author.Repository, ids []int64) (map[int64]*models.Author, error) {
+	g, ctx := errgroup.WithContext(c)
+	var mu sync.Mutex
+	res := make(map[int64]*models.Author, len(ids))
+	for _, id := range ids {
+		id := id
+		g.Go(func() error {
+			a, err := repo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			res[id] = a
+			mu.Unlock()
+			return nil
+		})
+	}
+	return res, g.Wait()
+}
+
+var authorColumns = []string{"id", "name", "user_id", "created_at", "updated_at"}
+
+func authorIDs(n int) []int64 {
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	return ids
+}
+
+// benchmarkAuthors will time read over a fresh stub database per run, set up by expect to answer the ids
+func benchmarkAuthors(b *testing.B, expect func(mock sqlmock.Sqlmock, ids []int64),
+	read func(repo author.Repository, ids []int64) (map[int64]*models.Author, error)) {
+	for _, n := range []int{1, 10, 50} {
+		b.Run(fmt.Sprintf("authors=%d", n), func(b *testing.B) {
+			ids := authorIDs(n)
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				db, mock, err := sqlmock.New()
+				require.NoError(b, err)
+				expect(mock, ids)
+				repo := repository.NewMysqlAuthorRepository(db)
+				b.StartTimer()
+
+				res, err := read(repo, ids)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(res) != n {
+					b.Fatalf("read %d authors, want %d", len(res), n)
+				}
+
+				b.StopTimer()
+				db.Close()
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+func BenchmarkFanOut(b *testing.B) {
+	now := time.Now()
+	benchmarkAuthors(b, func(mock sqlmock.Sqlmock, ids []int64) {
+		mock.MatchExpectationsInOrder(false)
+		for _, id := range ids {
+			mock.ExpectPrepare("SELECT (.+) FROM author WHERE id=\\?").ExpectQuery().WithArgs(id).
+				WillReturnRows(sqlmock.NewRows(authorColumns).AddRow(id, "Iman Tumorang", nil, now, now))
+		}
+	}, func(repo author.Repository, ids []int64) (map[int64]*models.Author, error) {
+		return fanOut(context.TODO(), repo, ids)
+	})
+}
+
+func BenchmarkLoader(b *testing.B) {
+	now := time.Now()
+	benchmarkAuthors(b, func(mock sqlmock.Sqlmock, ids []int64) {
+		rows := sqlmock.NewRows(authorColumns)
+		for _, id := range ids {
+			rows.AddRow(id, "Iman Tumorang", nil, now, now)
+		}
+		mock.ExpectQuery("SELECT (.+) FROM author WHERE id IN").WillReturnRows(rows)
+	}, func(repo author.Repository, ids []int64) (map[int64]*models.Author, error) {
+		return loader.New(context.TODO(), repo, 0).LoadMany(context.TODO(), ids)
+	})
+}
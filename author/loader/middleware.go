@@ -0,0 +1,18 @@
+package loader
+
+import (
+	"github.com/labstack/echo"
+
+	"github.com/author"
+)
+
+// Middleware will store a Loader reading the authors from repo in the context of every request
+func Middleware(repo author.Repository) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			c.SetRequest(req.WithContext(NewContext(req.Context(), New(req.Context(), repo, DefaultWait))))
+			return next(c)
+		}
+	}
+}
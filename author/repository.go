@@ -3,10 +3,18 @@ package author
 import (
 	"context"
 
-	"github.com/bxcodec/go-clean-arch/models"
+	"github.com/models"
 )
 
-// Repository represent the author's repository contract
+// Repository represent the author's repository contract.
+// GetByIDs reads the authors of the ids with a single query, an id without an author is left out of the result.
+// Delete refuses to remove an author who still has articles.
 type Repository interface {
+	Fetch(ctx context.Context, cursor string, num int64) (res []*models.Author, nextCursor string, err error)
 	GetByID(ctx context.Context, id int64) (*models.Author, error)
+	GetByIDs(ctx context.Context, ids []int64) (map[int64]*models.Author, error)
+	GetByUserID(ctx context.Context, userId string) (*models.Author, error)
+	Store(ctx context.Context, a *models.Author) error
+	Update(ctx context.Context, a *models.Author) error
+	Delete(ctx context.Context, id int64) error
 }
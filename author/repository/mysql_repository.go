@@ -3,13 +3,24 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/sirupsen/logrus"
 
-	"github.com/bxcodec/go-clean-arch/author"
-	"github.com/bxcodec/go-clean-arch/models"
+	"github.com/author"
+	"github.com/models"
+	"github.com/mysqlerr"
+	"github.com/transaction"
 )
 
+// authorColumns lists the columns in the order they are scanned by scan
+const authorColumns = `id, name, user_id, created_at, updated_at`
+
 type mysqlAuthorRepo struct {
 	DB *sql.DB
 }
@@ -21,22 +32,46 @@ func NewMysqlAuthorRepository(db *sql.DB) author.Repository {
 	}
 }
 
+// conn will return the transaction of the unit of work ctx belongs to, or the connection pool
+func (m *mysqlAuthorRepo) conn(ctx context.Context) transaction.DBTX {
+	return transaction.Conn(ctx, m.DB)
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scan(row scanner) (*models.Author, error) {
+	a := &models.Author{}
+	var userId sql.NullString
+	err := row.Scan(
+		&a.ID,
+		&a.Name,
+		&userId,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	a.UserId = userId.String
+	return a, nil
+}
+
 func (m *mysqlAuthorRepo) getOne(ctx context.Context, query string, args ...interface{}) (*models.Author, error) {
 
-	stmt, err := m.DB.PrepareContext(ctx, query)
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
 	}
 	row := stmt.QueryRowContext(ctx, args...)
-	a := &models.Author{}
 
-	err = row.Scan(
-		&a.ID,
-		&a.Name,
-		&a.CreatedAt,
-		&a.UpdatedAt,
-	)
+	a, err := scan(row)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
@@ -45,7 +80,194 @@ func (m *mysqlAuthorRepo) getOne(ctx context.Context, query string, args ...inte
 	return a, nil
 }
 
+func (m *mysqlAuthorRepo) fetch(ctx context.Context, query string, args ...interface{}) ([]*models.Author, error) {
+	rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}()
+
+	result := make([]*models.Author, 0)
+	for rows.Next() {
+		a, err := scan(rows)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, a)
+	}
+	return result, rows.Err()
+}
+
+func (m *mysqlAuthorRepo) Fetch(ctx context.Context, cursor string, num int64) ([]*models.Author, string, error) {
+	after := int64(0)
+	if cursor != "" {
+		decodedCursor, err := DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", models.ErrBadParamInput
+		}
+		after = decodedCursor
+	}
+
+	res, err := m.fetch(ctx, `SELECT `+authorColumns+` FROM author WHERE id > ? ORDER BY id LIMIT ?`, after, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(res) == int(num) {
+		nextCursor = EncodeCursor(res[len(res)-1].ID)
+	}
+	return res, nextCursor, nil
+}
+
 func (m *mysqlAuthorRepo) GetByID(ctx context.Context, id int64) (*models.Author, error) {
-	query := `SELECT id, name, created_at, updated_at FROM author WHERE id=?`
+	query := `SELECT ` + authorColumns + ` FROM author WHERE id=?`
 	return m.getOne(ctx, query, id)
 }
+
+func (m *mysqlAuthorRepo) GetByIDs(ctx context.Context, ids []int64) (map[int64]*models.Author, error) {
+	result := make(map[int64]*models.Author, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	list, err := m.fetch(ctx, `SELECT `+authorColumns+` FROM author WHERE id IN (`+placeholders(len(ids))+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range list {
+		result[a.ID] = a
+	}
+	return result, nil
+}
+
+func (m *mysqlAuthorRepo) GetByUserID(ctx context.Context, userId string) (*models.Author, error) {
+	query := `SELECT ` + authorColumns + ` FROM author WHERE user_id=?`
+	return m.getOne(ctx, query, userId)
+}
+
+func (m *mysqlAuthorRepo) Store(ctx context.Context, a *models.Author) error {
+	query := `INSERT author SET name=? , user_id=? , created_at=? , updated_at=?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.Name, nullable(a.UserId), now, now)
+	if err != nil {
+		return linkError(err)
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.ID = lastID
+	a.CreatedAt = now
+	a.UpdatedAt = now
+	return nil
+}
+
+func (m *mysqlAuthorRepo) Update(ctx context.Context, a *models.Author) error {
+	query := `UPDATE author SET name=?, user_id=?, updated_at=? WHERE id = ?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	a.UpdatedAt = time.Now()
+	res, err := stmt.ExecContext(ctx, a.Name, nullable(a.UserId), a.UpdatedAt, a.ID)
+	if err != nil {
+		return linkError(err)
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
+// Delete will remove the author unless one of the articles is still written by the author, checked in the same statement
+func (m *mysqlAuthorRepo) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM author WHERE id = ? AND NOT EXISTS (SELECT 1 FROM article WHERE author_id = ?)`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, id, id)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect == 1 {
+		return nil
+	}
+
+	// tell a missing author from one that still has articles
+	if _, err := m.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return author.ErrHasArticles
+}
+
+// linkError will report the violations of the user_id constraints as errors of the author package
+func linkError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case mysqlerr.DuplicateEntry:
+			return author.ErrUserLinked
+		case mysqlerr.NoReferencedRow:
+			return author.ErrUnknownUser
+		}
+	}
+	return err
+}
+
+// nullable will store an empty user id as NULL, the unique key allows any number of them
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// placeholders will return n comma separated query placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// DecodeCursor will decode the cursor of the author listing, the id of the last author of a page
+func DecodeCursor(cursor string) (int64, error) {
+	byt, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(byt), 10, 64)
+}
+
+// EncodeCursor will encode the id of the last author of a page
+func EncodeCursor(id int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
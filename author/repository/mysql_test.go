@@ -5,11 +5,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
 
-	"github.com/bxcodec/go-clean-arch/author/repository"
+	"github.com/author"
+	"github.com/author/repository"
+	"github.com/models"
 )
 
 func TestGetByID(t *testing.T) {
@@ -19,14 +22,15 @@ func TestGetByID(t *testing.T) {
 	}
 
 	defer func() {
+		mock.ExpectClose()
 		err := db.Close()
 		require.NoError(t, err)
 	}()
 
-	rows := sqlmock.NewRows([]string{"id", "name", "updated_at", "created_at"}).
-		AddRow(1, "Iman Tumorang", time.Now(), time.Now())
+	rows := sqlmock.NewRows([]string{"id", "name", "user_id", "created_at", "updated_at"}).
+		AddRow(1, "Iman Tumorang", nil, time.Now(), time.Now())
 
-	query := "SELECT id, name, created_at, updated_at FROM author WHERE id=\\?"
+	query := "SELECT id, name, user_id, created_at, updated_at FROM author WHERE id=\\?"
 
 	prep := mock.ExpectPrepare(query)
 	userID := int64(1)
@@ -38,3 +42,135 @@ func TestGetByID(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, anArticle)
 }
+
+func TestGetByIDNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	defer func() {
+		mock.ExpectClose()
+		err := db.Close()
+		require.NoError(t, err)
+	}()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "user_id", "created_at", "updated_at"})
+
+	query := "SELECT id, name, user_id, created_at, updated_at FROM author WHERE id=\\?"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectQuery().WithArgs(int64(7)).WillReturnRows(rows)
+
+	a := repository.NewMysqlAuthorRepository(db)
+
+	_, err = a.GetByID(context.TODO(), int64(7))
+	assert.Equal(t, models.ErrNotFound, err)
+}
+
+func TestFetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "user_id", "created_at", "updated_at"}).
+		AddRow(3, "Iman Tumorang", "u1", time.Now(), time.Now()).
+		AddRow(4, "Rinaldy", nil, time.Now(), time.Now())
+	query := "SELECT id, name, user_id, created_at, updated_at FROM author WHERE id > \\? ORDER BY id LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(2, 2).WillReturnRows(rows)
+
+	a := repository.NewMysqlAuthorRepository(db)
+	list, nextCursor, err := a.Fetch(context.TODO(), repository.EncodeCursor(2), 2)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "u1", list[0].UserId)
+	assert.Equal(t, "", list[1].UserId)
+	assert.Equal(t, repository.EncodeCursor(4), nextCursor)
+
+	_, _, err = a.Fetch(context.TODO(), "not a cursor", 2)
+	assert.Equal(t, models.ErrBadParamInput, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "name", "user_id", "created_at", "updated_at"}).
+		AddRow(1, "Iman Tumorang", nil, time.Now(), time.Now()).
+		AddRow(3, "Rinaldy", "u1", time.Now(), time.Now())
+	query := "SELECT id, name, user_id, created_at, updated_at FROM author WHERE id IN \\(\\?,\\?,\\?\\)"
+	mock.ExpectQuery(query).WithArgs(1, 2, 3).WillReturnRows(rows)
+
+	a := repository.NewMysqlAuthorRepository(db)
+	authors, err := a.GetByIDs(context.TODO(), []int64{1, 2, 3})
+	require.NoError(t, err)
+	assert.Len(t, authors, 2)
+	assert.Equal(t, "Rinaldy", authors[3].Name)
+	assert.NotContains(t, authors, int64(2))
+
+	authors, err = a.GetByIDs(context.TODO(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, authors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	query := "SELECT id, name, user_id, created_at, updated_at FROM author WHERE user_id=\\?"
+	mock.ExpectPrepare(query).ExpectQuery().WithArgs("u9").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "user_id", "created_at", "updated_at"}))
+
+	a := repository.NewMysqlAuthorRepository(db)
+	_, err = a.GetByUserID(context.TODO(), "u9")
+	assert.Equal(t, models.ErrNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	query := "INSERT author SET name=\\? , user_id=\\? , created_at=\\? , updated_at=\\?"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("Iman", nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("Iman", "u1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'u1' for key 'uq_author_user_id'"})
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("Iman", "u9", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"})
+
+	a := repository.NewMysqlAuthorRepository(db)
+	au := &models.Author{Name: "Iman"}
+	require.NoError(t, a.Store(context.TODO(), au))
+	assert.Equal(t, int64(7), au.ID)
+	assert.Equal(t, author.ErrUserLinked, a.Store(context.TODO(), &models.Author{Name: "Iman", UserId: "u1"}))
+	assert.Equal(t, author.ErrUnknownUser, a.Store(context.TODO(), &models.Author{Name: "Iman", UserId: "u9"}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	query := "DELETE FROM author WHERE id = \\? AND NOT EXISTS \\(SELECT 1 FROM article WHERE author_id = \\?\\)"
+	get := "SELECT id, name, user_id, created_at, updated_at FROM author WHERE id=\\?"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(1, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(2, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectPrepare(get).ExpectQuery().WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "user_id", "created_at", "updated_at"}).AddRow(2, "Iman", nil, time.Now(), time.Now()))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(3, 3).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectPrepare(get).ExpectQuery().WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "user_id", "created_at", "updated_at"}))
+
+	a := repository.NewMysqlAuthorRepository(db)
+	assert.NoError(t, a.Delete(context.TODO(), 1))
+	assert.Equal(t, author.ErrHasArticles, a.Delete(context.TODO(), 2))
+	assert.Equal(t, models.ErrNotFound, a.Delete(context.TODO(), 3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,16 @@
+package author
+
+import (
+	"context"
+
+	"github.com/models"
+)
+
+// Usecase represent the author's usecases
+type Usecase interface {
+	Fetch(ctx context.Context, cursor string, num int64) ([]*models.Author, string, error)
+	GetByID(ctx context.Context, id int64) (*models.Author, error)
+	Store(ctx context.Context, a *models.Author) error
+	Update(ctx context.Context, a *models.Author) error
+	Delete(ctx context.Context, id int64) error
+}
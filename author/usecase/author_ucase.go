@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/author"
+	"github.com/models"
+	"github.com/user"
+)
+
+type authorUsecase struct {
+	authorRepo     author.Repository
+	userRepo       user.Repository
+	contextTimeout time.Duration
+}
+
+// NewAuthorUsecase will create new an authorUsecase object representation of author.Usecase interface
+func NewAuthorUsecase(a author.Repository, ur user.Repository, timeout time.Duration) author.Usecase {
+	return &authorUsecase{
+		authorRepo:     a,
+		userRepo:       ur,
+		contextTimeout: timeout,
+	}
+}
+
+// checkUser will make sure the users account linked to the author is an active user
+func (u *authorUsecase) checkUser(ctx context.Context, a *models.Author) error {
+	if a.UserId == "" {
+		return nil
+	}
+	_, err := u.userRepo.GetByID(ctx, a.UserId)
+	if errors.Is(err, models.ErrNotFound) {
+		return author.ErrUnknownUser
+	}
+	return err
+}
+
+func (u *authorUsecase) Fetch(c context.Context, cursor string, num int64) ([]*models.Author, string, error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.authorRepo.Fetch(ctx, cursor, num)
+}
+
+func (u *authorUsecase) GetByID(c context.Context, id int64) (*models.Author, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.authorRepo.GetByID(ctx, id)
+}
+
+// Store will create the author, linked to a users account when a.UserId is set
+func (u *authorUsecase) Store(c context.Context, a *models.Author) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	if err := u.checkUser(ctx, a); err != nil {
+		return err
+	}
+	return u.authorRepo.Store(ctx, a)
+}
+
+// Update will rename the author and change the users account linked to it, an empty a.UserId unlinks it
+func (u *authorUsecase) Update(c context.Context, a *models.Author) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	existed, err := u.authorRepo.GetByID(ctx, a.ID)
+	if err != nil {
+		return err
+	}
+	if err := u.checkUser(ctx, a); err != nil {
+		return err
+	}
+	if err := u.authorRepo.Update(ctx, a); err != nil {
+		return err
+	}
+	a.CreatedAt = existed.CreatedAt
+	return nil
+}
+
+// Delete will remove the author, an author who still has articles is kept with author.ErrHasArticles
+func (u *authorUsecase) Delete(c context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.authorRepo.Delete(ctx, id)
+}
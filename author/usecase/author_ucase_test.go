@@ -0,0 +1,80 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/author"
+	"github.com/author/mocks"
+	ucase "github.com/author/usecase"
+	"github.com/models"
+	_userMock "github.com/user/mocks"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("linked", func(t *testing.T) {
+		mockAuthorRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		au := &models.Author{Name: "Iman", UserId: "u1"}
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(&models.User{Id: "u1"}, nil).Once()
+		mockAuthorRepo.On("Store", mock.Anything, au).Return(nil).Once()
+
+		u := ucase.NewAuthorUsecase(mockAuthorRepo, mockUserRepo, time.Second*2)
+		require.NoError(t, u.Store(context.TODO(), au))
+		mockAuthorRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-user", func(t *testing.T) {
+		mockAuthorRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByID", mock.Anything, "u9").Return(nil, models.ErrNotFound).Once()
+
+		u := ucase.NewAuthorUsecase(mockAuthorRepo, mockUserRepo, time.Second*2)
+		err := u.Store(context.TODO(), &models.Author{Name: "Iman", UserId: "u9"})
+		assert.Equal(t, author.ErrUnknownUser, err)
+		mockAuthorRepo.AssertNotCalled(t, "Store", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unlinked", func(t *testing.T) {
+		mockAuthorRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		mockAuthorRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.Author")).Return(nil).Once()
+
+		u := ucase.NewAuthorUsecase(mockAuthorRepo, mockUserRepo, time.Second*2)
+		require.NoError(t, u.Store(context.TODO(), &models.Author{Name: "Iman"}))
+		mockUserRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockAuthorRepo := new(mocks.Repository)
+	au := &models.Author{ID: 1, Name: "Iman Tumorang"}
+	mockAuthorRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Author{ID: 1, Name: "Iman", UserId: "u1", CreatedAt: created}, nil).Once()
+	mockAuthorRepo.On("Update", mock.Anything, au).Return(nil).Once()
+	mockAuthorRepo.On("GetByID", mock.Anything, int64(2)).Return(nil, models.ErrNotFound).Once()
+
+	u := ucase.NewAuthorUsecase(mockAuthorRepo, new(_userMock.Repository), time.Second*2)
+	require.NoError(t, u.Update(context.TODO(), au))
+	assert.Equal(t, created, au.CreatedAt)
+	assert.Equal(t, models.ErrNotFound, u.Update(context.TODO(), &models.Author{ID: 2, Name: "Iman"}))
+	mockAuthorRepo.AssertExpectations(t)
+}
+
+func TestFetch(t *testing.T) {
+	mockAuthorRepo := new(mocks.Repository)
+	mockAuthorRepo.On("Fetch", mock.Anything, "", int64(10)).Return([]*models.Author{{ID: 1}}, "next", nil).Once()
+
+	u := ucase.NewAuthorUsecase(mockAuthorRepo, new(_userMock.Repository), time.Second*2)
+	list, next, err := u.Fetch(context.TODO(), "", 0)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "next", next)
+	mockAuthorRepo.AssertExpectations(t)
+}
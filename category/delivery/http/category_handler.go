@@ -0,0 +1,159 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/category"
+	"github.com/middleware"
+	"github.com/models"
+	"github.com/validation"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// CategoryHandler  represent the httphandler for category
+type CategoryHandler struct {
+	CUsecase category.Usecase
+}
+
+// NewCategoryHandler will initialize the categories/ resources endpoint
+func NewCategoryHandler(e *echo.Echo, us category.Usecase, mw *middleware.GoMiddleware) {
+	handler := &CategoryHandler{
+		CUsecase: us,
+	}
+	adminOnly := mw.Authorize(middleware.Policy{Roles: []string{models.RoleAdmin}})
+	e.GET("/categories", handler.FetchCategory)
+	e.POST("/categories", handler.Store, mw.Authenticate, adminOnly)
+	e.GET("/categories/:id", handler.GetByID)
+	e.PUT("/categories/:id", handler.Update, mw.Authenticate, adminOnly)
+	e.DELETE("/categories/:id", handler.Delete, mw.Authenticate, adminOnly)
+}
+
+// FetchCategory will list every category by name
+func (a *CategoryHandler) FetchCategory(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	list, err := a.CUsecase.Fetch(ctx)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, list)
+}
+
+// GetByID will get category by given id
+func (a *CategoryHandler) GetByID(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: models.ErrNotFound.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := a.CUsecase.GetByID(ctx, id)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// Store will create the category sent as a JSON or form request body
+func (a *CategoryHandler) Store(c echo.Context) error {
+	body, err := validation.Decode(c.Request())
+	if err != nil {
+		return c.JSON(validation.StatusCode(err), err)
+	}
+	var cat models.Category
+	if err := body.Bind(&cat); err != nil {
+		return c.JSON(validation.StatusCode(err), err)
+	}
+	cat.ID = 0
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := a.CUsecase.Store(ctx, &cat); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusCreated, cat)
+}
+
+// Update will change the name and tag of the category by given id
+func (a *CategoryHandler) Update(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: models.ErrNotFound.Error()})
+	}
+	body, err := validation.Decode(c.Request())
+	if err != nil {
+		return c.JSON(validation.StatusCode(err), err)
+	}
+	var cat models.Category
+	if err := body.Bind(&cat); err != nil {
+		return c.JSON(validation.StatusCode(err), err)
+	}
+	cat.ID = id
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := a.CUsecase.Update(ctx, &cat); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, cat)
+}
+
+// Delete will delete category by given param
+func (a *CategoryHandler) Delete(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: models.ErrNotFound.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := a.CUsecase.Delete(ctx, id); err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	logrus.Error(err)
+	switch {
+	case errors.Is(err, models.ErrInternalServerError):
+		return http.StatusInternalServerError
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrUnAuthorize):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrBadParamInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,90 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	categoryHttp "github.com/category/delivery/http"
+	"github.com/category/mocks"
+	"github.com/middleware/middlewaretest"
+	"github.com/models"
+	"github.com/validation"
+)
+
+func newServer(mockUCase *mocks.Usecase) *echo.Echo {
+	e := echo.New()
+	categoryHttp.NewCategoryHandler(e, mockUCase, middlewaretest.New())
+	return e
+}
+
+func serve(e *echo.Echo, method string, target string, body string, token string) *httptest.ResponseRecorder {
+	return middlewaretest.Serve(e, middlewaretest.NewRequest(method, target, body), token)
+}
+
+func TestFetch(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Fetch", mock.Anything).Return([]*models.Category{{ID: 1, Name: "Travel", Tag: "travel"}}, nil).Once()
+
+	rec := serve(e, echo.GET, "/categories", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var list []models.Category
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Equal(t, "travel", list[0].Tag)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestStore(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Store", mock.Anything, &models.Category{Name: "Street Food", Tag: "street-food"}).Return(nil).Once()
+
+	body := `{"name": "Street Food", "tag": "street-food"}`
+	rec := serve(e, echo.POST, "/categories", body, middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = serve(e, echo.POST, "/categories", body, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(e, echo.POST, "/categories", `{"name": "Street Food", "tag": "Street Food"}`, middlewaretest.AdminToken)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	var res validation.Error
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, "tag", res.Errors[0].Field)
+	assert.Equal(t, "slug", res.Errors[0].Rule)
+	mockUCase.AssertNumberOfCalls(t, "Store", 1)
+}
+
+func TestUpdate(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Update", mock.Anything, &models.Category{ID: 1, Name: "Trips", Tag: "trips"}).Return(nil).Once()
+	mockUCase.On("Update", mock.Anything, &models.Category{ID: 2, Name: "Trips", Tag: "trips"}).Return(models.ErrConflict).Once()
+
+	rec := serve(e, echo.PUT, "/categories/1", `{"name": "Trips", "tag": "trips"}`, middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(e, echo.PUT, "/categories/2", `{"name": "Trips", "tag": "trips"}`, middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDelete(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Delete", mock.Anything, int64(1)).Return(nil).Once()
+
+	rec := serve(e, echo.DELETE, "/categories/1", "", middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = serve(e, echo.DELETE, "/categories/abc", "", middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
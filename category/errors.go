@@ -0,0 +1,8 @@
+package category
+
+import (
+	"github.com/models"
+)
+
+// ErrUnknownCategory will throw if an article is given a category tag that does not exist
+var ErrUnknownCategory = models.NewBadParam("The article is given an unknown category")
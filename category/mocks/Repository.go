@@ -0,0 +1,159 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *Repository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fetch provides a mock function with given fields: ctx
+func (_m *Repository) Fetch(ctx context.Context) ([]*models.Category, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*models.Category
+	if rf, ok := ret.Get(0).(func(context.Context) []*models.Category); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Category)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchByArticleIDs provides a mock function with given fields: ctx, articleIDs
+func (_m *Repository) FetchByArticleIDs(ctx context.Context, articleIDs []int64) (map[int64][]models.Category, error) {
+	ret := _m.Called(ctx, articleIDs)
+
+	var r0 map[int64][]models.Category
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) map[int64][]models.Category); ok {
+		r0 = rf(ctx, articleIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64][]models.Category)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, articleIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByID(ctx context.Context, id int64) (*models.Category, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Category
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Category); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Category)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByTags provides a mock function with given fields: ctx, tags
+func (_m *Repository) GetByTags(ctx context.Context, tags []string) ([]*models.Category, error) {
+	ret := _m.Called(ctx, tags)
+
+	var r0 []*models.Category
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*models.Category); ok {
+		r0 = rf(ctx, tags)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Category)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, tags)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetArticleCategories provides a mock function with given fields: ctx, articleID, categoryIDs
+func (_m *Repository) SetArticleCategories(ctx context.Context, articleID int64, categoryIDs []int64) error {
+	ret := _m.Called(ctx, articleID, categoryIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64) error); ok {
+		r0 = rf(ctx, articleID, categoryIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Store provides a mock function with given fields: ctx, c
+func (_m *Repository) Store(ctx context.Context, c *models.Category) error {
+	ret := _m.Called(ctx, c)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Category) error); ok {
+		r0 = rf(ctx, c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, c
+func (_m *Repository) Update(ctx context.Context, c *models.Category) error {
+	ret := _m.Called(ctx, c)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Category) error); ok {
+		r0 = rf(ctx, c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,20 @@
+package category
+
+import (
+	"context"
+
+	"github.com/models"
+)
+
+// Repository represent the category's repository contract.
+// FetchByArticleIDs loads the categories of a page of articles in one query, keyed by article id.
+type Repository interface {
+	Fetch(ctx context.Context) ([]*models.Category, error)
+	GetByID(ctx context.Context, id int64) (*models.Category, error)
+	GetByTags(ctx context.Context, tags []string) ([]*models.Category, error)
+	Store(ctx context.Context, c *models.Category) error
+	Update(ctx context.Context, c *models.Category) error
+	Delete(ctx context.Context, id int64) error
+	FetchByArticleIDs(ctx context.Context, articleIDs []int64) (map[int64][]models.Category, error)
+	SetArticleCategories(ctx context.Context, articleID int64, categoryIDs []int64) error
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+
+	"github.com/category"
+	"github.com/models"
+	"github.com/mysqlerr"
+	"github.com/transaction"
+)
+
+// categoryColumns lists the columns in the order they are scanned by fetch
+const categoryColumns = `id, name, tag, created_at, updated_at`
+
+type mysqlCategoryRepository struct {
+	Conn *sql.DB
+}
+
+// NewMysqlCategoryRepository will create an object that represent the category.Repository interface
+func NewMysqlCategoryRepository(Conn *sql.DB) category.Repository {
+	return &mysqlCategoryRepository{Conn}
+}
+
+// conn will return the transaction of the unit of work ctx belongs to, or the connection pool
+func (m *mysqlCategoryRepository) conn(ctx context.Context) transaction.DBTX {
+	return transaction.Conn(ctx, m.Conn)
+}
+
+func (m *mysqlCategoryRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*models.Category, error) {
+	rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}()
+
+	result := make([]*models.Category, 0)
+	for rows.Next() {
+		t := new(models.Category)
+		err = rows.Scan(
+			&t.ID,
+			&t.Name,
+			&t.Tag,
+			&t.CreatedAt,
+			&t.UpdatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+func (m *mysqlCategoryRepository) Fetch(ctx context.Context) ([]*models.Category, error) {
+	return m.fetch(ctx, `SELECT `+categoryColumns+` FROM category ORDER BY name`)
+}
+
+func (m *mysqlCategoryRepository) GetByID(ctx context.Context, id int64) (*models.Category, error) {
+	list, err := m.fetch(ctx, `SELECT `+categoryColumns+` FROM category WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, models.ErrNotFound
+	}
+	return list[0], nil
+}
+
+func (m *mysqlCategoryRepository) GetByTags(ctx context.Context, tags []string) ([]*models.Category, error) {
+	if len(tags) == 0 {
+		return make([]*models.Category, 0), nil
+	}
+	args := make([]interface{}, len(tags))
+	for i, tag := range tags {
+		args[i] = tag
+	}
+	query := `SELECT ` + categoryColumns + ` FROM category WHERE tag IN (` + placeholders(len(tags)) + `) ORDER BY name`
+	return m.fetch(ctx, query, args...)
+}
+
+func (m *mysqlCategoryRepository) Store(ctx context.Context, c *models.Category) error {
+	query := `INSERT category SET name=? , tag=? , created_at=? , updated_at=?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, c.Name, c.Tag, now, now)
+	if err != nil {
+		return conflict(err)
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	c.ID = lastID
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	return nil
+}
+
+func (m *mysqlCategoryRepository) Update(ctx context.Context, c *models.Category) error {
+	c.UpdatedAt = time.Now()
+	err := m.exec(ctx, `UPDATE category SET name=?, tag=?, updated_at=? WHERE id = ?`, c.Name, c.Tag, c.UpdatedAt, c.ID)
+	return conflict(err)
+}
+
+func (m *mysqlCategoryRepository) Delete(ctx context.Context, id int64) error {
+	return m.exec(ctx, `DELETE FROM category WHERE id = ?`, id)
+}
+
+func (m *mysqlCategoryRepository) FetchByArticleIDs(ctx context.Context, articleIDs []int64) (map[int64][]models.Category, error) {
+	result := make(map[int64][]models.Category, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return result, nil
+	}
+	args := make([]interface{}, len(articleIDs))
+	for i, id := range articleIDs {
+		args[i] = id
+	}
+	query := `SELECT ac.article_id, c.id, c.name, c.tag, c.created_at, c.updated_at
+  						FROM article_category ac JOIN category c ON c.id = ac.category_id
+  						WHERE ac.article_id IN (` + placeholders(len(articleIDs)) + `) ORDER BY c.name`
+
+	rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		var articleID int64
+		var c models.Category
+		if err := rows.Scan(&articleID, &c.ID, &c.Name, &c.Tag, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result[articleID] = append(result[articleID], c)
+	}
+	return result, rows.Err()
+}
+
+// SetArticleCategories will replace the categories of the article, it should run in the unit of work storing the article
+func (m *mysqlCategoryRepository) SetArticleCategories(ctx context.Context, articleID int64, categoryIDs []int64) error {
+	if _, err := m.conn(ctx).ExecContext(ctx, `DELETE FROM article_category WHERE article_id = ?`, articleID); err != nil {
+		return err
+	}
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(categoryIDs))
+	args := make([]interface{}, 0, 2*len(categoryIDs))
+	for i, id := range categoryIDs {
+		values[i] = "(?, ?)"
+		args = append(args, articleID, id)
+	}
+	query := `INSERT INTO article_category (article_id, category_id) VALUES ` + strings.Join(values, ", ")
+	_, err := m.conn(ctx).ExecContext(ctx, query, args...)
+	return err
+}
+
+func (m *mysqlCategoryRepository) exec(ctx context.Context, query string, args ...interface{}) error {
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
+// conflict will report a violation of the unique tag as models.ErrConflict
+func conflict(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlerr.DuplicateEntry {
+		return models.ErrConflict
+	}
+	return err
+}
+
+// placeholders will return n comma separated query placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
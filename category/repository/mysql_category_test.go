@@ -0,0 +1,119 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	categoryRepo "github.com/category/repository"
+	"github.com/models"
+)
+
+var columns = []string{"id", "name", "tag", "created_at", "updated_at"}
+
+func TestGetByTags(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows(columns).
+		AddRow(2, "Food", "food", time.Now(), time.Now()).
+		AddRow(1, "Travel", "travel", time.Now(), time.Now())
+	mock.ExpectQuery("SELECT id, name, tag, created_at, updated_at FROM category WHERE tag IN \\(\\?,\\?\\) ORDER BY name").
+		WithArgs("travel", "food").WillReturnRows(rows)
+
+	c := categoryRepo.NewMysqlCategoryRepository(db)
+	list, err := c.GetByTags(context.TODO(), []string{"travel", "food"})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "food", list[0].Tag)
+
+	list, err = c.GetByTags(context.TODO(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	query := "INSERT category SET name=\\? , tag=\\? , created_at=\\? , updated_at=\\?"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("Travel", "travel", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("Travel", "travel", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'travel' for key 'uq_category_tag'"})
+
+	c := categoryRepo.NewMysqlCategoryRepository(db)
+	cat := &models.Category{Name: "Travel", Tag: "travel"}
+	require.NoError(t, c.Store(context.TODO(), cat))
+	assert.Equal(t, int64(12), cat.ID)
+	assert.False(t, cat.CreatedAt.IsZero())
+
+	err = c.Store(context.TODO(), &models.Category{Name: "Travel", Tag: "travel"})
+	assert.Equal(t, models.ErrConflict, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	query := "UPDATE category SET name=\\?, tag=\\?, updated_at=\\? WHERE id = \\?"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("Trips", "trips", sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("Trips", "trips", sqlmock.AnyArg(), 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	c := categoryRepo.NewMysqlCategoryRepository(db)
+	require.NoError(t, c.Update(context.TODO(), &models.Category{ID: 1, Name: "Trips", Tag: "trips"}))
+	assert.Equal(t, models.ErrNotFound, c.Update(context.TODO(), &models.Category{ID: 9, Name: "Trips", Tag: "trips"}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchByArticleIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows(append([]string{"article_id"}, columns...)).
+		AddRow(1, 2, "Food", "food", time.Now(), time.Now()).
+		AddRow(3, 2, "Food", "food", time.Now(), time.Now()).
+		AddRow(1, 1, "Travel", "travel", time.Now(), time.Now())
+	query := "SELECT ac.article_id, c.id, c.name, c.tag, c.created_at, c.updated_at FROM article_category ac " +
+		"JOIN category c ON c.id = ac.category_id WHERE ac.article_id IN \\(\\?,\\?,\\?\\) ORDER BY c.name"
+	mock.ExpectQuery(query).WithArgs(1, 2, 3).WillReturnRows(rows)
+
+	c := categoryRepo.NewMysqlCategoryRepository(db)
+	res, err := c.FetchByArticleIDs(context.TODO(), []int64{1, 2, 3})
+	require.NoError(t, err)
+	require.Len(t, res[1], 2)
+	assert.Equal(t, "food", res[1][0].Tag)
+	assert.Equal(t, "travel", res[1][1].Tag)
+	assert.Empty(t, res[2])
+	assert.Len(t, res[3], 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetArticleCategories(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec("DELETE FROM article_category WHERE article_id = \\?").WithArgs(5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO article_category \\(article_id, category_id\\) VALUES \\(\\?, \\?\\), \\(\\?, \\?\\)").
+		WithArgs(5, 1, 5, 2).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("DELETE FROM article_category WHERE article_id = \\?").WithArgs(6).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	c := categoryRepo.NewMysqlCategoryRepository(db)
+	require.NoError(t, c.SetArticleCategories(context.TODO(), 5, []int64{1, 2}))
+	require.NoError(t, c.SetArticleCategories(context.TODO(), 6, nil))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,16 @@
+package category
+
+import (
+	"context"
+
+	"github.com/models"
+)
+
+// Usecase represent the category's usecases
+type Usecase interface {
+	Fetch(ctx context.Context) ([]*models.Category, error)
+	GetByID(ctx context.Context, id int64) (*models.Category, error)
+	Store(ctx context.Context, c *models.Category) error
+	Update(ctx context.Context, c *models.Category) error
+	Delete(ctx context.Context, id int64) error
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/category"
+	"github.com/models"
+)
+
+type categoryUsecase struct {
+	categoryRepo   category.Repository
+	contextTimeout time.Duration
+}
+
+// NewCategoryUsecase will create new a categoryUsecase object representation of category.Usecase interface
+func NewCategoryUsecase(c category.Repository, timeout time.Duration) category.Usecase {
+	return &categoryUsecase{
+		categoryRepo:   c,
+		contextTimeout: timeout,
+	}
+}
+
+func (u *categoryUsecase) Fetch(c context.Context) ([]*models.Category, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.categoryRepo.Fetch(ctx)
+}
+
+func (u *categoryUsecase) GetByID(c context.Context, id int64) (*models.Category, error) {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.categoryRepo.GetByID(ctx, id)
+}
+
+// Store will create the category, a tag already in use is a models.ErrConflict
+func (u *categoryUsecase) Store(c context.Context, m *models.Category) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.categoryRepo.Store(ctx, m)
+}
+
+// Update will rename the category or change its tag, the articles keep the category
+func (u *categoryUsecase) Update(c context.Context, m *models.Category) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	existed, err := u.categoryRepo.GetByID(ctx, m.ID)
+	if err != nil {
+		return err
+	}
+	if err := u.categoryRepo.Update(ctx, m); err != nil {
+		return err
+	}
+	m.CreatedAt = existed.CreatedAt
+	return nil
+}
+
+// Delete will remove the category, the articles lose it
+func (u *categoryUsecase) Delete(c context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	return u.categoryRepo.Delete(ctx, id)
+}
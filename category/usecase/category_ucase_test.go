@@ -0,0 +1,51 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/category/mocks"
+	ucase "github.com/category/usecase"
+	"github.com/models"
+)
+
+func TestUpdate(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.Repository)
+		cat := &models.Category{ID: 1, Name: "Trips", Tag: "trips"}
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(1)).Return(&models.Category{ID: 1, Name: "Travel", Tag: "travel", CreatedAt: created}, nil).Once()
+		mockCategoryRepo.On("Update", mock.Anything, cat).Return(nil).Once()
+
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, time.Second*2)
+		require.NoError(t, u.Update(context.TODO(), cat))
+		assert.Equal(t, created, cat.CreatedAt)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		mockCategoryRepo := new(mocks.Repository)
+		mockCategoryRepo.On("GetByID", mock.Anything, int64(9)).Return(nil, models.ErrNotFound).Once()
+
+		u := ucase.NewCategoryUsecase(mockCategoryRepo, time.Second*2)
+		err := u.Update(context.TODO(), &models.Category{ID: 9, Name: "Trips", Tag: "trips"})
+		assert.Equal(t, models.ErrNotFound, err)
+		mockCategoryRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestStore(t *testing.T) {
+	mockCategoryRepo := new(mocks.Repository)
+	mockCategoryRepo.On("Store", mock.Anything, mock.AnythingOfType("*models.Category")).Return(models.ErrConflict).Once()
+
+	u := ucase.NewCategoryUsecase(mockCategoryRepo, time.Second*2)
+	err := u.Store(context.TODO(), &models.Category{Name: "Travel", Tag: "travel"})
+	assert.Equal(t, models.ErrConflict, err)
+	mockCategoryRepo.AssertExpectations(t)
+}
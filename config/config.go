@@ -0,0 +1,501 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix is the prefix of the environment variables that override config.json keys,
+// e.g. CGO_DATABASE_PASS overrides database.pass
+const EnvPrefix = "CGO"
+
+// Config represent the runtime settings of the service
+type Config struct {
+	Debug          bool
+	Server         Server
+	Context        Context
+	Database       Database
+	IdentityServer IdentityServer
+	Auth           Auth
+	Points         Points
+	Verification   Verification
+	Password       Password
+	Storage        Storage
+	Search         Search
+}
+
+// Server represent the http server settings
+type Server struct {
+	Address string
+}
+
+// Context represent the settings applied to the context of every usecase call
+type Context struct {
+	Timeout time.Duration
+}
+
+// Database represent the mysql connection settings
+type Database struct {
+	Host     string
+	Port     string
+	User     string
+	Pass     string
+	Name     string
+	Location string
+}
+
+// IdentityServer represent the identity server client settings
+type IdentityServer struct {
+	BaseURL   string
+	BasicAuth string
+	TLS       TLS
+	JWT       JWT
+}
+
+// JWT represent how the access tokens are verified locally with the identity server JWKS,
+// when disabled every token is checked by calling /connect/userinfo. Audience is required when enabled.
+type JWT struct {
+	Enabled  bool
+	Audience string
+	Issuer   string
+	Leeway   time.Duration
+}
+
+// TLS represent how the identity server certificate is verified.
+// Verification is on by default, InsecureSkipVerify is only meant for local development.
+type TLS struct {
+	CAFile             string
+	PinnedSHA256       []string
+	ClientCertFile     string
+	ClientKeyFile      string
+	InsecureSkipVerify bool
+}
+
+// Auth represent the authorization settings
+type Auth struct {
+	// Admins lists the emails or usernames granted the admin role
+	Admins []string
+}
+
+// Points represent the loyalty points settings.
+// The earned points expire after ExpiryPeriod, they never expire when it is zero,
+// and the expiry job looks for expired points every ExpiryInterval.
+type Points struct {
+	ExpiryPeriod   time.Duration
+	ExpiryInterval time.Duration
+	Rules          []PointsRule
+}
+
+// PointsRule represent how many points an event is worth, for every PerAmount of the event amount when it is set
+type PointsRule struct {
+	Event     string
+	Points    int64
+	PerAmount int64
+}
+
+// Verification represent how the verification codes are generated and delivered.
+// The codes are sent through SMTP and the SMS gateway when they are configured.
+type Verification struct {
+	CodeTTL        time.Duration
+	MaxAttempts    int
+	ResendInterval time.Duration
+	SMTP           SMTP
+	SMS            SMS
+}
+
+// SMTP represent the mail server the verification emails are sent through, it is disabled when Host is empty
+type SMTP struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMS represent the SMS gateway the verification texts are sent through, it is disabled when URL is empty
+type SMS struct {
+	URL    string
+	APIKey string
+	Sender string
+}
+
+// Password represent the password reset settings.
+// The reset link is ResetURL with the token as the token query parameter, the token is sent alone when it is empty.
+// An account is sent a token at most once every ForgotInterval.
+type Password struct {
+	ResetTokenTTL  time.Duration
+	ResetURL       string
+	ForgotInterval time.Duration
+}
+
+// Search represent the full-text search of the articles, Engine is mysql or memory.
+// The memory engine reads every article at startup and keeps the index in the service.
+type Search struct {
+	Engine string
+}
+
+// Storage represent where the uploaded pictures are stored, Backend is local or s3.
+// The uploads bigger than MaxUploadSize bytes are refused.
+type Storage struct {
+	Backend       string
+	MaxUploadSize int64
+	Local         LocalStorage
+	S3            S3Storage
+}
+
+// LocalStorage represent a directory the service writes the files to and serves them from under BaseURL
+type LocalStorage struct {
+	Dir     string
+	BaseURL string
+}
+
+// S3Storage represent a bucket of an S3 compatible object storage, the files are served from BaseURL when it is set
+type S3Storage struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+	BaseURL   string
+	ACL       string
+}
+
+// Load will read the config file from the given path and apply the environment variable overrides.
+// A missing config file is not an error, so the service can be configured from the environment only.
+func Load(path string) (*Config, error) {
+	cfg, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadDatabase will read the config like Load but only require the database settings,
+// for the tools that do not start the service such as the migrations.
+func LoadDatabase(path string) (*Database, error) {
+	cfg, err := read(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Database.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg.Database, nil
+}
+
+func read(path string) (*Config, error) {
+	v := viper.New()
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	v.SetDefault("debug", false)
+	v.SetDefault("server.address", ":9090")
+	v.SetDefault("context.timeout", 30)
+	v.SetDefault("database.port", "3306")
+	v.SetDefault("database.location", "Asia/Jakarta")
+	v.SetDefault("identityServer.jwt.enabled", true)
+	v.SetDefault("identityServer.jwt.leeway", 30)
+	v.SetDefault("points.expiryDays", 365)
+	v.SetDefault("points.expiryInterval", 60)
+	v.SetDefault("verification.codeTtl", 15)
+	v.SetDefault("verification.maxAttempts", 5)
+	v.SetDefault("verification.resendInterval", 60)
+	v.SetDefault("verification.smtp.port", "587")
+	v.SetDefault("password.resetTokenTtl", 60)
+	v.SetDefault("password.forgotInterval", 60)
+	v.SetDefault("storage.backend", "local")
+	v.SetDefault("storage.maxUploadKb", 5120)
+	v.SetDefault("storage.local.dir", "uploads")
+	v.SetDefault("storage.local.baseUrl", "/uploads")
+	v.SetDefault("storage.s3.region", "us-east-1")
+	v.SetDefault("search.engine", "mysql")
+
+	if path != "" {
+		v.SetConfigFile(path)
+		if err := v.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("read config %s: %v", path, err)
+		}
+	}
+
+	cfg := &Config{
+		Debug: v.GetBool("debug"),
+		Server: Server{
+			Address: v.GetString("server.address"),
+		},
+		Context: Context{
+			Timeout: time.Duration(v.GetInt("context.timeout")) * time.Second,
+		},
+		Database: Database{
+			Host:     v.GetString("database.host"),
+			Port:     v.GetString("database.port"),
+			User:     v.GetString("database.user"),
+			Pass:     v.GetString("database.pass"),
+			Name:     v.GetString("database.name"),
+			Location: v.GetString("database.location"),
+		},
+		IdentityServer: IdentityServer{
+			BaseURL:   strings.TrimRight(v.GetString("identityServer.baseUrl"), "/"),
+			BasicAuth: v.GetString("identityServer.basicAuth"),
+			TLS: TLS{
+				CAFile:             v.GetString("identityServer.tls.caFile"),
+				PinnedSHA256:       v.GetStringSlice("identityServer.tls.pinnedSha256"),
+				ClientCertFile:     v.GetString("identityServer.tls.clientCertFile"),
+				ClientKeyFile:      v.GetString("identityServer.tls.clientKeyFile"),
+				InsecureSkipVerify: v.GetBool("identityServer.tls.insecureSkipVerify"),
+			},
+			JWT: JWT{
+				Enabled:  v.GetBool("identityServer.jwt.enabled"),
+				Audience: v.GetString("identityServer.jwt.audience"),
+				Issuer:   v.GetString("identityServer.jwt.issuer"),
+				Leeway:   time.Duration(v.GetInt("identityServer.jwt.leeway")) * time.Second,
+			},
+		},
+		Auth: Auth{
+			Admins: v.GetStringSlice("auth.admins"),
+		},
+		Points: Points{
+			ExpiryPeriod:   time.Duration(v.GetInt("points.expiryDays")) * 24 * time.Hour,
+			ExpiryInterval: time.Duration(v.GetInt("points.expiryInterval")) * time.Minute,
+		},
+		Verification: Verification{
+			CodeTTL:        time.Duration(v.GetInt("verification.codeTtl")) * time.Minute,
+			MaxAttempts:    v.GetInt("verification.maxAttempts"),
+			ResendInterval: time.Duration(v.GetInt("verification.resendInterval")) * time.Second,
+			SMTP: SMTP{
+				Host:     v.GetString("verification.smtp.host"),
+				Port:     v.GetString("verification.smtp.port"),
+				Username: v.GetString("verification.smtp.username"),
+				Password: v.GetString("verification.smtp.password"),
+				From:     v.GetString("verification.smtp.from"),
+			},
+			SMS: SMS{
+				URL:    v.GetString("verification.sms.url"),
+				APIKey: v.GetString("verification.sms.apiKey"),
+				Sender: v.GetString("verification.sms.sender"),
+			},
+		},
+		Password: Password{
+			ResetTokenTTL:  time.Duration(v.GetInt("password.resetTokenTtl")) * time.Minute,
+			ResetURL:       v.GetString("password.resetUrl"),
+			ForgotInterval: time.Duration(v.GetInt("password.forgotInterval")) * time.Second,
+		},
+		Storage: Storage{
+			Backend:       v.GetString("storage.backend"),
+			MaxUploadSize: v.GetInt64("storage.maxUploadKb") * 1024,
+			Local: LocalStorage{
+				Dir:     v.GetString("storage.local.dir"),
+				BaseURL: strings.TrimRight(v.GetString("storage.local.baseUrl"), "/"),
+			},
+			S3: S3Storage{
+				Endpoint:  strings.TrimRight(v.GetString("storage.s3.endpoint"), "/"),
+				Region:    v.GetString("storage.s3.region"),
+				Bucket:    v.GetString("storage.s3.bucket"),
+				AccessKey: v.GetString("storage.s3.accessKey"),
+				SecretKey: v.GetString("storage.s3.secretKey"),
+				PathStyle: v.GetBool("storage.s3.pathStyle"),
+				BaseURL:   strings.TrimRight(v.GetString("storage.s3.baseUrl"), "/"),
+				ACL:       v.GetString("storage.s3.acl"),
+			},
+		},
+		Search: Search{
+			Engine: v.GetString("search.engine"),
+		},
+	}
+	if err := v.UnmarshalKey("points.rules", &cfg.Points.Rules); err != nil {
+		return nil, fmt.Errorf("read points.rules: %v", err)
+	}
+	return cfg, nil
+}
+
+// Validate will check that every setting required at startup is present
+func (c *Config) Validate() error {
+	if err := checkRequired([]requiredKey{
+		{"server.address", c.Server.Address},
+		{"database.host", c.Database.Host},
+		{"database.port", c.Database.Port},
+		{"database.user", c.Database.User},
+		{"database.name", c.Database.Name},
+		{"identityServer.baseUrl", c.IdentityServer.BaseURL},
+		{"identityServer.basicAuth", c.IdentityServer.BasicAuth},
+	}); err != nil {
+		return err
+	}
+
+	if c.Context.Timeout <= 0 {
+		return errors.New("context.timeout must be greater than zero")
+	}
+	if _, err := url.ParseRequestURI(c.IdentityServer.BaseURL); err != nil {
+		return fmt.Errorf("identityServer.baseUrl is not a valid url: %v", err)
+	}
+	if c.IdentityServer.JWT.Enabled && c.IdentityServer.JWT.Audience == "" {
+		return errors.New("identityServer.jwt.audience is required when identityServer.jwt.enabled is true")
+	}
+	if (c.IdentityServer.TLS.ClientCertFile == "") != (c.IdentityServer.TLS.ClientKeyFile == "") {
+		return errors.New("identityServer.tls.clientCertFile and identityServer.tls.clientKeyFile must be set together")
+	}
+	if err := c.Points.Validate(); err != nil {
+		return err
+	}
+	if err := c.Verification.Validate(); err != nil {
+		return err
+	}
+	if err := c.Password.Validate(); err != nil {
+		return err
+	}
+	if err := c.Storage.Validate(); err != nil {
+		return err
+	}
+	return c.Search.Validate()
+}
+
+// Validate will check the expiry settings and that every earning rule awards points to a distinct event
+func (p Points) Validate() error {
+	if p.ExpiryPeriod < 0 {
+		return errors.New("points.expiryDays must not be negative")
+	}
+	if p.ExpiryInterval <= 0 {
+		return errors.New("points.expiryInterval must be greater than zero")
+	}
+	events := map[string]bool{}
+	for i, rule := range p.Rules {
+		switch {
+		case rule.Event == "":
+			return fmt.Errorf("points.rules[%d].event is required", i)
+		case events[rule.Event]:
+			return fmt.Errorf("points.rules[%d]: event %q has more than one rule", i, rule.Event)
+		case rule.Points <= 0:
+			return fmt.Errorf("points.rules[%d].points must be greater than zero", i)
+		case rule.PerAmount < 0:
+			return fmt.Errorf("points.rules[%d].perAmount must not be negative", i)
+		}
+		events[rule.Event] = true
+	}
+	return nil
+}
+
+// Validate will check the code settings and that the enabled notifiers know who they send as
+func (v Verification) Validate() error {
+	switch {
+	case v.CodeTTL <= 0:
+		return errors.New("verification.codeTtl must be greater than zero")
+	case v.MaxAttempts <= 0:
+		return errors.New("verification.maxAttempts must be greater than zero")
+	case v.ResendInterval < 0:
+		return errors.New("verification.resendInterval must not be negative")
+	case v.SMTP.Host != "" && v.SMTP.From == "":
+		return errors.New("verification.smtp.from is required when verification.smtp.host is set")
+	}
+	if v.SMS.URL != "" {
+		if _, err := url.ParseRequestURI(v.SMS.URL); err != nil {
+			return fmt.Errorf("verification.sms.url is not a valid url: %v", err)
+		}
+	}
+	return nil
+}
+
+// Validate will check the lifetime of the reset tokens, how often they are sent and the reset link
+func (p Password) Validate() error {
+	if p.ResetTokenTTL <= 0 {
+		return errors.New("password.resetTokenTtl must be greater than zero")
+	}
+	if p.ForgotInterval < 0 {
+		return errors.New("password.forgotInterval must not be negative")
+	}
+	if p.ResetURL != "" {
+		if _, err := url.ParseRequestURI(p.ResetURL); err != nil {
+			return fmt.Errorf("password.resetUrl is not a valid url: %v", err)
+		}
+	}
+	return nil
+}
+
+// Validate will check the upload limit and the settings of the selected backend
+func (s Storage) Validate() error {
+	if s.MaxUploadSize <= 0 {
+		return errors.New("storage.maxUploadKb must be greater than zero")
+	}
+	switch s.Backend {
+	case "local":
+		return checkRequired([]requiredKey{
+			{"storage.local.dir", s.Local.Dir},
+			{"storage.local.baseUrl", s.Local.BaseURL},
+		})
+	case "s3":
+		if err := checkRequired([]requiredKey{
+			{"storage.s3.endpoint", s.S3.Endpoint},
+			{"storage.s3.region", s.S3.Region},
+			{"storage.s3.bucket", s.S3.Bucket},
+			{"storage.s3.accessKey", s.S3.AccessKey},
+			{"storage.s3.secretKey", s.S3.SecretKey},
+		}); err != nil {
+			return err
+		}
+		if _, err := url.ParseRequestURI(s.S3.Endpoint); err != nil {
+			return fmt.Errorf("storage.s3.endpoint is not a valid url: %v", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("storage.backend must be local or s3, got %q", s.Backend)
+	}
+}
+
+// Validate will check that the engine is known
+func (s Search) Validate() error {
+	switch s.Engine {
+	case "mysql", "memory":
+		return nil
+	default:
+		return fmt.Errorf("search.engine must be mysql or memory, got %q", s.Engine)
+	}
+}
+
+// Validate will check that the connection settings are present
+func (d Database) Validate() error {
+	return checkRequired([]requiredKey{
+		{"database.host", d.Host},
+		{"database.port", d.Port},
+		{"database.user", d.User},
+		{"database.name", d.Name},
+	})
+}
+
+type requiredKey struct {
+	key   string
+	value string
+}
+
+func checkRequired(required []requiredKey) error {
+	var missing []string
+	for _, r := range required {
+		if r.value == "" {
+			missing = append(missing, r.key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required config: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// DSN will build the mysql data source name from the database settings.
+// The updates report the rows they matched rather than the rows they changed, so writing a row again as it
+// is is not taken for a missing row.
+func (d Database) DSN() string {
+	connection := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", d.User, d.Pass, d.Host, d.Port, d.Name)
+	val := url.Values{}
+	val.Add("clientFoundRows", "true")
+	val.Add("parseTime", "1")
+	val.Add("loc", d.Location)
+	return fmt.Sprintf("%s?%s", connection, val.Encode())
+}
@@ -0,0 +1,203 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/config"
+)
+
+const sampleConfig = `{
+  "debug": true,
+  "server": {"address": ":8080"},
+  "context": {"timeout": 10},
+  "database": {"host": "localhost", "port": "3307", "user": "cgo", "pass": "secret", "name": "cgo_indonesia"},
+  "identityServer": {"baseUrl": "https://is.local/", "basicAuth": "Y2xpZW50OnNlY3JldA==", "jwt": {"audience": "cgo-api"}}
+}`
+
+func writeConfig(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "config.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	cfg, err := config.Load(writeConfig(t, sampleConfig))
+	require.NoError(t, err)
+
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, ":8080", cfg.Server.Address)
+	assert.Equal(t, 10*time.Second, cfg.Context.Timeout)
+	assert.Equal(t, "secret", cfg.Database.Pass)
+	assert.Equal(t, "https://is.local", cfg.IdentityServer.BaseURL)
+	assert.Equal(t, "cgo:secret@tcp(localhost:3307)/cgo_indonesia?clientFoundRows=true&loc=Asia%2FJakarta&parseTime=1", cfg.Database.DSN())
+}
+
+func TestLoadEnvOverride(t *testing.T) {
+	os.Setenv("CGO_DATABASE_PASS", "from-env")
+	os.Setenv("CGO_SERVER_ADDRESS", ":9999")
+	defer os.Unsetenv("CGO_DATABASE_PASS")
+	defer os.Unsetenv("CGO_SERVER_ADDRESS")
+
+	cfg, err := config.Load(writeConfig(t, sampleConfig))
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", cfg.Database.Pass)
+	assert.Equal(t, ":9999", cfg.Server.Address)
+}
+
+func TestLoadMissingRequired(t *testing.T) {
+	_, err := config.Load(writeConfig(t, `{"database": {"host": "localhost"}}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database.user")
+	assert.Contains(t, err.Error(), "identityServer.baseUrl")
+}
+
+func TestLoadJWTAudience(t *testing.T) {
+	withoutAudience := strings.Replace(sampleConfig, `, "jwt": {"audience": "cgo-api"}`, ``, 1)
+	_, err := config.Load(writeConfig(t, withoutAudience))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "identityServer.jwt.audience")
+
+	disabled := strings.Replace(sampleConfig, `"jwt": {"audience": "cgo-api"}`, `"jwt": {"enabled": false}`, 1)
+	cfg, err := config.Load(writeConfig(t, disabled))
+	require.NoError(t, err)
+	assert.False(t, cfg.IdentityServer.JWT.Enabled)
+}
+
+func TestLoadDatabase(t *testing.T) {
+	db, err := config.LoadDatabase(writeConfig(t, `{"database": {"host": "localhost", "user": "root", "name": "cgo"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "3306", db.Port)
+	assert.Contains(t, db.DSN(), "root:@tcp(localhost:3306)/cgo?")
+
+	_, err = config.LoadDatabase(writeConfig(t, `{"database": {"host": "localhost"}}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database.user")
+	assert.NotContains(t, err.Error(), "identityServer.baseUrl")
+}
+
+func TestLoadPoints(t *testing.T) {
+	cfg, err := config.Load(writeConfig(t, sampleConfig))
+	require.NoError(t, err)
+	assert.Equal(t, 365*24*time.Hour, cfg.Points.ExpiryPeriod)
+	assert.Equal(t, time.Hour, cfg.Points.ExpiryInterval)
+	assert.Empty(t, cfg.Points.Rules)
+
+	withRules := strings.Replace(sampleConfig, `"debug": true,`, `"debug": true,
+  "points": {"expiryDays": 30, "rules": [{"event": "registration", "points": 100}, {"event": "purchase", "points": 1, "perAmount": 1000000}]},`, 1)
+	cfg, err = config.Load(writeConfig(t, withRules))
+	require.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, cfg.Points.ExpiryPeriod)
+	assert.Equal(t, []config.PointsRule{
+		{Event: "registration", Points: 100},
+		{Event: "purchase", Points: 1, PerAmount: 1000000},
+	}, cfg.Points.Rules)
+
+	duplicated := strings.Replace(sampleConfig, `"debug": true,`, `"debug": true,
+  "points": {"rules": [{"event": "registration", "points": 100}, {"event": "registration", "points": 50}]},`, 1)
+	_, err = config.Load(writeConfig(t, duplicated))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "registration")
+}
+
+func TestLoadVerification(t *testing.T) {
+	cfg, err := config.Load(writeConfig(t, sampleConfig))
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, cfg.Verification.CodeTTL)
+	assert.Equal(t, 5, cfg.Verification.MaxAttempts)
+	assert.Equal(t, time.Minute, cfg.Verification.ResendInterval)
+	assert.Empty(t, cfg.Verification.SMTP.Host)
+	assert.Equal(t, "587", cfg.Verification.SMTP.Port)
+
+	withSMTP := strings.Replace(sampleConfig, `"debug": true,`, `"debug": true,
+  "verification": {"codeTtl": 5, "smtp": {"host": "smtp.local", "port": "25", "from": "no-reply@cgo.local"}, "sms": {"url": "https://sms.local/send", "apiKey": "key"}},`, 1)
+	cfg, err = config.Load(writeConfig(t, withSMTP))
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, cfg.Verification.CodeTTL)
+	assert.Equal(t, config.SMTP{Host: "smtp.local", Port: "25", From: "no-reply@cgo.local"}, cfg.Verification.SMTP)
+	assert.Equal(t, "key", cfg.Verification.SMS.APIKey)
+
+	withoutFrom := strings.Replace(sampleConfig, `"debug": true,`, `"debug": true,
+  "verification": {"smtp": {"host": "smtp.local"}},`, 1)
+	_, err = config.Load(writeConfig(t, withoutFrom))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "verification.smtp.from")
+}
+
+func TestLoadPassword(t *testing.T) {
+	cfg, err := config.Load(writeConfig(t, sampleConfig))
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, cfg.Password.ResetTokenTTL)
+	assert.Empty(t, cfg.Password.ResetURL)
+	assert.Equal(t, time.Minute, cfg.Password.ForgotInterval)
+
+	invalid := strings.Replace(sampleConfig, `"debug": true,`, `"debug": true,
+  "password": {"resetTokenTtl": 30, "resetUrl": "not a url"},`, 1)
+	_, err = config.Load(writeConfig(t, invalid))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "password.resetUrl")
+
+	negative := strings.Replace(sampleConfig, `"debug": true,`, `"debug": true,
+  "password": {"forgotInterval": -1},`, 1)
+	_, err = config.Load(writeConfig(t, negative))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "password.forgotInterval")
+}
+
+func TestLoadStorage(t *testing.T) {
+	cfg, err := config.Load(writeConfig(t, sampleConfig))
+	require.NoError(t, err)
+	assert.Equal(t, "local", cfg.Storage.Backend)
+	assert.Equal(t, int64(5*1024*1024), cfg.Storage.MaxUploadSize)
+	assert.Equal(t, config.LocalStorage{Dir: "uploads", BaseURL: "/uploads"}, cfg.Storage.Local)
+
+	withS3 := strings.Replace(sampleConfig, `"debug": true,`, `"debug": true,
+  "storage": {"backend": "s3", "maxUploadKb": 1024, "s3": {"endpoint": "http://minio.local:9000/", "bucket": "cgo", "accessKey": "key", "secretKey": "secret", "pathStyle": true}},`, 1)
+	cfg, err = config.Load(writeConfig(t, withS3))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1024*1024), cfg.Storage.MaxUploadSize)
+	assert.Equal(t, "http://minio.local:9000", cfg.Storage.S3.Endpoint)
+	assert.Equal(t, "us-east-1", cfg.Storage.S3.Region)
+	assert.True(t, cfg.Storage.S3.PathStyle)
+
+	withoutBucket := strings.Replace(sampleConfig, `"debug": true,`, `"debug": true,
+  "storage": {"backend": "s3", "s3": {"endpoint": "http://minio.local:9000"}},`, 1)
+	_, err = config.Load(writeConfig(t, withoutBucket))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "storage.s3.bucket")
+
+	unknown := strings.Replace(sampleConfig, `"debug": true,`, `"debug": true,
+  "storage": {"backend": "ftp"},`, 1)
+	_, err = config.Load(writeConfig(t, unknown))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "storage.backend")
+}
+
+func TestLoadSearch(t *testing.T) {
+	cfg, err := config.Load(writeConfig(t, sampleConfig))
+	require.NoError(t, err)
+	assert.Equal(t, "mysql", cfg.Search.Engine)
+
+	inMemory := strings.Replace(sampleConfig, `"debug": true,`, `"debug": true,
+  "search": {"engine": "memory"},`, 1)
+	cfg, err = config.Load(writeConfig(t, inMemory))
+	require.NoError(t, err)
+	assert.Equal(t, "memory", cfg.Search.Engine)
+
+	unknown := strings.Replace(sampleConfig, `"debug": true,`, `"debug": true,
+  "search": {"engine": "elastic"},`, 1)
+	_, err = config.Load(writeConfig(t, unknown))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "search.engine")
+}
@@ -0,0 +1,36 @@
+package identityserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RemoveAccount will undo the registration of an account whose local record could not be stored.
+// The account is deleted, or disabled when the identity server refuses to delete it.
+// It runs on its own context so the compensation still happens when the request was cancelled.
+func RemoveAccount(is Usecase, id string, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := is.DeleteUser(ctx, id)
+	if err == nil {
+		return
+	}
+	logrus.WithError(err).WithField("id", id).Warn("delete the identity server account, disabling it instead")
+	if err := is.DisableUser(ctx, id); err != nil {
+		logrus.WithError(err).WithField("id", id).Error("the identity server account is orphaned, remove it manually")
+	}
+}
+
+// EnableAccount will undo the DisableUser of an account whose local record could not be deleted.
+// It runs on its own context so the compensation still happens when the request was cancelled.
+func EnableAccount(is Usecase, id string, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := is.EnableUser(ctx, id); err != nil {
+		logrus.WithError(err).WithField("id", id).Error("the identity server account is disabled but not deleted, enable it manually")
+	}
+}
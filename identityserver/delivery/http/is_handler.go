@@ -2,13 +2,16 @@ package http
 
 import (
 	"context"
+	"errors"
 	"github.com/merchant"
+	"github.com/middleware"
 	"github.com/user"
 	"net/http"
 
 	"github.com/labstack/echo"
 	"github.com/sirupsen/logrus"
 
+	"github.com/identityserver"
 	"github.com/models"
 )
 
@@ -20,17 +23,19 @@ type ResponseError struct {
 // isHandler  represent the httphandler for is
 type isHandler struct {
 	merchantUsecase merchant.Usecase
-	userUsecase 	user.Usecase
+	userUsecase     user.Usecase
 }
 
 // NewisHandler will initialize the iss/ resources endpoint
-func NewisHandler(e *echo.Echo, m merchant.Usecase,u user.Usecase) {
+func NewisHandler(e *echo.Echo, m merchant.Usecase, u user.Usecase, mw *middleware.GoMiddleware) {
 	handler := &isHandler{
-		merchantUsecase:m,
-		userUsecase:u,
+		merchantUsecase: m,
+		userUsecase:     u,
 	}
-	e.GET("/account/info", handler.GetInfo)
+	e.GET("/account/info", handler.GetInfo, mw.Authenticate)
 	e.POST("/account/login", handler.Login)
+	e.POST("/account/refresh", handler.Refresh)
+	e.POST("/account/logout", handler.Logout, mw.Authenticate)
 }
 
 func (a *isHandler) Login(c echo.Context) error {
@@ -57,7 +62,7 @@ func (a *isHandler) Login(c echo.Context) error {
 			return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 		}
 		responseToken = token
-	}else if isLogin.Type == "merchant" {
+	} else if isLogin.Type == "merchant" {
 
 		token, err := a.merchantUsecase.Login(ctx, &isLogin)
 
@@ -65,41 +70,97 @@ func (a *isHandler) Login(c echo.Context) error {
 			return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 		}
 		responseToken = token
-	}else {
-		return c.JSON(http.StatusBadRequest,"Bad Request")
+	} else {
+		return c.JSON(http.StatusBadRequest, "Bad Request")
 	}
 
 	return c.JSON(http.StatusCreated, responseToken)
 }
 
+// Refresh will exchange a refresh token of a user or merchant for a new access token
+func (a *isHandler) Refresh(c echo.Context) error {
+	var request models.RefreshTokenRequest
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+	if request.RefreshToken == "" {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: "refresh_token is required"})
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var responseToken *models.GetToken
+	if request.Type == models.PrincipalUser {
+		responseToken, err = a.userUsecase.RefreshToken(ctx, request.RefreshToken)
+	} else if request.Type == models.PrincipalMerchant {
+		responseToken, err = a.merchantUsecase.RefreshToken(ctx, request.RefreshToken)
+	} else {
+		return c.JSON(http.StatusBadRequest, "Bad Request")
+	}
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, responseToken)
+}
+
+// Logout will revoke the access token of the caller and the refresh token given in the body
+func (a *isHandler) Logout(c echo.Context) error {
+	var request models.Logout
+	err := c.Bind(&request)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, err.Error())
+	}
+
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	principal := middleware.GetPrincipal(c)
+	if principal.Type == models.PrincipalUser {
+		err = a.userUsecase.Logout(ctx, principal, request.RefreshToken)
+	} else if principal.Type == models.PrincipalMerchant {
+		err = a.merchantUsecase.Logout(ctx, principal, request.RefreshToken)
+	} else {
+		return c.JSON(http.StatusBadRequest, "Bad Request")
+	}
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetInfo will return the profile of the authenticated user or merchant
 func (a *isHandler) GetInfo(c echo.Context) error {
 
 	ctx := c.Request().Context()
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	token := c.Request().Header.Get("Authorization")
-	typeUser := c.QueryParam("type")
-	if typeUser == "user"{
-		response, err := a.userUsecase.GetUserInfo(ctx, token)
+	principal := middleware.GetPrincipal(c)
+	if principal.Type == models.PrincipalUser {
+		response, err := a.userUsecase.GetUserInfo(ctx, principal.Token)
 
 		if err != nil {
 			return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 		}
 
-		return c.JSON(http.StatusCreated, response)
-	} else if typeUser == "merchant"{
-		response, err := a.merchantUsecase.GetMerchantInfo(ctx, token)
+		return c.JSON(http.StatusOK, response)
+	} else if principal.Type == models.PrincipalMerchant {
+		response, err := a.merchantUsecase.GetMerchantInfo(ctx, principal.Token)
 
 		if err != nil {
 			return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 		}
-		return c.JSON(http.StatusCreated, response)
-	}else {
-		return c.JSON(http.StatusBadRequest,"Bad Request")
+		return c.JSON(http.StatusOK, response)
 	}
-
-	return c.JSON(http.StatusBadRequest,"Bad Request")
+	return c.JSON(http.StatusBadRequest, "Bad Request")
 }
 
 func getStatusCode(err error) int {
@@ -107,17 +168,21 @@ func getStatusCode(err error) int {
 		return http.StatusOK
 	}
 	logrus.Error(err)
-	switch err {
-	case models.ErrInternalServerError:
+	switch {
+	case errors.Is(err, models.ErrInternalServerError):
 		return http.StatusInternalServerError
-	case models.ErrNotFound:
+	case errors.Is(err, models.ErrNotFound):
 		return http.StatusNotFound
-	case models.ErrConflict:
-		return http.StatusConflict
-	case models.ErrUnAuthorize:
+	case errors.Is(err, models.ErrUnAuthorize):
 		return http.StatusUnauthorized
-	case models.ErrBadParamInput:
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrBadParamInput):
 		return http.StatusBadRequest
+	case errors.Is(err, identityserver.ErrUnreachable):
+		return http.StatusBadGateway
 	default:
 		return http.StatusInternalServerError
 	}
@@ -0,0 +1,24 @@
+package identityserver
+
+import (
+	"errors"
+
+	"github.com/models"
+)
+
+// ErrUnreachable will throw if the identity server can not be reached or is failing
+var ErrUnreachable = errors.New("Identity server is unreachable")
+
+// ValidationError will throw if the identity server rejects the given payload,
+// Message holds the reason given by the identity server
+type ValidationError struct {
+	models.BadParam
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Message == "" {
+		return models.ErrBadParamInput.Error()
+	}
+	return e.Message
+}
@@ -1 +1,182 @@
+// Code generated by mockery v1.0.0
 package mocks
+
+import context "context"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// CreateUser provides a mock function with given fields: ctx, ar
+func (_m *Usecase) CreateUser(ctx context.Context, ar *models.RegisterAndUpdateUser) (*models.RegisterAndUpdateUser, error) {
+	ret := _m.Called(ctx, ar)
+
+	var r0 *models.RegisterAndUpdateUser
+	if rf, ok := ret.Get(0).(func(context.Context, *models.RegisterAndUpdateUser) *models.RegisterAndUpdateUser); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RegisterAndUpdateUser)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.RegisterAndUpdateUser) error); ok {
+		r1 = rf(ctx, ar)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteUser provides a mock function with given fields: ctx, id
+func (_m *Usecase) DeleteUser(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableUser provides a mock function with given fields: ctx, id
+func (_m *Usecase) DisableUser(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableUser provides a mock function with given fields: ctx, id
+func (_m *Usecase) EnableUser(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetToken provides a mock function with given fields: ctx, username, password
+func (_m *Usecase) GetToken(ctx context.Context, username string, password string) (*models.GetToken, error) {
+	ret := _m.Called(ctx, username, password)
+
+	var r0 *models.GetToken
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.GetToken); ok {
+		r0 = rf(ctx, username, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, username, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserInfo provides a mock function with given fields: ctx, token
+func (_m *Usecase) GetUserInfo(ctx context.Context, token string) (*models.GetUserInfo, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *models.GetUserInfo
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.GetUserInfo); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetUserInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshToken provides a mock function with given fields: ctx, refreshToken
+func (_m *Usecase) RefreshToken(ctx context.Context, refreshToken string) (*models.GetToken, error) {
+	ret := _m.Called(ctx, refreshToken)
+
+	var r0 *models.GetToken
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.GetToken); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeToken provides a mock function with given fields: ctx, token, tokenTypeHint
+func (_m *Usecase) RevokeToken(ctx context.Context, token string, tokenTypeHint string) error {
+	ret := _m.Called(ctx, token, tokenTypeHint)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, token, tokenTypeHint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUser provides a mock function with given fields: ctx, ar
+func (_m *Usecase) UpdateUser(ctx context.Context, ar *models.RegisterAndUpdateUser) (*models.RegisterAndUpdateUser, error) {
+	ret := _m.Called(ctx, ar)
+
+	var r0 *models.RegisterAndUpdateUser
+	if rf, ok := ret.Get(0).(func(context.Context, *models.RegisterAndUpdateUser) *models.RegisterAndUpdateUser); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RegisterAndUpdateUser)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.RegisterAndUpdateUser) error); ok {
+		r1 = rf(ctx, ar)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,17 @@
+package identityserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/models"
+)
+
+// Repository represent the refresh token store contract.
+// RevokeForAccount revokes the refresh tokens of an account, once its password changed.
+type Repository interface {
+	GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	StoreRefreshToken(ctx context.Context, a *models.RefreshToken) error
+	RevokeRefreshToken(ctx context.Context, tokenHash string) error
+	RevokeForAccount(ctx context.Context, accountType string, accountId string, revokedAt time.Time) error
+}
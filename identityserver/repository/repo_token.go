@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/identityserver"
+	"github.com/models"
+	"github.com/transaction"
+)
+
+type tokenRepository struct {
+	Conn *sql.DB
+}
+
+// NewtokenRepository will create an object that represent the identityserver.Repository interface
+func NewtokenRepository(Conn *sql.DB) identityserver.Repository {
+	return &tokenRepository{Conn}
+}
+
+// conn will return the transaction of the unit of work ctx belongs to, or the connection pool
+func (m *tokenRepository) conn(ctx context.Context) transaction.DBTX {
+	return transaction.Conn(ctx, m.Conn)
+}
+
+func (m *tokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	query := `SELECT token_hash, account_id, account_type, created_date, revoked_date, is_revoked FROM refresh_tokens WHERE token_hash = ?`
+
+	t := new(models.RefreshToken)
+	err := m.conn(ctx).QueryRowContext(ctx, query, tokenHash).Scan(
+		&t.TokenHash,
+		&t.AccountId,
+		&t.AccountType,
+		&t.CreatedDate,
+		&t.RevokedDate,
+		&t.IsRevoked,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return t, nil
+}
+
+func (m *tokenRepository) StoreRefreshToken(ctx context.Context, a *models.RefreshToken) error {
+	query := `INSERT refresh_tokens SET token_hash=? , account_id=? , account_type=? , created_date=? , revoked_date=? , is_revoked=?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, a.TokenHash, a.AccountId, a.AccountType, a.CreatedDate, nil, 0)
+	return err
+}
+
+func (m *tokenRepository) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
+	query := `UPDATE refresh_tokens SET revoked_date=? , is_revoked=? WHERE token_hash = ? AND is_revoked = 0`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, time.Now(), 1, tokenHash)
+	return err
+}
+
+// RevokeForAccount will revoke every refresh token of the account that was not revoked yet
+func (m *tokenRepository) RevokeForAccount(ctx context.Context, accountType string, accountId string, revokedAt time.Time) error {
+	query := `UPDATE refresh_tokens SET revoked_date=? , is_revoked=? WHERE account_type = ? AND account_id = ? AND is_revoked = 0`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, revokedAt, 1, accountType, accountId)
+	return err
+}
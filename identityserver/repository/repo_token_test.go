@@ -0,0 +1,114 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	tokenRepo "github.com/identityserver/repository"
+	"github.com/models"
+)
+
+func TestGetRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "SELECT token_hash, account_id, account_type, created_date, revoked_date, is_revoked FROM refresh_tokens WHERE token_hash = \\?"
+
+	t.Run("found", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"token_hash", "account_id", "account_type", "created_date", "revoked_date", "is_revoked"}).
+			AddRow("hash", "u1", models.PrincipalUser, time.Now(), nil, 0)
+		mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(rows)
+
+		a := tokenRepo.NewtokenRepository(db)
+		token, err := a.GetRefreshToken(context.TODO(), "hash")
+		require.NoError(t, err)
+		assert.Equal(t, "u1", token.AccountId)
+		assert.Equal(t, models.PrincipalUser, token.AccountType)
+		assert.Nil(t, token.RevokedDate)
+	})
+
+	t.Run("not-found", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"token_hash", "account_id", "account_type", "created_date", "revoked_date", "is_revoked"})
+		mock.ExpectQuery(query).WithArgs("missing").WillReturnRows(rows)
+
+		a := tokenRepo.NewtokenRepository(db)
+		_, err := a.GetRefreshToken(context.TODO(), "missing")
+		assert.Equal(t, models.ErrNotFound, err)
+	})
+}
+
+func TestStoreRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	now := time.Now()
+	token := &models.RefreshToken{TokenHash: "hash", AccountId: "m1", AccountType: models.PrincipalMerchant, CreatedDate: now}
+
+	query := "INSERT refresh_tokens SET token_hash=\\? , account_id=\\? , account_type=\\? , created_date=\\? , revoked_date=\\? , is_revoked=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs("hash", "m1", models.PrincipalMerchant, now, nil, 0).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := tokenRepo.NewtokenRepository(db)
+	err = a.StoreRefreshToken(context.TODO(), token)
+	assert.NoError(t, err)
+}
+
+func TestRevokeRefreshToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "UPDATE refresh_tokens SET revoked_date=\\? , is_revoked=\\? WHERE token_hash = \\? AND is_revoked = 0"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(sqlmock.AnyArg(), 1, "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := tokenRepo.NewtokenRepository(db)
+	err = a.RevokeRefreshToken(context.TODO(), "hash")
+	assert.NoError(t, err)
+}
+
+func TestRevokeForAccount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	now := time.Now()
+	query := "UPDATE refresh_tokens SET revoked_date=\\? , is_revoked=\\? WHERE account_type = \\? AND account_id = \\? AND is_revoked = 0"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(now, 1, models.PrincipalUser, "u1").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	a := tokenRepo.NewtokenRepository(db)
+	err = a.RevokeForAccount(context.TODO(), models.PrincipalUser, "u1", now)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,19 @@
+package identityserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	// TokenTypeAccess is the token_type_hint of an access token
+	TokenTypeAccess = "access_token"
+	// TokenTypeRefresh is the token_type_hint of a refresh token
+	TokenTypeRefresh = "refresh_token"
+)
+
+// HashToken will return the hex sha256 of a token, the form under which refresh tokens are stored
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
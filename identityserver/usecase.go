@@ -1,12 +1,20 @@
 package identityserver
 
 import (
+	"context"
+
 	"github.com/models"
 )
 
+// Usecase represent the identity server client contract
 type Usecase interface {
-	UpdateUser(ar *models.RegisterAndUpdateUser) (*models.RegisterAndUpdateUser,error)
-	CreateUser(ar *models.RegisterAndUpdateUser) (*models.RegisterAndUpdateUser,error)
-	GetUserInfo(token string) (*models.GetUserInfo, error)
-	GetToken(username string, password string) (*models.GetToken, error)
-}
\ No newline at end of file
+	UpdateUser(ctx context.Context, ar *models.RegisterAndUpdateUser) (*models.RegisterAndUpdateUser, error)
+	CreateUser(ctx context.Context, ar *models.RegisterAndUpdateUser) (*models.RegisterAndUpdateUser, error)
+	GetUserInfo(ctx context.Context, token string) (*models.GetUserInfo, error)
+	GetToken(ctx context.Context, username string, password string) (*models.GetToken, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*models.GetToken, error)
+	RevokeToken(ctx context.Context, token string, tokenTypeHint string) error
+	DisableUser(ctx context.Context, id string) error
+	EnableUser(ctx context.Context, id string) error
+	DeleteUser(ctx context.Context, id string) error
+}
@@ -1 +1,190 @@
 package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/identityserver"
+	"github.com/identityserver/usecase"
+	"github.com/models"
+)
+
+func TestGetToken(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/connect/token", r.URL.Path)
+			assert.Equal(t, "Basic secret", r.Header.Get("Authorization"))
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "password", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "john@mail.com", r.PostForm.Get("username"))
+			w.Write([]byte(`{"access_token":"abc","expires_in":3600,"token_type":"Bearer"}`))
+		}))
+		defer srv.Close()
+
+		u := usecase.NewidentityserverUsecase(srv.URL, "secret", srv.Client(), nil)
+		token, err := u.GetToken(context.TODO(), "john@mail.com", "pass")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", token.AccessToken)
+		assert.Equal(t, 3600, token.ExpiresIn)
+	})
+
+	t.Run("invalid-grant", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+		}))
+		defer srv.Close()
+
+		u := usecase.NewidentityserverUsecase(srv.URL, "secret", srv.Client(), nil)
+		_, err := u.GetToken(context.TODO(), "john@mail.com", "wrong")
+		assert.Equal(t, models.ErrUnAuthorize, err)
+	})
+}
+
+func TestRefreshToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/connect/token", r.URL.Path)
+		assert.Equal(t, "Basic secret", r.Header.Get("Authorization"))
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "old-refresh", r.PostForm.Get("refresh_token"))
+		w.Write([]byte(`{"access_token":"abc","expires_in":3600,"token_type":"Bearer","refresh_token":"new-refresh"}`))
+	}))
+	defer srv.Close()
+
+	u := usecase.NewidentityserverUsecase(srv.URL, "secret", srv.Client(), nil)
+	token, err := u.RefreshToken(context.TODO(), "old-refresh")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", token.AccessToken)
+	assert.Equal(t, "new-refresh", token.RefreshToken)
+}
+
+func TestRevokeToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/connect/revocation", r.URL.Path)
+		assert.Equal(t, "Basic secret", r.Header.Get("Authorization"))
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "refresh", r.PostForm.Get("token"))
+		assert.Equal(t, identityserver.TokenTypeRefresh, r.PostForm.Get("token_type_hint"))
+	}))
+	defer srv.Close()
+
+	u := usecase.NewidentityserverUsecase(srv.URL, "secret", srv.Client(), nil)
+	err := u.RevokeToken(context.TODO(), "refresh", identityserver.TokenTypeRefresh)
+	assert.NoError(t, err)
+}
+
+func TestGetUserInfoUnauthorized(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer expired", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	u := usecase.NewidentityserverUsecase(srv.URL, "secret", srv.Client(), nil)
+	_, err := u.GetUserInfo(context.TODO(), "expired")
+	assert.Equal(t, models.ErrUnAuthorize, err)
+}
+
+func TestCreateUser(t *testing.T) {
+	t.Run("conflict", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+		}))
+		defer srv.Close()
+
+		u := usecase.NewidentityserverUsecase(srv.URL, "secret", srv.Client(), nil)
+		_, err := u.CreateUser(context.TODO(), &models.RegisterAndUpdateUser{Email: "john@mail.com"})
+		assert.Equal(t, models.ErrConflict, err)
+	})
+
+	t.Run("validation-failure", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`[{"code":"PasswordTooShort","description":"Passwords must be at least 6 characters."}]`))
+		}))
+		defer srv.Close()
+
+		u := usecase.NewidentityserverUsecase(srv.URL, "secret", srv.Client(), nil)
+		_, err := u.CreateUser(context.TODO(), &models.RegisterAndUpdateUser{Email: "john@mail.com"})
+
+		var validationErr *identityserver.ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, "Passwords must be at least 6 characters.", validationErr.Message)
+		assert.True(t, errors.Is(err, models.ErrBadParamInput))
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		srv.Close()
+
+		u := usecase.NewidentityserverUsecase(srv.URL, "secret", nil, nil)
+		_, err := u.CreateUser(context.TODO(), &models.RegisterAndUpdateUser{Email: "john@mail.com"})
+		assert.True(t, errors.Is(err, identityserver.ErrUnreachable))
+	})
+}
+
+func TestDisableUser(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/connect/disable-user", r.URL.Path)
+		assert.Equal(t, "Basic secret", r.Header.Get("Authorization"))
+			assert.Equal(t, "Basic secret", r.Header.Get("Authorization"))
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"id":"u1"}`, string(body))
+		}))
+		defer srv.Close()
+
+		u := usecase.NewidentityserverUsecase(srv.URL, "secret", srv.Client(), nil)
+		assert.NoError(t, u.DisableUser(context.TODO(), "u1"))
+	})
+
+	t.Run("unknown-user", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+
+		u := usecase.NewidentityserverUsecase(srv.URL, "secret", srv.Client(), nil)
+		assert.Error(t, u.DisableUser(context.TODO(), "u1"))
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/connect/delete-user", r.URL.Path)
+		assert.Equal(t, "Basic secret", r.Header.Get("Authorization"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"id":"u1"}`, string(body))
+	}))
+	defer srv.Close()
+
+	u := usecase.NewidentityserverUsecase(srv.URL, "secret", srv.Client(), nil)
+	assert.NoError(t, u.DeleteUser(context.TODO(), "u1"))
+}
+
+func TestUpdateUserContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+
+	u := usecase.NewidentityserverUsecase(srv.URL, "secret", srv.Client(), nil)
+	_, err := u.UpdateUser(ctx, &models.RegisterAndUpdateUser{Id: "1"})
+	assert.True(t, errors.Is(err, identityserver.ErrUnreachable))
+}
@@ -0,0 +1,338 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/models"
+)
+
+var (
+	// errNotJWT means the token is opaque (a reference token) and has to be checked by the userinfo endpoint
+	errNotJWT = errors.New("token is not a JWT")
+	// errKeysUnavailable means the discovery document or the JWKS could not be loaded
+	errKeysUnavailable = errors.New("identity server signing keys are unavailable")
+)
+
+// JWTOptions represent how the access tokens are verified locally against the identity server JWKS
+type JWTOptions struct {
+	// Audience is the expected aud claim, it is not checked when empty
+	Audience string
+	// Issuer overrides the issuer announced by the discovery document
+	Issuer string
+	// Leeway is the clock skew tolerated on exp and nbf
+	Leeway time.Duration
+	// MinRefreshInterval limits how often an unknown kid triggers a JWKS download
+	MinRefreshInterval time.Duration
+}
+
+type discoveryDocument struct {
+	Issuer             string `json:"issuer"`
+	JwksURI            string `json:"jwks_uri"`
+	RevocationEndpoint string `json:"revocation_endpoint"`
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type tokenClaims struct {
+	Issuer            string          `json:"iss"`
+	Subject           string          `json:"sub"`
+	Audience          json.RawMessage `json:"aud"`
+	ExpiresAt         int64           `json:"exp"`
+	NotBefore         int64           `json:"nbf"`
+	Email             string          `json:"email"`
+	EmailVerified     interface{}     `json:"email_verified"`
+	Name              string          `json:"name"`
+	PreferredUsername string          `json:"preferred_username"`
+	GivenName         string          `json:"given_name"`
+	FamilyName        string          `json:"family_name"`
+	Website           string          `json:"website"`
+}
+
+// jwksVerifier will verify RS256/RS384/RS512 access tokens with the keys published by the identity server.
+// Keys are downloaded lazily and downloaded again when a token is signed with an unknown kid.
+type jwksVerifier struct {
+	baseUrl string
+	client  *http.Client
+	opts    JWTOptions
+	now     func() time.Time
+
+	mu          sync.RWMutex
+	discovery   *discoveryDocument
+	keys        map[string]*rsa.PublicKey
+	lastRefresh time.Time
+}
+
+func newJWKSVerifier(baseUrl string, client *http.Client, opts JWTOptions) *jwksVerifier {
+	if opts.MinRefreshInterval <= 0 {
+		opts.MinRefreshInterval = time.Minute
+	}
+	return &jwksVerifier{
+		baseUrl: baseUrl,
+		client:  client,
+		opts:    opts,
+		now:     time.Now,
+	}
+}
+
+// verify will check the signature, exp, nbf, iss and aud of the token and return its claims
+func (v *jwksVerifier) verify(ctx context.Context, token string) (*tokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errNotJWT
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, errNotJWT
+	}
+	hash, ok := map[string]crypto.Hash{"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512}[header.Alg]
+	if !ok {
+		return nil, models.ErrUnAuthorize
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, models.ErrUnAuthorize
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, hash, hasher.Sum(nil), signature); err != nil {
+		return nil, models.ErrUnAuthorize
+	}
+
+	claims := &tokenClaims{}
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, models.ErrUnAuthorize
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *jwksVerifier) validateClaims(claims *tokenClaims) error {
+	now := v.now()
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(v.opts.Leeway)) {
+		return models.ErrUnAuthorize
+	}
+	if claims.NotBefore != 0 && now.Add(v.opts.Leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return models.ErrUnAuthorize
+	}
+
+	issuer := v.opts.Issuer
+	if issuer == "" {
+		v.mu.RLock()
+		if v.discovery != nil {
+			issuer = v.discovery.Issuer
+		}
+		v.mu.RUnlock()
+	}
+	if issuer != "" && strings.TrimRight(claims.Issuer, "/") != strings.TrimRight(issuer, "/") {
+		return models.ErrUnAuthorize
+	}
+
+	if v.opts.Audience != "" && !audienceContains(claims.Audience, v.opts.Audience) {
+		return models.ErrUnAuthorize
+	}
+	return nil
+}
+
+// key will return the key of the given kid, downloading the JWKS again on a cache miss
+func (v *jwksVerifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	v.mu.RLock()
+	key, ok := v.lookup(kid)
+	lastRefresh := v.lastRefresh
+	v.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	if !lastRefresh.IsZero() && v.now().Sub(lastRefresh) < v.opts.MinRefreshInterval {
+		return nil, models.ErrUnAuthorize
+	}
+	if err := v.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, models.ErrUnAuthorize
+}
+
+// lookup will find the key of the given kid in the cache, v.mu must be held.
+// A single key is used for tokens without kid.
+func (v *jwksVerifier) lookup(kid string) (*rsa.PublicKey, bool) {
+	if key, ok := v.keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func (v *jwksVerifier) refresh(ctx context.Context) error {
+	doc, err := v.discoveryDocument(ctx)
+	if err != nil {
+		return err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := v.getJSON(ctx, doc.JwksURI, &set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := rsaPublicKey(jwk)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.lastRefresh = v.now()
+	v.mu.Unlock()
+	return nil
+}
+
+// discoveryDocument will return the cached OpenID discovery document, loading it on first use
+func (v *jwksVerifier) discoveryDocument(ctx context.Context) (*discoveryDocument, error) {
+	v.mu.RLock()
+	doc := v.discovery
+	v.mu.RUnlock()
+	if doc != nil {
+		return doc, nil
+	}
+
+	doc = &discoveryDocument{}
+	if err := v.getJSON(ctx, v.baseUrl+"/.well-known/openid-configuration", doc); err != nil {
+		return nil, err
+	}
+	if doc.JwksURI == "" {
+		return nil, errKeysUnavailable
+	}
+
+	v.mu.Lock()
+	v.discovery = doc
+	v.mu.Unlock()
+	return doc, nil
+}
+
+func (v *jwksVerifier) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errKeysUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s responded with status %d", errKeysUnavailable, url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: %v", errKeysUnavailable, err)
+	}
+	return nil
+}
+
+func (c *tokenClaims) userInfo() *models.GetUserInfo {
+	username := c.PreferredUsername
+	if username == "" {
+		username = c.Email
+	}
+	verified, _ := c.EmailVerified.(bool)
+	if s, ok := c.EmailVerified.(string); ok {
+		verified = s == "true"
+	}
+	return &models.GetUserInfo{
+		Id:            c.Subject,
+		Username:      username,
+		Name:          c.Name,
+		GivenName:     c.GivenName,
+		FamilyName:    c.FamilyName,
+		Email:         c.Email,
+		EmailVerified: verified,
+		Website:       c.Website,
+	}
+}
+
+func audienceContains(raw json.RawMessage, audience string) bool {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return single == audience
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		for _, aud := range list {
+			if aud == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func rsaPublicKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 {
+		return nil, errors.New("invalid rsa exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+func decodeSegment(segment string, out interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.NewDecoder(bytes.NewReader(raw)).Decode(out)
+}
@@ -0,0 +1,207 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/identityserver/usecase"
+	"github.com/models"
+)
+
+type fakeIdentityServer struct {
+	*httptest.Server
+	mu           sync.Mutex
+	keys         map[string]*rsa.PrivateKey
+	userinfoHits int32
+}
+
+func newFakeIdentityServer(t *testing.T, kids ...string) *fakeIdentityServer {
+	is := &fakeIdentityServer{keys: map[string]*rsa.PrivateKey{}}
+	for _, kid := range kids {
+		is.addKey(t, kid)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   is.URL,
+			"jwks_uri": is.URL + "/.well-known/openid-configuration/jwks",
+		})
+	})
+	mux.HandleFunc("/.well-known/openid-configuration/jwks", func(w http.ResponseWriter, r *http.Request) {
+		is.mu.Lock()
+		defer is.mu.Unlock()
+		keys := []map[string]string{}
+		for kid, key := range is.keys {
+			keys = append(keys, map[string]string{
+				"kid": kid,
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	})
+	mux.HandleFunc("/connect/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&is.userinfoHits, 1)
+		w.Write([]byte(`{"id":"sub-1","username":"john","email":"john@mail.com"}`))
+	})
+	is.Server = httptest.NewServer(mux)
+	return is
+}
+
+func (is *fakeIdentityServer) addKey(t *testing.T, kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	is.mu.Lock()
+	is.keys[kid] = key
+	is.mu.Unlock()
+}
+
+func (is *fakeIdentityServer) sign(t *testing.T, kid string, claims map[string]interface{}) string {
+	return is.signHeader(t, kid, map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid}, claims)
+}
+
+// signWithoutKid will sign with the key of kid and leave the kid out of the header
+func (is *fakeIdentityServer) signWithoutKid(t *testing.T, kid string, claims map[string]interface{}) string {
+	return is.signHeader(t, kid, map[string]string{"alg": "RS256", "typ": "JWT"}, claims)
+}
+
+func (is *fakeIdentityServer) signHeader(t *testing.T, kid string, fields map[string]string, claims map[string]interface{}) string {
+	is.mu.Lock()
+	key := is.keys[kid]
+	is.mu.Unlock()
+
+	header, _ := json.Marshal(fields)
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	sum := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	require.NoError(t, err)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (is *fakeIdentityServer) claims(overrides map[string]interface{}) map[string]interface{} {
+	claims := map[string]interface{}{
+		"iss":                is.URL,
+		"aud":                []string{"cgo-api", "openid"},
+		"sub":                "sub-1",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"nbf":                time.Now().Add(-time.Minute).Unix(),
+		"email":              "john@mail.com",
+		"preferred_username": "john",
+	}
+	for k, v := range overrides {
+		if v == nil {
+			delete(claims, k)
+			continue
+		}
+		claims[k] = v
+	}
+	return claims
+}
+
+func TestGetUserInfoLocalValidation(t *testing.T) {
+	is := newFakeIdentityServer(t, "key-1")
+	defer is.Close()
+	u := usecase.NewidentityserverUsecase(is.URL, "secret", is.Client(), &usecase.JWTOptions{Audience: "cgo-api"})
+
+	t.Run("valid", func(t *testing.T) {
+		info, err := u.GetUserInfo(context.TODO(), is.sign(t, "key-1", is.claims(nil)))
+		require.NoError(t, err)
+		assert.Equal(t, "sub-1", info.Id)
+		assert.Equal(t, "john", info.Username)
+		assert.Equal(t, "john@mail.com", info.Email)
+		assert.Equal(t, int32(0), atomic.LoadInt32(&is.userinfoHits))
+	})
+
+	rejected := map[string]map[string]interface{}{
+		"expired":        {"exp": time.Now().Add(-time.Hour).Unix()},
+		"not-yet-valid":  {"nbf": time.Now().Add(time.Hour).Unix()},
+		"wrong-audience": {"aud": "another-api"},
+		"wrong-issuer":   {"iss": "https://evil.example.com"},
+	}
+	for name, overrides := range rejected {
+		overrides := overrides
+		t.Run(name, func(t *testing.T) {
+			_, err := u.GetUserInfo(context.TODO(), is.sign(t, "key-1", is.claims(overrides)))
+			assert.Equal(t, models.ErrUnAuthorize, err)
+		})
+	}
+
+	t.Run("tampered", func(t *testing.T) {
+		token := strings.Split(is.sign(t, "key-1", is.claims(nil)), ".")
+		other := strings.Split(is.sign(t, "key-1", is.claims(map[string]interface{}{"email": "admin@mail.com"})), ".")
+		forged := other[0] + "." + other[1] + "." + token[2]
+		_, err := u.GetUserInfo(context.TODO(), forged)
+		assert.Equal(t, models.ErrUnAuthorize, err)
+	})
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&is.userinfoHits))
+}
+
+func TestGetUserInfoFallback(t *testing.T) {
+	is := newFakeIdentityServer(t, "key-1")
+	defer is.Close()
+	u := usecase.NewidentityserverUsecase(is.URL, "secret", is.Client(), &usecase.JWTOptions{})
+
+	t.Run("opaque-token", func(t *testing.T) {
+		info, err := u.GetUserInfo(context.TODO(), "reference-token")
+		require.NoError(t, err)
+		assert.Equal(t, "john@mail.com", info.Email)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&is.userinfoHits))
+	})
+
+	t.Run("token-without-profile", func(t *testing.T) {
+		token := is.sign(t, "key-1", is.claims(map[string]interface{}{"email": nil}))
+		info, err := u.GetUserInfo(context.TODO(), token)
+		require.NoError(t, err)
+		assert.Equal(t, "john@mail.com", info.Email)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&is.userinfoHits))
+	})
+}
+
+func TestGetUserInfoKeyRotation(t *testing.T) {
+	is := newFakeIdentityServer(t, "key-1")
+	defer is.Close()
+	u := usecase.NewidentityserverUsecase(is.URL, "secret", is.Client(), &usecase.JWTOptions{MinRefreshInterval: time.Nanosecond})
+
+	_, err := u.GetUserInfo(context.TODO(), is.sign(t, "key-1", is.claims(nil)))
+	require.NoError(t, err)
+
+	is.addKey(t, "key-2")
+	info, err := u.GetUserInfo(context.TODO(), is.sign(t, "key-2", is.claims(nil)))
+	require.NoError(t, err)
+	assert.Equal(t, "sub-1", info.Id)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&is.userinfoHits))
+}
+
+func TestGetUserInfoWithoutKid(t *testing.T) {
+	is := newFakeIdentityServer(t, "key-1")
+	defer is.Close()
+	u := usecase.NewidentityserverUsecase(is.URL, "secret", is.Client(), &usecase.JWTOptions{MinRefreshInterval: time.Hour})
+
+	// the second token is checked against the cached key rather than refused by the refresh throttle
+	for i := 0; i < 2; i++ {
+		info, err := u.GetUserInfo(context.TODO(), is.signWithoutKid(t, "key-1", is.claims(nil)))
+		require.NoError(t, err)
+		assert.Equal(t, "sub-1", info.Id)
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&is.userinfoHits))
+}
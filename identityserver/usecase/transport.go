@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TLSOptions represent how the identity server is trusted and how this service authenticates to it
+type TLSOptions struct {
+	// CAFile is a PEM bundle used instead of the system roots to verify the identity server
+	CAFile string
+	// PinnedSHA256 lists the base64 sha256 of the accepted SubjectPublicKeyInfo (optionally prefixed by "sha256//")
+	PinnedSHA256 []string
+	// ClientCertFile and ClientKeyFile enable mTLS when both are set
+	ClientCertFile string
+	ClientKeyFile  string
+	// InsecureSkipVerify disables every verification, only meant for local development
+	InsecureSkipVerify bool
+}
+
+// NewHTTPClient will create the http.Client shared by every identity server call
+func NewHTTPClient(opts TLSOptions, timeout time.Duration) (*http.Client, error) {
+	tlsConfig, err := newTLSConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
+
+func newTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if opts.InsecureSkipVerify {
+		logrus.Warn("!!! TLS verification of the identity server is DISABLED. " +
+			"Tokens and passwords can be intercepted, never run this mode outside local development !!!")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	if opts.CAFile != "" {
+		pem, err := ioutil.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read identity server ca bundle: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", opts.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.ClientCertFile != "" || opts.ClientKeyFile != "" {
+		if opts.ClientCertFile == "" || opts.ClientKeyFile == "" {
+			return nil, errors.New("both the client certificate and key are required for mTLS")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load identity server client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(opts.PinnedSHA256) > 0 {
+		pins := make(map[string]bool, len(opts.PinnedSHA256))
+		for _, pin := range opts.PinnedSHA256 {
+			pins[strings.TrimPrefix(strings.TrimSpace(pin), "sha256//")] = true
+		}
+		tlsConfig.VerifyPeerCertificate = verifyPins(pins)
+	}
+
+	return tlsConfig, nil
+}
+
+// verifyPins will accept the connection only if one of the presented certificates matches a pin.
+// It runs after the regular chain verification, so pinning narrows the trust instead of replacing it.
+func verifyPins(pins map[string]bool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		for _, raw := range rawCerts {
+			cert, err := x509.ParseCertificate(raw)
+			if err != nil {
+				return err
+			}
+			sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+			if pins[base64.StdEncoding.EncodeToString(sum[:])] {
+				return nil
+			}
+		}
+		return errors.New("identity server certificate does not match any pinned key")
+	}
+}
@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/identityserver/usecase"
+)
+
+func writeTemp(t *testing.T, name string, content []byte) string {
+	dir, err := ioutil.TempDir("", "istls")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, content, 0600))
+	return path
+}
+
+func serverCAFile(t *testing.T, srv *httptest.Server) string {
+	return writeTemp(t, "ca.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+}
+
+func serverPin(srv *httptest.Server) string {
+	sum := sha256.Sum256(srv.Certificate().RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func newTLSServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestNewHTTPClientVerifiesByDefault(t *testing.T) {
+	srv := newTLSServer()
+	defer srv.Close()
+
+	client, err := usecase.NewHTTPClient(usecase.TLSOptions{}, time.Second)
+	require.NoError(t, err)
+	_, err = client.Get(srv.URL)
+	assert.Error(t, err)
+}
+
+func TestNewHTTPClientCustomCA(t *testing.T) {
+	srv := newTLSServer()
+	defer srv.Close()
+
+	client, err := usecase.NewHTTPClient(usecase.TLSOptions{CAFile: serverCAFile(t, srv)}, time.Second)
+	require.NoError(t, err)
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestNewHTTPClientPinning(t *testing.T) {
+	srv := newTLSServer()
+	defer srv.Close()
+
+	t.Run("match", func(t *testing.T) {
+		client, err := usecase.NewHTTPClient(usecase.TLSOptions{
+			CAFile:       serverCAFile(t, srv),
+			PinnedSHA256: []string{"sha256//" + serverPin(srv)},
+		}, time.Second)
+		require.NoError(t, err)
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		client, err := usecase.NewHTTPClient(usecase.TLSOptions{
+			CAFile:       serverCAFile(t, srv),
+			PinnedSHA256: []string{base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))},
+		}, time.Second)
+		require.NoError(t, err)
+		_, err = client.Get(srv.URL)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewHTTPClientInsecure(t *testing.T) {
+	srv := newTLSServer()
+	defer srv.Close()
+
+	client, err := usecase.NewHTTPClient(usecase.TLSOptions{InsecureSkipVerify: true}, time.Second)
+	require.NoError(t, err)
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestNewHTTPClientMutualTLS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "cgo-api"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	clientCert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "cgo-api", r.TLS.PeerCertificates[0].Subject.CommonName)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+
+	opts := usecase.TLSOptions{CAFile: serverCAFile(t, srv)}
+	client, err := usecase.NewHTTPClient(opts, time.Second)
+	require.NoError(t, err)
+	_, err = client.Get(srv.URL)
+	assert.Error(t, err)
+
+	opts.ClientCertFile = writeTemp(t, "client.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	opts.ClientKeyFile = writeTemp(t, "client.key", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+	client, err = usecase.NewHTTPClient(opts, time.Second)
+	require.NoError(t, err)
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
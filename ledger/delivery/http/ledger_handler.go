@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/ledger"
+	"github.com/middleware"
+	"github.com/models"
+)
+
+// headerIdempotencyKey lets the caller send the idempotency key of an entry as a header instead of in the body
+const headerIdempotencyKey = "Idempotency-Key"
+
+// errPayoutEntry refuses payout entries from the admins, they are only recorded by approving a payout
+var errPayoutEntry = models.NewBadParam("payout entries are recorded by approving a payout")
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// ledgerHandler  represent the httphandler for the merchant ledger
+type ledgerHandler struct {
+	LedgerUsecase ledger.Usecase
+}
+
+// NewledgerHandler will initialize the merchants/:id/transactions resources endpoint
+func NewledgerHandler(e *echo.Echo, us ledger.Usecase, mw *middleware.GoMiddleware) {
+	handler := &ledgerHandler{
+		LedgerUsecase: us,
+	}
+	adminOnly := mw.Authorize(middleware.Policy{Roles: []string{models.RoleAdmin}})
+	ownerOrAdmin := mw.Authorize(middleware.Policy{
+		Roles:      []string{models.RoleMerchant, models.RoleAdmin},
+		OwnerParam: "id",
+	})
+	e.GET("/merchants/:id/transactions", handler.FetchTransaction, mw.Authenticate, ownerOrAdmin)
+	e.POST("/merchants/:id/transactions", handler.Record, mw.Authenticate, adminOnly)
+	e.GET("/merchants/:id/balance", handler.GetBalance, mw.Authenticate, ownerOrAdmin)
+}
+
+// FetchTransaction will list the ledger entries of the merchant, the most recent first
+func (a *ledgerHandler) FetchTransaction(c echo.Context) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	list, nextCursor, err := a.LedgerUsecase.Fetch(ctx, c.Param("id"), cursor, int64(num))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, list)
+}
+
+func isRequestValid(m *models.NewCommandMerchantTransaction) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Record will add an entry to the ledger of the merchant by given request body, except a payout
+func (a *ledgerHandler) Record(c echo.Context) error {
+	var command models.NewCommandMerchantTransaction
+	err := c.Bind(&command)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+	}
+	command.MerchantId = c.Param("id")
+	if key := c.Request().Header.Get(headerIdempotencyKey); key != "" {
+		command.IdempotencyKey = key
+	}
+
+	if ok, err := isRequestValid(&command); !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	if command.Type == models.TransactionPayout {
+		return c.JSON(getStatusCode(errPayoutEntry), ResponseError{Message: errPayoutEntry.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := a.LedgerUsecase.Record(ctx, &command, middleware.GetPrincipal(c).Username)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// GetBalance will return the balance of the merchant reconciled against its ledger
+func (a *ledgerHandler) GetBalance(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := a.LedgerUsecase.GetBalance(ctx, c.Param("id"))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	logrus.Error(err)
+	switch {
+	case errors.Is(err, models.ErrInternalServerError):
+		return http.StatusInternalServerError
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrUnAuthorize):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrConflict), errors.Is(err, models.ErrInsufficientBalance):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrBadParamInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
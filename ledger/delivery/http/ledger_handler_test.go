@@ -0,0 +1,88 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	ledgerHttp "github.com/ledger/delivery/http"
+	"github.com/ledger/mocks"
+	"github.com/middleware/middlewaretest"
+	"github.com/models"
+)
+
+func newServer(mockUCase *mocks.Usecase) *echo.Echo {
+	e := echo.New()
+	ledgerHttp.NewledgerHandler(e, mockUCase, middlewaretest.New())
+	return e
+}
+
+func TestFetchTransaction(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Fetch", mock.Anything, "m1", "", int64(5)).
+		Return([]*models.MerchantTransaction{{Id: 12, MerchantId: "m1"}}, "next", nil).Once()
+
+	rec := middlewaretest.Serve(e, httptest.NewRequest(echo.GET, "/merchants/m1/transactions?num=5", nil), middlewaretest.MerchantToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "next", rec.Header().Get("X-Cursor"))
+
+	rec = middlewaretest.Serve(e, httptest.NewRequest(echo.GET, "/merchants/m2/transactions", nil), middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestRecord(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Record", mock.Anything, mock.MatchedBy(func(ar *models.NewCommandMerchantTransaction) bool {
+		return ar.MerchantId == "m1" && ar.Type == models.TransactionCredit && ar.Amount == 2000 && ar.IdempotencyKey == "order-1"
+	}), "admin@mail.com").Return(&models.MerchantTransaction{Id: 11, MerchantId: "m1", BalanceAfter: 2000}, nil).Once()
+	mockUCase.On("Record", mock.Anything, mock.MatchedBy(func(ar *models.NewCommandMerchantTransaction) bool {
+		return ar.Type == models.TransactionDebit
+	}), "admin@mail.com").Return(nil, models.ErrInsufficientBalance).Once()
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(echo.POST, "/merchants/m1/transactions", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("Idempotency-Key", "order-1")
+		return req
+	}
+
+	rec := middlewaretest.Serve(e, newRequest(`{"type":"credit","amount":2000}`), middlewaretest.AdminToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var entry models.MerchantTransaction
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entry))
+	assert.Equal(t, int64(2000), entry.BalanceAfter)
+
+	rec = middlewaretest.Serve(e, newRequest(`{"type":"debit","amount":9000}`), middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = middlewaretest.Serve(e, newRequest(`{"type":"payout","amount":2000}`), middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = middlewaretest.Serve(e, newRequest(`{"type":"bonus","amount":2000}`), middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = middlewaretest.Serve(e, newRequest(`{"type":"credit","amount":2000}`), middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestGetBalance(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("GetBalance", mock.Anything, "m1").
+		Return(&models.MerchantBalance{MerchantId: "m1", Balance: 1500, LedgerBalance: 1500, Reconciled: true}, nil).Once()
+
+	rec := middlewaretest.Serve(e, httptest.NewRequest(echo.GET, "/merchants/m1/balance", nil), middlewaretest.MerchantToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
@@ -0,0 +1,99 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// Fetch provides a mock function with given fields: ctx, merchantId, cursor, num
+func (_m *Repository) Fetch(ctx context.Context, merchantId string, cursor string, num int64) ([]*models.MerchantTransaction, string, error) {
+	ret := _m.Called(ctx, merchantId, cursor, num)
+
+	var r0 []*models.MerchantTransaction
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) []*models.MerchantTransaction); ok {
+		r0 = rf(ctx, merchantId, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.MerchantTransaction)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) string); ok {
+		r1 = rf(ctx, merchantId, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, int64) error); ok {
+		r2 = rf(ctx, merchantId, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByIdempotencyKey provides a mock function with given fields: ctx, merchantId, idempotencyKey
+func (_m *Repository) GetByIdempotencyKey(ctx context.Context, merchantId string, idempotencyKey string) (*models.MerchantTransaction, error) {
+	ret := _m.Called(ctx, merchantId, idempotencyKey)
+
+	var r0 *models.MerchantTransaction
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.MerchantTransaction); ok {
+		r0 = rf(ctx, merchantId, idempotencyKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.MerchantTransaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, merchantId, idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, t
+func (_m *Repository) Insert(ctx context.Context, t *models.MerchantTransaction) error {
+	ret := _m.Called(ctx, t)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.MerchantTransaction) error); ok {
+		r0 = rf(ctx, t)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Sum provides a mock function with given fields: ctx, merchantId
+func (_m *Repository) Sum(ctx context.Context, merchantId string) (int64, error) {
+	ret := _m.Called(ctx, merchantId)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, merchantId)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, merchantId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,87 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Fetch provides a mock function with given fields: ctx, merchantId, cursor, num
+func (_m *Usecase) Fetch(ctx context.Context, merchantId string, cursor string, num int64) ([]*models.MerchantTransaction, string, error) {
+	ret := _m.Called(ctx, merchantId, cursor, num)
+
+	var r0 []*models.MerchantTransaction
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) []*models.MerchantTransaction); ok {
+		r0 = rf(ctx, merchantId, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.MerchantTransaction)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) string); ok {
+		r1 = rf(ctx, merchantId, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, int64) error); ok {
+		r2 = rf(ctx, merchantId, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetBalance provides a mock function with given fields: ctx, merchantId
+func (_m *Usecase) GetBalance(ctx context.Context, merchantId string) (*models.MerchantBalance, error) {
+	ret := _m.Called(ctx, merchantId)
+
+	var r0 *models.MerchantBalance
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.MerchantBalance); ok {
+		r0 = rf(ctx, merchantId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.MerchantBalance)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, merchantId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Record provides a mock function with given fields: ctx, ar, user
+func (_m *Usecase) Record(ctx context.Context, ar *models.NewCommandMerchantTransaction, user string) (*models.MerchantTransaction, error) {
+	ret := _m.Called(ctx, ar, user)
+
+	var r0 *models.MerchantTransaction
+	if rf, ok := ret.Get(0).(func(context.Context, *models.NewCommandMerchantTransaction, string) *models.MerchantTransaction); ok {
+		r0 = rf(ctx, ar, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.MerchantTransaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.NewCommandMerchantTransaction, string) error); ok {
+		r1 = rf(ctx, ar, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
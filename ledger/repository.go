@@ -0,0 +1,16 @@
+package ledger
+
+import (
+	"context"
+
+	"github.com/models"
+)
+
+// Repository represent the merchant ledger's repository contract.
+// Entries are only ever inserted, a correction is recorded as a new entry.
+type Repository interface {
+	Fetch(ctx context.Context, merchantId string, cursor string, num int64) (res []*models.MerchantTransaction, nextCursor string, err error)
+	GetByIdempotencyKey(ctx context.Context, merchantId string, idempotencyKey string) (*models.MerchantTransaction, error)
+	Insert(ctx context.Context, t *models.MerchantTransaction) error
+	Sum(ctx context.Context, merchantId string) (int64, error)
+}
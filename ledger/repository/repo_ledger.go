@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+
+	"github.com/ledger"
+	"github.com/models"
+	"github.com/mysqlerr"
+	"github.com/transaction"
+)
+
+// transactionColumns lists the columns in the order they are scanned by fetch
+const transactionColumns = `id, merchant_id, type, amount, balance_after, currency, idempotency_key, reference, description, created_by, created_date`
+
+type ledgerRepository struct {
+	Conn *sql.DB
+}
+
+// NewledgerRepository will create an object that represent the ledger.Repository interface
+func NewledgerRepository(Conn *sql.DB) ledger.Repository {
+	return &ledgerRepository{Conn}
+}
+
+// conn will return the transaction of the unit of work ctx belongs to, or the connection pool
+func (m *ledgerRepository) conn(ctx context.Context) transaction.DBTX {
+	return transaction.Conn(ctx, m.Conn)
+}
+
+func (m *ledgerRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*models.MerchantTransaction, error) {
+	rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}()
+
+	result := make([]*models.MerchantTransaction, 0)
+	for rows.Next() {
+		t := new(models.MerchantTransaction)
+		err = rows.Scan(
+			&t.Id,
+			&t.MerchantId,
+			&t.Type,
+			&t.Amount,
+			&t.BalanceAfter,
+			&t.Currency,
+			&t.IdempotencyKey,
+			&t.Reference,
+			&t.Description,
+			&t.CreatedBy,
+			&t.CreatedDate,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+// Fetch will list the entries of the merchant, the most recent first
+func (m *ledgerRepository) Fetch(ctx context.Context, merchantId string, cursor string, num int64) ([]*models.MerchantTransaction, string, error) {
+	query := `SELECT ` + transactionColumns + ` FROM merchant_transactions WHERE merchant_id = ? AND id < ? ORDER BY id DESC LIMIT ?`
+
+	before := int64(math.MaxInt64)
+	if cursor != "" {
+		decodedCursor, err := DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", models.ErrBadParamInput
+		}
+		before = decodedCursor
+	}
+
+	res, err := m.fetch(ctx, query, merchantId, before, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(res) == int(num) {
+		nextCursor = EncodeCursor(res[len(res)-1].Id)
+	}
+
+	return res, nextCursor, nil
+}
+
+func (m *ledgerRepository) GetByIdempotencyKey(ctx context.Context, merchantId string, idempotencyKey string) (*models.MerchantTransaction, error) {
+	query := `SELECT ` + transactionColumns + ` FROM merchant_transactions WHERE merchant_id = ? AND idempotency_key = ?`
+
+	list, err := m.fetch(ctx, query, merchantId, idempotencyKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, models.ErrNotFound
+	}
+	return list[0], nil
+}
+
+func (m *ledgerRepository) Insert(ctx context.Context, a *models.MerchantTransaction) error {
+	query := `INSERT merchant_transactions SET merchant_id=? , type=? , amount=? , balance_after=? , currency=? , idempotency_key=? , reference=? , description=? , created_by=? , created_date=?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	a.CreatedDate = time.Now()
+	res, err := stmt.ExecContext(ctx, a.MerchantId, a.Type, a.Amount, a.BalanceAfter, a.Currency, a.IdempotencyKey,
+		a.Reference, a.Description, a.CreatedBy, a.CreatedDate)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlerr.DuplicateEntry {
+			return models.ErrConflict
+		}
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.Id = lastID
+	return nil
+}
+
+// Sum will return the balance of the merchant according to its entries
+func (m *ledgerRepository) Sum(ctx context.Context, merchantId string) (int64, error) {
+	query := `SELECT COALESCE(SUM(CASE WHEN type = ? THEN amount ELSE -amount END), 0) FROM merchant_transactions WHERE merchant_id = ?`
+
+	var sum int64
+	err := m.conn(ctx).QueryRowContext(ctx, query, models.TransactionCredit, merchantId).Scan(&sum)
+	if err != nil {
+		logrus.Error(err)
+		return 0, err
+	}
+	return sum, nil
+}
+
+// DecodeCursor will decode the id of the last entry of a page
+func DecodeCursor(cursor string) (int64, error) {
+	byt, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(byt), 10, 64)
+}
+
+// EncodeCursor will encode the id of the last entry of a page
+func EncodeCursor(id int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
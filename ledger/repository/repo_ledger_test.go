@@ -0,0 +1,116 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	ledgerRepo "github.com/ledger/repository"
+	"github.com/models"
+)
+
+var transactionColumns = []string{"id", "merchant_id", "type", "amount", "balance_after", "currency", "idempotency_key",
+	"reference", "description", "created_by", "created_date"}
+
+func TestFetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	now := time.Now()
+	rows := sqlmock.NewRows(transactionColumns).
+		AddRow(12, "m1", "debit", 500, 1500, "IDR", "k2", "", "", "admin", now).
+		AddRow(11, "m1", "credit", 2000, 2000, "IDR", "k1", "order-1", "", "admin", now)
+
+	query := "SELECT .+ FROM merchant_transactions WHERE merchant_id = \\? AND id < \\? ORDER BY id DESC LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs("m1", int64(20), int64(2)).WillReturnRows(rows)
+
+	a := ledgerRepo.NewledgerRepository(db)
+	list, nextCursor, err := a.Fetch(context.TODO(), "m1", ledgerRepo.EncodeCursor(20), 2)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, int64(1500), list[0].BalanceAfter)
+	assert.Equal(t, ledgerRepo.EncodeCursor(11), nextCursor)
+
+	_, _, err = a.Fetch(context.TODO(), "m1", "not-a-cursor", 2)
+	assert.Equal(t, models.ErrBadParamInput, err)
+}
+
+func TestGetByIdempotencyKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "SELECT .+ FROM merchant_transactions WHERE merchant_id = \\? AND idempotency_key = \\?"
+	mock.ExpectQuery(query).WithArgs("m1", "k1").WillReturnRows(sqlmock.NewRows(transactionColumns))
+
+	a := ledgerRepo.NewledgerRepository(db)
+	_, err = a.GetByIdempotencyKey(context.TODO(), "m1", "k1")
+	assert.Equal(t, models.ErrNotFound, err)
+}
+
+func TestInsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	entry := &models.MerchantTransaction{MerchantId: "m1", Type: models.TransactionCredit, Amount: 2000, BalanceAfter: 2000,
+		Currency: models.CurrencyIDR, IdempotencyKey: "k1", CreatedBy: "admin"}
+	query := "INSERT merchant_transactions SET merchant_id=\\? , type=\\? , amount=\\? , balance_after=\\? , currency=\\? , idempotency_key=\\? , reference=\\? , description=\\? , created_by=\\? , created_date=\\?"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs("m1", "credit", int64(2000), int64(2000), "IDR", "k1", "", "", "admin", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(11, 1))
+	prep = mock.ExpectPrepare(query)
+	prep.ExpectExec().WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+
+	a := ledgerRepo.NewledgerRepository(db)
+	require.NoError(t, a.Insert(context.TODO(), entry))
+	assert.Equal(t, int64(11), entry.Id)
+
+	err = a.Insert(context.TODO(), entry)
+	assert.Equal(t, models.ErrConflict, err)
+}
+
+func TestSum(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "SELECT COALESCE\\(SUM\\(CASE WHEN type = \\? THEN amount ELSE -amount END\\), 0\\) FROM merchant_transactions WHERE merchant_id = \\?"
+	mock.ExpectQuery(query).WithArgs("credit", "m1").WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(1500))
+
+	a := ledgerRepo.NewledgerRepository(db)
+	sum, err := a.Sum(context.TODO(), "m1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), sum)
+}
@@ -0,0 +1,14 @@
+package ledger
+
+import (
+	"context"
+
+	"github.com/models"
+)
+
+// Usecase represent the merchant ledger's usecases
+type Usecase interface {
+	Record(ctx context.Context, ar *models.NewCommandMerchantTransaction, user string) (*models.MerchantTransaction, error)
+	Fetch(ctx context.Context, merchantId string, cursor string, num int64) ([]*models.MerchantTransaction, string, error)
+	GetBalance(ctx context.Context, merchantId string) (*models.MerchantBalance, error)
+}
@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ledger"
+	"github.com/merchant"
+	"github.com/models"
+	"github.com/transaction"
+)
+
+type ledgerUsecase struct {
+	ledgerRepo     ledger.Repository
+	merchantRepo   merchant.Repository
+	txManager      transaction.Manager
+	contextTimeout time.Duration
+}
+
+// NewledgerUsecase will create new an ledgerUsecase object representation of ledger.Usecase interface
+func NewledgerUsecase(l ledger.Repository, mr merchant.Repository, txManager transaction.Manager, timeout time.Duration) ledger.Usecase {
+	return &ledgerUsecase{
+		ledgerRepo:     l,
+		merchantRepo:   mr,
+		txManager:      txManager,
+		contextTimeout: timeout,
+	}
+}
+
+// Record will add an entry to the merchant ledger and apply it to the merchant balance in one transaction.
+// Recording the same idempotency key again returns the first entry, or models.ErrConflict when the type
+// or the amount differ. Entries taking the balance below zero fail with models.ErrInsufficientBalance.
+func (m ledgerUsecase) Record(c context.Context, ar *models.NewCommandMerchantTransaction, user string) (*models.MerchantTransaction, error) {
+	if !isTransactionType(ar.Type) || ar.Amount <= 0 || ar.IdempotencyKey == "" {
+		return nil, models.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	var result *models.MerchantTransaction
+	err := m.txManager.Do(ctx, func(ctx context.Context) error {
+		// the merchant row stays locked until the end of the transaction, the entries of a merchant are serialized
+		balance, err := m.merchantRepo.GetBalanceForUpdate(ctx, ar.MerchantId)
+		if err != nil {
+			return err
+		}
+
+		existed, err := m.ledgerRepo.GetByIdempotencyKey(ctx, ar.MerchantId, ar.IdempotencyKey)
+		if err == nil {
+			if existed.Type != ar.Type || existed.Amount != ar.Amount {
+				return models.ErrConflict
+			}
+			result = existed
+			return nil
+		}
+		if !errors.Is(err, models.ErrNotFound) {
+			return err
+		}
+
+		entry := &models.MerchantTransaction{
+			MerchantId:     ar.MerchantId,
+			Type:           ar.Type,
+			Amount:         ar.Amount,
+			Currency:       models.CurrencyIDR,
+			IdempotencyKey: ar.IdempotencyKey,
+			Reference:      ar.Reference,
+			Description:    ar.Description,
+			CreatedBy:      user,
+		}
+		entry.BalanceAfter = balance + entry.Delta()
+		if entry.BalanceAfter < 0 {
+			return models.ErrInsufficientBalance
+		}
+		if err := m.merchantRepo.UpdateBalance(ctx, ar.MerchantId, entry.BalanceAfter); err != nil {
+			return err
+		}
+		if err := m.ledgerRepo.Insert(ctx, entry); err != nil {
+			return err
+		}
+		result = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Fetch will list the entries of the merchant ledger, the most recent first
+func (m ledgerUsecase) Fetch(c context.Context, merchantId string, cursor string, num int64) ([]*models.MerchantTransaction, string, error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	return m.ledgerRepo.Fetch(ctx, merchantId, cursor, num)
+}
+
+// GetBalance will return the stored balance of the merchant reconciled against the sum of its ledger entries
+func (m ledgerUsecase) GetBalance(c context.Context, merchantId string) (*models.MerchantBalance, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	existedMerchant, err := m.merchantRepo.GetByIDIncludeDeleted(ctx, merchantId)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := m.ledgerRepo.Sum(ctx, merchantId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &models.MerchantBalance{
+		MerchantId:    merchantId,
+		Balance:       existedMerchant.Balance,
+		LedgerBalance: sum,
+		Currency:      models.CurrencyIDR,
+		Reconciled:    existedMerchant.Balance == sum,
+	}
+	if !res.Reconciled {
+		logrus.WithFields(logrus.Fields{
+			"merchant_id":    merchantId,
+			"balance":        res.Balance,
+			"ledger_balance": res.LedgerBalance,
+		}).Warn("the merchant balance does not match its ledger")
+	}
+	return res, nil
+}
+
+func isTransactionType(t string) bool {
+	switch t {
+	case models.TransactionCredit, models.TransactionDebit, models.TransactionRefund, models.TransactionPayout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledger/mocks"
+	ucase "github.com/ledger/usecase"
+	_merchantMock "github.com/merchant/mocks"
+	"github.com/models"
+	_txMock "github.com/transaction/mocks"
+)
+
+func inTransaction() *_txMock.Manager {
+	mockTx := new(_txMock.Manager)
+	mockTx.On("Do", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+	return mockTx
+}
+
+func TestRecord(t *testing.T) {
+	debit := func() *models.NewCommandMerchantTransaction {
+		return &models.NewCommandMerchantTransaction{MerchantId: "m1", Type: models.TransactionDebit, Amount: 500, IdempotencyKey: "k2"}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockLedgerRepo := new(mocks.Repository)
+		mockMerchantRepo := new(_merchantMock.Repository)
+		mockMerchantRepo.On("GetBalanceForUpdate", mock.Anything, "m1").Return(int64(2000), nil).Once()
+		mockLedgerRepo.On("GetByIdempotencyKey", mock.Anything, "m1", "k2").Return(nil, models.ErrNotFound).Once()
+		mockMerchantRepo.On("UpdateBalance", mock.Anything, "m1", int64(1500)).Return(nil).Once()
+		mockLedgerRepo.On("Insert", mock.Anything, mock.MatchedBy(func(e *models.MerchantTransaction) bool {
+			return e.BalanceAfter == 1500 && e.Currency == models.CurrencyIDR && e.CreatedBy == "admin"
+		})).Return(nil).Once()
+
+		u := ucase.NewledgerUsecase(mockLedgerRepo, mockMerchantRepo, inTransaction(), time.Second*2)
+		res, err := u.Record(context.TODO(), debit(), "admin")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1500), res.BalanceAfter)
+		mockLedgerRepo.AssertExpectations(t)
+		mockMerchantRepo.AssertExpectations(t)
+	})
+
+	t.Run("replayed", func(t *testing.T) {
+		mockLedgerRepo := new(mocks.Repository)
+		mockMerchantRepo := new(_merchantMock.Repository)
+		existed := &models.MerchantTransaction{Id: 12, MerchantId: "m1", Type: models.TransactionDebit, Amount: 500, BalanceAfter: 1500}
+		mockMerchantRepo.On("GetBalanceForUpdate", mock.Anything, "m1").Return(int64(1500), nil).Once()
+		mockLedgerRepo.On("GetByIdempotencyKey", mock.Anything, "m1", "k2").Return(existed, nil).Once()
+
+		u := ucase.NewledgerUsecase(mockLedgerRepo, mockMerchantRepo, inTransaction(), time.Second*2)
+		res, err := u.Record(context.TODO(), debit(), "admin")
+		require.NoError(t, err)
+		assert.Equal(t, existed, res)
+		mockMerchantRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything)
+		mockLedgerRepo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("key-reused", func(t *testing.T) {
+		mockLedgerRepo := new(mocks.Repository)
+		mockMerchantRepo := new(_merchantMock.Repository)
+		existed := &models.MerchantTransaction{Id: 12, MerchantId: "m1", Type: models.TransactionDebit, Amount: 700}
+		mockMerchantRepo.On("GetBalanceForUpdate", mock.Anything, "m1").Return(int64(1500), nil).Once()
+		mockLedgerRepo.On("GetByIdempotencyKey", mock.Anything, "m1", "k2").Return(existed, nil).Once()
+
+		u := ucase.NewledgerUsecase(mockLedgerRepo, mockMerchantRepo, inTransaction(), time.Second*2)
+		_, err := u.Record(context.TODO(), debit(), "admin")
+		assert.Equal(t, models.ErrConflict, err)
+	})
+
+	t.Run("insufficient-balance", func(t *testing.T) {
+		mockLedgerRepo := new(mocks.Repository)
+		mockMerchantRepo := new(_merchantMock.Repository)
+		mockMerchantRepo.On("GetBalanceForUpdate", mock.Anything, "m1").Return(int64(100), nil).Once()
+		mockLedgerRepo.On("GetByIdempotencyKey", mock.Anything, "m1", "k2").Return(nil, models.ErrNotFound).Once()
+
+		u := ucase.NewledgerUsecase(mockLedgerRepo, mockMerchantRepo, inTransaction(), time.Second*2)
+		_, err := u.Record(context.TODO(), debit(), "admin")
+		assert.Equal(t, models.ErrInsufficientBalance, err)
+		mockMerchantRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		u := ucase.NewledgerUsecase(new(mocks.Repository), new(_merchantMock.Repository), new(_txMock.Manager), time.Second*2)
+		for _, ar := range []*models.NewCommandMerchantTransaction{
+			{MerchantId: "m1", Type: "bonus", Amount: 500, IdempotencyKey: "k"},
+			{MerchantId: "m1", Type: models.TransactionCredit, Amount: -500, IdempotencyKey: "k"},
+			{MerchantId: "m1", Type: models.TransactionCredit, Amount: 500},
+		} {
+			_, err := u.Record(context.TODO(), ar, "admin")
+			assert.Equal(t, models.ErrBadParamInput, err)
+		}
+	})
+}
+
+func TestGetBalance(t *testing.T) {
+	mockLedgerRepo := new(mocks.Repository)
+	mockMerchantRepo := new(_merchantMock.Repository)
+	mockMerchantRepo.On("GetByIDIncludeDeleted", mock.Anything, "m1").Return(&models.Merchant{Id: "m1", Balance: 1500}, nil).Once()
+	mockLedgerRepo.On("Sum", mock.Anything, "m1").Return(int64(2000), nil).Once()
+
+	u := ucase.NewledgerUsecase(mockLedgerRepo, mockMerchantRepo, new(_txMock.Manager), time.Second*2)
+	res, err := u.GetBalance(context.TODO(), "m1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), res.Balance)
+	assert.Equal(t, int64(2000), res.LedgerBalance)
+	assert.False(t, res.Reconciled)
+}
@@ -1,71 +1,91 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"net/url"
-	"os"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
 
-	_articleHttpDeliver "github.com/bxcodec/go-clean-arch/article/delivery/http"
-	_articleRepo "github.com/bxcodec/go-clean-arch/article/repository"
-	_articleUcase "github.com/bxcodec/go-clean-arch/article/usecase"
-	_authorRepo "github.com/bxcodec/go-clean-arch/author/repository"
-	"github.com/bxcodec/go-clean-arch/middleware"
+	_articleHttpDeliver "github.com/article/delivery/http"
+	_articleRepo "github.com/article/repository"
+	_articleSearch "github.com/article/search"
+	_articleUcase "github.com/article/usecase"
+	_authorHttpDeliver "github.com/author/delivery/http"
+	_authorLoader "github.com/author/loader"
+	_authorRepo "github.com/author/repository"
+	_authorUcase "github.com/author/usecase"
+	_categoryHttpDeliver "github.com/category/delivery/http"
+	_categoryRepo "github.com/category/repository"
+	_categoryUcase "github.com/category/usecase"
+	"github.com/config"
 	_isHttpDeliver "github.com/identityserver/delivery/http"
+	_isRepo "github.com/identityserver/repository"
 	_isUcase "github.com/identityserver/usecase"
+	_ledgerHttpDeliver "github.com/ledger/delivery/http"
+	_ledgerRepo "github.com/ledger/repository"
+	_ledgerUcase "github.com/ledger/usecase"
 	_merchantHttpDeliver "github.com/merchant/delivery/http"
 	_merchantRepo "github.com/merchant/repository"
 	_merchantUcase "github.com/merchant/usecase"
+	"github.com/middleware"
+	"github.com/models"
+	"github.com/notifier"
+	"github.com/notifier/memory"
+	"github.com/notifier/sms"
+	"github.com/notifier/smtp"
+	_passwordHttpDeliver "github.com/password/delivery/http"
+	_passwordRepo "github.com/password/repository"
+	_passwordUcase "github.com/password/usecase"
+	_payoutHttpDeliver "github.com/payout/delivery/http"
+	_payoutRepo "github.com/payout/repository"
+	_payoutUcase "github.com/payout/usecase"
+	"github.com/picture"
+	"github.com/points"
+	_pointsHttpDeliver "github.com/points/delivery/http"
+	_pointsRepo "github.com/points/repository"
+	_pointsUcase "github.com/points/usecase"
+	_referralHttpDeliver "github.com/referral/delivery/http"
+	_referralRepo "github.com/referral/repository"
+	_referralUcase "github.com/referral/usecase"
+	"github.com/storage"
+	"github.com/storage/local"
+	"github.com/storage/s3"
+	"github.com/transaction"
 	_userHttpDeliver "github.com/user/delivery/http"
 	_userRepo "github.com/user/repository"
 	_userUcase "github.com/user/usecase"
+	_verificationHttpDeliver "github.com/verification/delivery/http"
+	_verificationUcase "github.com/verification/usecase"
 )
 
-// func init() {
-// 	viper.SetConfigFile(`config.json`)
-// 	err := viper.ReadInConfig()
-// 	if err != nil {
-// 		panic(err)
-// 	}
-
-// 	if viper.GetBool(`debug`) {
-// 		fmt.Println("Service RUN on DEBUG mode")
-// 	}
-// }
+var configPath = flag.String("config", "config.json", "path to the config file")
 
 func main() {
-	// dbHost := viper.GetString(`database.host`)
-	// dbPort := viper.GetString(`database.port`)
-	// dbUser := viper.GetString(`database.user`)
-	// dbPass := viper.GetString(`database.pass`)
-	// dbName := viper.GetString(`database.name`)
-	// baseUrlis := viper.GetString(`identityServer.baseUrl`)
-	// basicAuth := viper.GetString(`identityServer.basicAuth`)
-	dbHost := "api-blog-cgo-mysqldbserver.mysql.database.azure.com"
-	dbPort := "3306"
-	dbUser := "AdminCgo@api-blog-cgo-mysqldbserver"
-	dbPass := "Standar123."
-	dbName := "cgo_indonesia"
-	baseUrlis := "https://identity-server-cgo-indonesia.azurewebsites.net"
-	basicAuth := "cm9jbGllbnQ6c2VjcmV0"
-	connection := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", dbUser, dbPass, dbHost, dbPort, dbName)
-	val := url.Values{}
-	val.Add("parseTime", "1")
-	val.Add("loc", "Asia/Jakarta")
-	dsn := fmt.Sprintf("%s?%s", connection, val.Encode())
-	dbConn, err := sql.Open(`mysql`, dsn)
-	// if err != nil && viper.GetBool("debug") {
-	// 	fmt.Println(err)
-	// }
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.Debug {
+		fmt.Println("Service RUN on DEBUG mode")
+	}
+
+	dbConn, err := sql.Open(`mysql`, cfg.Database.DSN())
+	if err != nil {
+		log.Fatal(err)
+	}
 	err = dbConn.Ping()
 	if err != nil {
 		log.Fatal(err)
-		os.Exit(1)
 	}
 
 	defer func() {
@@ -76,27 +96,148 @@ func main() {
 	}()
 
 	e := echo.New()
-	middL := middleware.InitMiddleware()
-	e.Use(middL.CORS)
 
 	merchantRepo := _merchantRepo.NewmerchantRepository(dbConn)
-	userRepo 	:= _userRepo.NewuserRepository(dbConn)
+	userRepo := _userRepo.NewuserRepository(dbConn)
+	tokenRepo := _isRepo.NewtokenRepository(dbConn)
 	authorRepo := _authorRepo.NewMysqlAuthorRepository(dbConn)
 	ar := _articleRepo.NewMysqlArticleRepository(dbConn)
+	categoryRepo := _categoryRepo.NewMysqlCategoryRepository(dbConn)
+	ledgerRepo := _ledgerRepo.NewledgerRepository(dbConn)
+	payoutRepo := _payoutRepo.NewpayoutRepository(dbConn)
+	pointsRepo := _pointsRepo.NewpointsRepository(dbConn)
+	referralRepo := _referralRepo.NewreferralRepository(dbConn)
+	passwordRepo := _passwordRepo.NewpasswordRepository(dbConn)
+	txManager := transaction.NewManager(dbConn)
 
-	timeoutContext := time.Duration(30) * time.Second
+	timeoutContext := cfg.Context.Timeout
 
-	isUsecase := _isUcase.NewidentityserverUsecase(baseUrlis, basicAuth)
-	userUsecase := _userUcase.NewuserUsecase(userRepo,isUsecase,timeoutContext)
-	merchantUsecase := _merchantUcase.NewmerchantUsecase(merchantRepo, isUsecase, timeoutContext)
-	au := _articleUcase.NewArticleUsecase(ar, authorRepo, timeoutContext)
+	isClient, err := _isUcase.NewHTTPClient(_isUcase.TLSOptions{
+		CAFile:             cfg.IdentityServer.TLS.CAFile,
+		PinnedSHA256:       cfg.IdentityServer.TLS.PinnedSHA256,
+		ClientCertFile:     cfg.IdentityServer.TLS.ClientCertFile,
+		ClientKeyFile:      cfg.IdentityServer.TLS.ClientKeyFile,
+		InsecureSkipVerify: cfg.IdentityServer.TLS.InsecureSkipVerify,
+	}, timeoutContext)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var jwtOptions *_isUcase.JWTOptions
+	if cfg.IdentityServer.JWT.Enabled {
+		jwtOptions = &_isUcase.JWTOptions{
+			Audience: cfg.IdentityServer.JWT.Audience,
+			Issuer:   cfg.IdentityServer.JWT.Issuer,
+			Leeway:   cfg.IdentityServer.JWT.Leeway,
+		}
+	}
+	isUsecase := _isUcase.NewidentityserverUsecase(cfg.IdentityServer.BaseURL, cfg.IdentityServer.BasicAuth, isClient, jwtOptions)
+	earningRules := make([]models.EarningRule, 0, len(cfg.Points.Rules))
+	for _, rule := range cfg.Points.Rules {
+		earningRules = append(earningRules, models.EarningRule{Event: rule.Event, Points: rule.Points, PerAmount: rule.PerAmount})
+	}
+	pointsUsecase := _pointsUcase.NewpointsUsecase(pointsRepo, userRepo, txManager, earningRules, cfg.Points.ExpiryPeriod, timeoutContext)
+	referralUsecase := _referralUcase.NewreferralUsecase(referralRepo, userRepo, pointsUsecase, txManager, timeoutContext)
+	pictureStore, err := pictureStorage(e, cfg.Storage, timeoutContext)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pictureUploader := picture.New(pictureStore, cfg.Storage.MaxUploadSize)
+	userUsecase := _userUcase.NewuserUsecase(userRepo, isUsecase, tokenRepo, referralUsecase, pointsUsecase, pictureUploader, txManager, timeoutContext)
+	merchantUsecase := _merchantUcase.NewmerchantUsecase(merchantRepo, isUsecase, tokenRepo, pictureUploader, txManager, timeoutContext)
+	ledgerUsecase := _ledgerUcase.NewledgerUsecase(ledgerRepo, merchantRepo, txManager, timeoutContext)
+	payoutUsecase := _payoutUcase.NewpayoutUsecase(payoutRepo, merchantRepo, ledgerUsecase, txManager, timeoutContext)
+	emailNotifier, smsNotifier := notifiers(cfg.Verification, timeoutContext)
+	verificationUsecase := _verificationUcase.NewverificationUsecase(userRepo, isUsecase, referralUsecase, emailNotifier, smsNotifier, _verificationUcase.Options{
+		CodeTTL:        cfg.Verification.CodeTTL,
+		MaxAttempts:    cfg.Verification.MaxAttempts,
+		ResendInterval: cfg.Verification.ResendInterval,
+	}, timeoutContext)
+	passwordUsecase := _passwordUcase.NewpasswordUsecase(passwordRepo, tokenRepo, userRepo, merchantRepo, isUsecase, emailNotifier, txManager, _passwordUcase.Options{
+		ResetTokenTTL:  cfg.Password.ResetTokenTTL,
+		ResetURL:       cfg.Password.ResetURL,
+		ForgotInterval: cfg.Password.ForgotInterval,
+	}, timeoutContext)
+	articleSearcher := _articleSearch.NewMysqlSearcher(dbConn)
+	if cfg.Search.Engine == "memory" {
+		articleSearcher = _articleSearch.NewMemorySearcher()
+	}
+	au := _articleUcase.NewArticleUsecase(ar, authorRepo, categoryRepo, articleSearcher, txManager, timeoutContext)
+	if cfg.Search.Engine == "memory" {
+		if err := au.Reindex(context.Background()); err != nil {
+			log.Fatal(err)
+		}
+	}
+	cu := _categoryUcase.NewCategoryUsecase(categoryRepo, timeoutContext)
+	authorUsecase := _authorUcase.NewAuthorUsecase(authorRepo, userRepo, timeoutContext)
+
+	middL := middleware.InitMiddleware(userUsecase, merchantUsecase, cfg.Auth.Admins)
+	e.Use(middL.CORS)
+	e.Use(_authorLoader.Middleware(authorRepo))
+
+	_isHttpDeliver.NewisHandler(e, merchantUsecase, userUsecase, middL)
+	_passwordHttpDeliver.NewpasswordHandler(e, passwordUsecase, middL)
+	_userHttpDeliver.NewuserHandler(e, userUsecase, middL)
+	_merchantHttpDeliver.NewmerchantHandler(e, merchantUsecase, middL)
+	_ledgerHttpDeliver.NewledgerHandler(e, ledgerUsecase, middL)
+	_payoutHttpDeliver.NewpayoutHandler(e, payoutUsecase, middL)
+	_pointsHttpDeliver.NewpointsHandler(e, pointsUsecase, middL)
+	_referralHttpDeliver.NewreferralHandler(e, referralUsecase, middL)
+	_verificationHttpDeliver.NewverificationHandler(e, verificationUsecase, middL)
+	_articleHttpDeliver.NewArticleHandler(e, au, middL)
+	_categoryHttpDeliver.NewCategoryHandler(e, cu, middL)
+	_authorHttpDeliver.NewAuthorHandler(e, authorUsecase, middL)
 
-	_isHttpDeliver.NewisHandler(e,merchantUsecase,userUsecase)
-	_userHttpDeliver.NewuserHandler(e,userUsecase)
-	_merchantHttpDeliver.NewmerchantHandler(e, merchantUsecase)
-	_articleHttpDeliver.NewArticleHandler(e, au)
+	go points.RunExpiry(context.Background(), pointsUsecase, cfg.Points.ExpiryInterval)
 
-	log.Fatal(e.Start(":9090"))
+	log.Fatal(e.Start(cfg.Server.Address))
 }
 
+// notifiers will build the email and sms notifiers of the verification codes.
+// A channel that is not configured keeps its messages in memory so the service still starts in development.
+func notifiers(cfg config.Verification, timeout time.Duration) (email notifier.Notifier, text notifier.Notifier) {
+	if cfg.SMTP.Host != "" {
+		email = smtp.New(smtp.Options{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		})
+	} else {
+		logrus.Warn("verification.smtp.host is empty, the verification emails are not delivered")
+		email = memory.New()
+	}
+	if cfg.SMS.URL != "" {
+		text = sms.New(sms.Options{URL: cfg.SMS.URL, APIKey: cfg.SMS.APIKey, Sender: cfg.SMS.Sender}, &http.Client{Timeout: timeout})
+	} else {
+		logrus.Warn("verification.sms.url is empty, the verification texts are not delivered")
+		text = memory.New()
+	}
+	return email, text
+}
 
+// pictureStorage will build the storage of the uploaded pictures.
+// The files of the local storage are served by the service itself under the path of its base url.
+func pictureStorage(e *echo.Echo, cfg config.Storage, timeout time.Duration) (storage.Storage, error) {
+	if cfg.Backend == "s3" {
+		return s3.New(s3.Options{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			PathStyle: cfg.S3.PathStyle,
+			BaseURL:   cfg.S3.BaseURL,
+			ACL:       cfg.S3.ACL,
+		}, &http.Client{Timeout: timeout})
+	}
+	baseURL, err := url.Parse(cfg.Local.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("storage.local.baseUrl is not a valid url: %v", err)
+	}
+	if baseURL.Path == "" || baseURL.Path == "/" {
+		return nil, fmt.Errorf("storage.local.baseUrl must have a path the files are served under")
+	}
+	e.Static(baseURL.Path, cfg.Local.Dir)
+	return local.New(cfg.Local.Dir, cfg.Local.BaseURL), nil
+}
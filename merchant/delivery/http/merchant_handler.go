@@ -2,17 +2,27 @@ package http
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo"
 	"github.com/sirupsen/logrus"
-	validator "gopkg.in/go-playground/validator.v9"
 
+	"github.com/identityserver"
 	"github.com/merchant"
+	"github.com/middleware"
 	"github.com/models"
+	"github.com/picture"
+	"github.com/validation"
 )
 
+// balanceNotEditable is answered to the profile requests that try to set the balance
+const balanceNotEditable = "balance can only change through the merchant transactions"
+
+// passwordNotEditable is answered to the profile updates that try to set the password
+const passwordNotEditable = "password can only change through /account/password/change"
+
 // ResponseError represent the reseponse error struct
 type ResponseError struct {
 	Message string `json:"message"`
@@ -24,80 +34,202 @@ type merchantHandler struct {
 }
 
 // NewmerchantHandler will initialize the merchants/ resources endpoint
-func NewmerchantHandler(e *echo.Echo, us merchant.Usecase) {
+func NewmerchantHandler(e *echo.Echo, us merchant.Usecase, mw *middleware.GoMiddleware) {
 	handler := &merchantHandler{
 		MerchantUsecase: us,
 	}
 	e.POST("/merchants", handler.CreateMerchant)
-	e.PUT("/merchants/:id", handler.UpdateMerchant)
-	//e.GET("/merchants/:id", handler.GetByID)
-	//e.DELETE("/merchants/:id", handler.Delete)
+	e.PUT("/merchants/:id", handler.UpdateMerchant, mw.Authenticate, mw.Authorize(middleware.Policy{
+		Roles:      []string{models.RoleMerchant, models.RoleAdmin},
+		OwnerParam: "id",
+	}))
+	adminOnly := mw.Authorize(middleware.Policy{Roles: []string{models.RoleAdmin}})
+	ownerOrAdmin := mw.Authorize(middleware.Policy{
+		Roles:      []string{models.RoleMerchant, models.RoleAdmin},
+		OwnerParam: "id",
+	})
+	e.GET("/merchants", handler.FetchMerchant, mw.Authenticate, adminOnly)
+	e.GET("/merchants/:id", handler.GetByID, mw.Authenticate, ownerOrAdmin)
+	e.DELETE("/merchants/:id", handler.Delete, mw.Authenticate, ownerOrAdmin)
+	e.PUT("/merchants/:id/logo", handler.UploadLogo, mw.Authenticate, ownerOrAdmin)
+	e.POST("/merchants/:id/activate", handler.Activate, mw.Authenticate, adminOnly)
+	e.POST("/merchants/:id/deactivate", handler.Deactivate, mw.Authenticate, adminOnly)
+	e.POST("/merchants/:id/restore", handler.Restore, mw.Authenticate, adminOnly)
+}
+
+// FetchMerchant will fetch the merchants based on given params, filtered by is_active and searched by name or email.
+// include_deleted also lists the deleted merchants.
+func (a *merchantHandler) FetchMerchant(c echo.Context) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
+	filter := &models.MerchantFilter{Search: c.QueryParam("search")}
+	if isActiveS := c.QueryParam("is_active"); isActiveS != "" {
+		active, err := strconv.ParseBool(isActiveS)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ResponseError{Message: "is_active must be a boolean"})
+		}
+		isActive := 0
+		if active {
+			isActive = 1
+		}
+		filter.IsActive = &isActive
+	}
+	if includeDeletedS := c.QueryParam("include_deleted"); includeDeletedS != "" {
+		includeDeleted, err := strconv.ParseBool(includeDeletedS)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ResponseError{Message: "include_deleted must be a boolean"})
+		}
+		filter.IncludeDeleted = includeDeleted
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	listMerchant, nextCursor, err := a.MerchantUsecase.Fetch(ctx, filter, cursor, int64(num))
+
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, listMerchant)
 }
 
-func isRequestValid(m *models.NewCommandMerchant) (bool, error) {
-	validate := validator.New()
-	err := validate.Struct(m)
+// GetByID will get merchant by given id
+func (a *merchantHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := a.MerchantUsecase.GetByID(ctx, c.Param("id"))
 	if err != nil {
-		return false, err
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
-	return true, nil
+	return c.JSON(http.StatusOK, res)
 }
 
-// Store will store the merchant by given request body
+// Delete will soft delete merchant by given id
+func (a *merchantHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err := a.MerchantUsecase.Delete(ctx, c.Param("id"), middleware.GetPrincipal(c).Username)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Activate will allow the merchant to login again
+func (a *merchantHandler) Activate(c echo.Context) error {
+	return a.setActive(c, true)
+}
+
+// Deactivate will prevent the merchant from logging in
+func (a *merchantHandler) Deactivate(c echo.Context) error {
+	return a.setActive(c, false)
+}
+
+// UploadLogo will store the logo sent as the file field of a multipart form and return its thumbnails
+func (a *merchantHandler) UploadLogo(c echo.Context) error {
+	file, err := picture.FormFile(c.Request(), "file")
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := a.MerchantUsecase.UploadLogo(ctx, c.Param("id"), file, middleware.GetPrincipal(c).Username)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// Restore will undo the soft delete of the merchant by given id
+func (a *merchantHandler) Restore(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err := a.MerchantUsecase.Restore(ctx, c.Param("id"), middleware.GetPrincipal(c).Username)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (a *merchantHandler) setActive(c echo.Context, active bool) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err := a.MerchantUsecase.SetActive(ctx, c.Param("id"), active, middleware.GetPrincipal(c).Username)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// CreateMerchant will register the merchant sent as a JSON or form request body
 func (a *merchantHandler) CreateMerchant(c echo.Context) error {
-	//var merchantCommand models.NewCommandMerchant
-	//err := c.Bind(&merchantCommand)
-	//if err != nil {
-	//	return c.JSON(http.StatusUnprocessableEntity, err.Error())
-	//}
-	balance, _ := strconv.ParseFloat(c.FormValue("balance"),64)
-	merchantCommand := models.NewCommandMerchant{
-		Id:               c.FormValue("id"),
-		MerchantName:     c.FormValue("merchant_name"),
-		MerchantDesc:     c.FormValue("merchant_desc"),
-		MerchantEmail:    c.FormValue("merchant_email"),
-		MerchantPassword: c.FormValue("password"),
-		Balance:          balance,
-	}
-	if ok, err := isRequestValid(&merchantCommand); !ok {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	body, err := validation.Decode(c.Request())
+	if err != nil {
+		return c.JSON(validation.StatusCode(err), err)
+	}
+	if body.Has("balance") {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: balanceNotEditable})
+	}
+	var merchantCommand models.NewCommandMerchant
+	if err := body.Bind(&merchantCommand); err != nil {
+		return c.JSON(validation.StatusCode(err), err)
 	}
 	ctx := c.Request().Context()
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	error := a.MerchantUsecase.Create(ctx, &merchantCommand,"admin")
+	error := a.MerchantUsecase.Create(ctx, &merchantCommand, merchantCommand.MerchantEmail)
 
 	if error != nil {
 		return c.JSON(getStatusCode(error), ResponseError{Message: error.Error()})
 	}
+	merchantCommand.MerchantPassword = ""
 	return c.JSON(http.StatusCreated, merchantCommand)
 }
 
+// UpdateMerchant will update the profile of the merchant by given id with the JSON or form request body
 func (a *merchantHandler) UpdateMerchant(c echo.Context) error {
-	//var merchantCommand models.NewCommandMerchant
-	//err := c.Bind(&merchantCommand)
-	//if err != nil {
-	//	return c.JSON(http.StatusUnprocessableEntity, err.Error())
-	//}
-	balance, _ := strconv.ParseFloat(c.FormValue("balance"),64)
-	merchantCommand := models.NewCommandMerchant{
-		Id:               c.FormValue("id"),
-		MerchantName:     c.FormValue("merchant_name"),
-		MerchantDesc:     c.FormValue("merchant_desc"),
-		MerchantEmail:    c.FormValue("merchant_email"),
-		MerchantPassword: c.FormValue("password"),
-		Balance:          balance,
-	}
-	if ok, err := isRequestValid(&merchantCommand); !ok {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	body, err := validation.Decode(c.Request())
+	if err != nil {
+		return c.JSON(validation.StatusCode(err), err)
+	}
+	if body.Has("balance") {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: balanceNotEditable})
+	}
+	if body.Has("password") {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: passwordNotEditable})
+	}
+	var merchantCommand models.NewCommandMerchant
+	if err := body.Bind(&merchantCommand); err != nil {
+		return c.JSON(validation.StatusCode(err), err)
 	}
+	merchantCommand.Id = c.Param("id")
 	ctx := c.Request().Context()
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	err := a.MerchantUsecase.Update(ctx, &merchantCommand,"admin")
+	err = a.MerchantUsecase.Update(ctx, &merchantCommand, middleware.GetPrincipal(c).Username)
 
 	if err != nil {
 		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
@@ -109,17 +241,25 @@ func getStatusCode(err error) int {
 		return http.StatusOK
 	}
 	logrus.Error(err)
-	switch err {
-	case models.ErrInternalServerError:
+	switch {
+	case errors.Is(err, models.ErrInternalServerError):
 		return http.StatusInternalServerError
-	case models.ErrNotFound:
+	case errors.Is(err, models.ErrNotFound):
 		return http.StatusNotFound
-	case models.ErrUnAuthorize:
+	case errors.Is(err, models.ErrUnAuthorize):
 		return http.StatusUnauthorized
-	case models.ErrConflict:
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrConflict):
 		return http.StatusConflict
-	case models.ErrBadParamInput:
+	case errors.Is(err, picture.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, picture.ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, models.ErrBadParamInput):
 		return http.StatusBadRequest
+	case errors.Is(err, identityserver.ErrUnreachable):
+		return http.StatusBadGateway
 	default:
 		return http.StatusInternalServerError
 	}
@@ -1 +1,184 @@
 package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	merchantHttp "github.com/merchant/delivery/http"
+	"github.com/merchant/mocks"
+	"github.com/middleware/middlewaretest"
+	"github.com/models"
+	"github.com/picture"
+)
+
+func newServer(mockUCase *mocks.Usecase) *echo.Echo {
+	e := echo.New()
+	merchantHttp.NewmerchantHandler(e, mockUCase, middlewaretest.New())
+	return e
+}
+
+func serve(e *echo.Echo, method string, target string, token string) *httptest.ResponseRecorder {
+	return middlewaretest.Serve(e, middlewaretest.NewRequest(method, target, ""), token)
+}
+
+func TestFetch(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Fetch", mock.Anything, mock.MatchedBy(func(filter *models.MerchantFilter) bool {
+		return filter.Search == "shop" && filter.IsActive != nil && *filter.IsActive == 0
+	}), "", int64(5)).Return([]*models.MerchantInfoDto{{Id: "m1"}}, "next", nil).Once()
+
+	rec := serve(e, echo.GET, "/merchants?num=5&search=shop&is_active=false", middlewaretest.AdminToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "next", rec.Header().Get("X-Cursor"))
+	var list []models.MerchantInfoDto
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Len(t, list, 1)
+
+	rec = serve(e, echo.GET, "/merchants?is_active=maybe", middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(e, echo.GET, "/merchants", middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestGetByID(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("GetByID", mock.Anything, "m1").Return(&models.MerchantInfoDto{Id: "m1", MerchantName: "Shop"}, nil).Once()
+
+	rec := serve(e, echo.GET, "/merchants/m1", middlewaretest.MerchantToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(e, echo.GET, "/merchants/m2", middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDelete(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Delete", mock.Anything, "m1", "shop@mail.com").Return(nil).Once()
+
+	rec := serve(e, echo.DELETE, "/merchants/m1", middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestSetActive(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("SetActive", mock.Anything, "m1", false, "admin@mail.com").Return(nil).Once()
+	mockUCase.On("SetActive", mock.Anything, "m2", true, "admin@mail.com").Return(models.ErrNotFound).Once()
+
+	rec := serve(e, echo.POST, "/merchants/m1/deactivate", middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(e, echo.POST, "/merchants/m1/deactivate", middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = serve(e, echo.POST, "/merchants/m2/activate", middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestUpdatePasswordNotEditable(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+
+	form := url.Values{"merchant_name": {"Shop"}, "merchant_email": {"shop@mail.com"}, "password": {"new-secret"}}
+	req := httptest.NewRequest(echo.PUT, "/merchants/m1", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer admin-token")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateBalanceNotEditable(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+
+	form := url.Values{"merchant_name": {"Shop"}, "merchant_email": {"shop@mail.com"}, "balance": {"1000000"}}
+	req := httptest.NewRequest(echo.PUT, "/merchants/m1", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer admin-token")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUploadLogo(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	pict := &models.Picture{Url: "/uploads/merchants/m1/logo-512.jpg"}
+	mockUCase.On("UploadLogo", mock.Anything, "m1", mock.Anything, "shop@mail.com").Return(pict, nil).Once()
+	mockUCase.On("UploadLogo", mock.Anything, "m1", mock.Anything, "shop@mail.com").Return(nil, picture.ErrCorrupt).Once()
+
+	upload := func(target string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "logo.jpg")
+		require.NoError(t, err)
+		_, err = part.Write([]byte("jpeg"))
+		require.NoError(t, err)
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest(echo.PUT, target, &body)
+		req.Header.Set(echo.HeaderContentType, form.FormDataContentType())
+		req.Header.Set(echo.HeaderAuthorization, "Bearer merchant-token")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := upload("/merchants/m1/logo")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/uploads/merchants/m1/logo-512.jpg")
+
+	rec = upload("/merchants/m1/logo")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = upload("/merchants/m2/logo")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestUpdateMerchant(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Update", mock.Anything, &models.NewCommandMerchant{Id: "m1", MerchantName: "Shop", MerchantEmail: "shop@mail.com"}, "shop@mail.com").
+		Return(nil).Once()
+
+	req := httptest.NewRequest(echo.PUT, "/merchants/m1", strings.NewReader(`{"merchant_name": "Shop", "merchant_email": "shop@mail.com"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer merchant-token")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	req = httptest.NewRequest(echo.PUT, "/merchants/m1", strings.NewReader(`{"merchant_name": "", "merchant_email": "shop", "website": "shop.com"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer merchant-token")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `{"field":"website","rule":"unknown","message":"is not a known field"}`)
+	mockUCase.AssertExpectations(t)
+}
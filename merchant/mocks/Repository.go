@@ -1 +1,282 @@
+// Code generated by mockery v1.0.0
 package mocks
+
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+import context "golang.org/x/net/context"
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, id, deleted_by
+func (_m *Repository) Delete(ctx context.Context, id string, deleted_by string) error {
+	ret := _m.Called(ctx, id, deleted_by)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, deleted_by)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *Repository) Fetch(ctx context.Context, filter *models.MerchantFilter, cursor string, num int64) ([]*models.Merchant, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
+
+	var r0 []*models.Merchant
+	if rf, ok := ret.Get(0).(func(context.Context, *models.MerchantFilter, string, int64) []*models.Merchant); ok {
+		r0 = rf(ctx, filter, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Merchant)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, *models.MerchantFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *models.MerchantFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchIncludeDeleted provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *Repository) FetchIncludeDeleted(ctx context.Context, filter *models.MerchantFilter, cursor string, num int64) ([]*models.Merchant, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
+
+	var r0 []*models.Merchant
+	if rf, ok := ret.Get(0).(func(context.Context, *models.MerchantFilter, string, int64) []*models.Merchant); ok {
+		r0 = rf(ctx, filter, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Merchant)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, *models.MerchantFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *models.MerchantFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetBalanceForUpdate provides a mock function with given fields: ctx, id
+func (_m *Repository) GetBalanceForUpdate(ctx context.Context, id string) (int64, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByID(ctx context.Context, id string) (*models.Merchant, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Merchant
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Merchant); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Merchant)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByIDIncludeDeleted provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByIDIncludeDeleted(ctx context.Context, id string) (*models.Merchant, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Merchant
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Merchant); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Merchant)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByMerchantEmail provides a mock function with given fields: ctx, merchantEmail
+func (_m *Repository) GetByMerchantEmail(ctx context.Context, merchantEmail string) (*models.Merchant, error) {
+	ret := _m.Called(ctx, merchantEmail)
+
+	var r0 *models.Merchant
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Merchant); ok {
+		r0 = rf(ctx, merchantEmail)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Merchant)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, merchantEmail)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByMerchantEmailIncludeDeleted provides a mock function with given fields: ctx, merchantEmail
+func (_m *Repository) GetByMerchantEmailIncludeDeleted(ctx context.Context, merchantEmail string) (*models.Merchant, error) {
+	ret := _m.Called(ctx, merchantEmail)
+
+	var r0 *models.Merchant
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Merchant); ok {
+		r0 = rf(ctx, merchantEmail)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Merchant)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, merchantEmail)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, a
+func (_m *Repository) Insert(ctx context.Context, a *models.Merchant) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Merchant) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, id, modified_by
+func (_m *Repository) Restore(ctx context.Context, id string, modified_by string) error {
+	ret := _m.Called(ctx, id, modified_by)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, modified_by)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetActive provides a mock function with given fields: ctx, id, isActive, modified_by
+func (_m *Repository) SetActive(ctx context.Context, id string, isActive int, modified_by string) error {
+	ret := _m.Called(ctx, id, isActive, modified_by)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, string) error); ok {
+		r0 = rf(ctx, id, isActive, modified_by)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, ar
+func (_m *Repository) Update(ctx context.Context, ar *models.Merchant) error {
+	ret := _m.Called(ctx, ar)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Merchant) error); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateBalance provides a mock function with given fields: ctx, id, balance
+func (_m *Repository) UpdateBalance(ctx context.Context, id string, balance int64) error {
+	ret := _m.Called(ctx, id, balance)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, id, balance)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateLogoUrl provides a mock function with given fields: ctx, id, url, modified_by
+func (_m *Repository) UpdateLogoUrl(ctx context.Context, id string, url string, modified_by string) error {
+	ret := _m.Called(ctx, id, url, modified_by)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, id, url, modified_by)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
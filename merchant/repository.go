@@ -5,11 +5,24 @@ import (
 	"golang.org/x/net/context"
 )
 
+// Repository represent the merchant's repository contract.
+// Fetch, GetByID and GetByMerchantEmail only return merchants that are neither deleted nor inactive,
+// unless Fetch is filtered by is_active. The IncludeDeleted variants return every row for the admin tooling.
+// The balance is only written by the ledger through UpdateBalance, Insert starts it at zero and Update leaves it as is.
+// The logo is only written by UpdateLogoUrl once it is uploaded.
 type Repository interface {
-	Fetch(ctx context.Context, cursor string, num int64) (res []*models.Merchant, nextCursor string, err error)
+	Fetch(ctx context.Context, filter *models.MerchantFilter, cursor string, num int64) (res []*models.Merchant, nextCursor string, err error)
+	FetchIncludeDeleted(ctx context.Context, filter *models.MerchantFilter, cursor string, num int64) (res []*models.Merchant, nextCursor string, err error)
 	GetByID(ctx context.Context, id string) (*models.Merchant, error)
+	GetByIDIncludeDeleted(ctx context.Context, id string) (*models.Merchant, error)
 	GetByMerchantEmail(ctx context.Context, merchantEmail string) (*models.Merchant, error)
+	GetByMerchantEmailIncludeDeleted(ctx context.Context, merchantEmail string) (*models.Merchant, error)
 	Update(ctx context.Context, ar *models.Merchant) error
 	Insert(ctx context.Context, a *models.Merchant) error
-	Delete(ctx context.Context, id string,deleted_by string) error
+	Delete(ctx context.Context, id string, deleted_by string) error
+	SetActive(ctx context.Context, id string, isActive int, modified_by string) error
+	Restore(ctx context.Context, id string, modified_by string) error
+	GetBalanceForUpdate(ctx context.Context, id string) (int64, error)
+	UpdateBalance(ctx context.Context, id string, balance int64) error
+	UpdateLogoUrl(ctx context.Context, id string, url string, modified_by string) error
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/base64"
 	"fmt"
+	"strings"
 
 	"time"
 
@@ -12,12 +13,22 @@ import (
 
 	"github.com/merchant"
 	"github.com/models"
+	"github.com/transaction"
 )
 
 const (
 	timeFormat = "2006-01-02T15:04:05.999Z07:00" // reduce precision from RFC3339Nano as date format
+
+	// merchantColumns lists the columns in the order they are scanned by fetch
+	merchantColumns = `id, created_by, created_date, modified_by, modified_date, deleted_by, deleted_date, is_deleted, is_active, merchant_name, merchant_desc, merchant_email, logo_url, balance`
+
+	// activeScope restricts a query to the rows that are neither soft deleted nor deactivated
+	activeScope = ` AND is_deleted = 0 AND is_active = 1`
 )
 
+// likeEscaper will escape the wildcards of a user supplied LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 type merchantRepository struct {
 	Conn *sql.DB
 }
@@ -27,8 +38,13 @@ func NewmerchantRepository(Conn *sql.DB) merchant.Repository {
 	return &merchantRepository{Conn}
 }
 
+// conn will return the transaction of the unit of work ctx belongs to, or the connection pool
+func (m *merchantRepository) conn(ctx context.Context) transaction.DBTX {
+	return transaction.Conn(ctx, m.Conn)
+}
+
 func (m *merchantRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*models.Merchant, error) {
-	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
@@ -57,6 +73,7 @@ func (m *merchantRepository) fetch(ctx context.Context, query string, args ...in
 			&t.MerchantName,
 			&t.MerchantDesc,
 			&t.MerchantEmail,
+			&t.LogoUrl,
 			&t.Balance,
 		)
 
@@ -70,15 +87,41 @@ func (m *merchantRepository) fetch(ctx context.Context, query string, args ...in
 	return result, nil
 }
 
-func (m *merchantRepository) Fetch(ctx context.Context, cursor string, num int64) ([]*models.Merchant, string, error) {
-	query := `SELECT * FROM merchants WHERE created_at > ? ORDER BY created_at LIMIT ? `
+func (m *merchantRepository) Fetch(ctx context.Context, filter *models.MerchantFilter, cursor string, num int64) ([]*models.Merchant, string, error) {
+	scope := ` AND is_deleted = 0`
+	if filter == nil || filter.IsActive == nil {
+		scope = activeScope
+	}
+	return m.fetchPage(ctx, scope, filter, cursor, num)
+}
 
+func (m *merchantRepository) FetchIncludeDeleted(ctx context.Context, filter *models.MerchantFilter, cursor string, num int64) ([]*models.Merchant, string, error) {
+	return m.fetchPage(ctx, "", filter, cursor, num)
+}
+
+func (m *merchantRepository) fetchPage(ctx context.Context, scope string, filter *models.MerchantFilter, cursor string, num int64) ([]*models.Merchant, string, error) {
 	decodedCursor, err := DecodeCursor(cursor)
 	if err != nil && cursor != "" {
 		return nil, "", models.ErrBadParamInput
 	}
 
-	res, err := m.fetch(ctx, query, decodedCursor, num)
+	query := `SELECT ` + merchantColumns + ` FROM merchants WHERE created_date > ?` + scope
+	args := []interface{}{decodedCursor}
+	if filter != nil {
+		if filter.IsActive != nil {
+			query += ` AND is_active = ?`
+			args = append(args, *filter.IsActive)
+		}
+		if filter.Search != "" {
+			search := "%" + likeEscaper.Replace(filter.Search) + "%"
+			query += ` AND (merchant_name LIKE ? OR merchant_email LIKE ?)`
+			args = append(args, search, search)
+		}
+	}
+	query += ` ORDER BY created_date LIMIT ? `
+	args = append(args, num)
+
+	res, err := m.fetch(ctx, query, args...)
 	if err != nil {
 		return nil, "", err
 	}
@@ -90,45 +133,42 @@ func (m *merchantRepository) Fetch(ctx context.Context, cursor string, num int64
 
 	return res, nextCursor, err
 }
-func (m *merchantRepository) GetByID(ctx context.Context, id string) (res *models.Merchant, err error) {
-	query := `SELECT * FROM merchants WHERE id = ?`
 
-	list, err := m.fetch(ctx, query, id)
-	if err != nil {
-		return nil, err
-	}
+func (m *merchantRepository) GetByID(ctx context.Context, id string) (*models.Merchant, error) {
+	return m.getOne(ctx, `SELECT `+merchantColumns+` FROM merchants WHERE id = ?`+activeScope, id)
+}
 
-	if len(list) > 0 {
-		res = list[0]
-	} else {
-		return nil, models.ErrNotFound
-	}
+func (m *merchantRepository) GetByIDIncludeDeleted(ctx context.Context, id string) (*models.Merchant, error) {
+	return m.getOne(ctx, `SELECT `+merchantColumns+` FROM merchants WHERE id = ?`, id)
+}
 
-	return
+func (m *merchantRepository) GetByMerchantEmail(ctx context.Context, merchantEmail string) (*models.Merchant, error) {
+	return m.getOne(ctx, `SELECT `+merchantColumns+` FROM merchants WHERE merchant_email = ?`+activeScope, merchantEmail)
 }
-func (m *merchantRepository) GetByMerchantEmail(ctx context.Context, merchantEmail string) (res *models.Merchant, err error) {
-	query := `SELECT * FROM merchants WHERE merchant_email = ?`
 
-	list, err := m.fetch(ctx, query, merchantEmail)
+func (m *merchantRepository) GetByMerchantEmailIncludeDeleted(ctx context.Context, merchantEmail string) (*models.Merchant, error) {
+	return m.getOne(ctx, `SELECT `+merchantColumns+` FROM merchants WHERE merchant_email = ?`, merchantEmail)
+}
+
+func (m *merchantRepository) getOne(ctx context.Context, query string, args ...interface{}) (*models.Merchant, error) {
+	list, err := m.fetch(ctx, query, args...)
 	if err != nil {
-		return
+		return nil, err
 	}
-
-	if len(list) > 0 {
-		res = list[0]
-	} else {
+	if len(list) == 0 {
 		return nil, models.ErrNotFound
 	}
-	return
+	return list[0], nil
 }
+
 func (m *merchantRepository) Insert(ctx context.Context, a *models.Merchant) error {
-	query := `INSERT merchants SET id=? , created_by=? , created_date=? , modified_by=?, modified_date=? , deleted_by=? , deleted_date=? , is_deleted=? , is_active=? , merchant_name=? , merchant_desc=? , merchant_email=? ,balance=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	query := `INSERT merchants SET id=? , created_by=? , created_date=? , modified_by=?, modified_date=? , deleted_by=? , deleted_date=? , is_deleted=? , is_active=? , merchant_name=? , merchant_desc=? , merchant_email=? ,balance=0`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
-	_, err = stmt.ExecContext(ctx,a.Id, a.CreatedBy, time.Now(), nil, nil, nil, nil, 0, 1, a.MerchantName, a.MerchantDesc,
-		a.MerchantEmail, a.Balance)
+	_, err = stmt.ExecContext(ctx, a.Id, a.CreatedBy, time.Now(), nil, nil, nil, nil, 0, 1, a.MerchantName, a.MerchantDesc,
+		a.MerchantEmail)
 	if err != nil {
 		return err
 	}
@@ -142,37 +182,82 @@ func (m *merchantRepository) Insert(ctx context.Context, a *models.Merchant) err
 	return nil
 }
 
-func (m *merchantRepository) Delete(ctx context.Context, id string,deleted_by string) error {
-	query := `UPDATE  merchants SET deleted_by=? , deleted_date=? , is_deleted=? , is_active=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+func (m *merchantRepository) Delete(ctx context.Context, id string, deleted_by string) error {
+	query := `UPDATE  merchants SET deleted_by=? , deleted_date=? , is_deleted=? , is_active=? WHERE id = ? AND is_deleted = 0`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
 
-	_, err = stmt.ExecContext(ctx, deleted_by, time.Now(),1,0)
+	res, err := stmt.ExecContext(ctx, deleted_by, time.Now(), 1, 0, id)
 	if err != nil {
 		return err
 	}
 
-	//lastID, err := res.RowsAffected()
+	affect, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if affect != 1 {
+		return models.ErrNotFound
+	}
+	return nil
+}
 
-	//a.Id = lastID
+func (m *merchantRepository) SetActive(ctx context.Context, id string, isActive int, modified_by string) error {
+	query := `UPDATE merchants SET is_active=? , modified_by=? , modified_date=? WHERE id = ? AND is_deleted = 0`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, isActive, modified_by, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrNotFound
+	}
 	return nil
 }
+
+func (m *merchantRepository) Restore(ctx context.Context, id string, modified_by string) error {
+	query := `UPDATE merchants SET deleted_by=NULL , deleted_date=NULL , is_deleted=0 , is_active=1 , modified_by=? , modified_date=? WHERE id = ? AND is_deleted = 1`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, modified_by, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
 func (m *merchantRepository) Update(ctx context.Context, ar *models.Merchant) error {
 	query := `UPDATE merchants set modified_by=?, modified_date=? , merchant_name=? , 
-				merchant_desc=? , merchant_email=? , balance=? WHERE id = ?`
+				merchant_desc=? , merchant_email=? WHERE id = ?`
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	res, err := stmt.ExecContext(ctx, ar.ModifiedBy, time.Now(), ar.MerchantName, ar.MerchantDesc, ar.MerchantEmail,
-								ar.Balance,ar.Id)
+	res, err := stmt.ExecContext(ctx, ar.ModifiedBy, time.Now(), ar.MerchantName, ar.MerchantDesc, ar.MerchantEmail, ar.Id)
 	if err != nil {
 		return err
 	}
@@ -189,6 +274,64 @@ func (m *merchantRepository) Update(ctx context.Context, ar *models.Merchant) er
 	return nil
 }
 
+// GetBalanceForUpdate will return the balance of the merchant and lock its row until the unit of work of ctx ends,
+// so the entries of a merchant are applied one after the other
+func (m *merchantRepository) GetBalanceForUpdate(ctx context.Context, id string) (int64, error) {
+	var balance int64
+	err := m.conn(ctx).QueryRowContext(ctx, `SELECT balance FROM merchants WHERE id = ? AND is_deleted = 0 FOR UPDATE`, id).Scan(&balance)
+	if err == sql.ErrNoRows {
+		return 0, models.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return 0, err
+	}
+	return balance, nil
+}
+
+func (m *merchantRepository) UpdateBalance(ctx context.Context, id string, balance int64) error {
+	query := `UPDATE merchants SET balance=? WHERE id = ?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, balance, id)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
+// UpdateLogoUrl will save the url of the uploaded logo of the merchant
+func (m *merchantRepository) UpdateLogoUrl(ctx context.Context, id string, url string, modified_by string) error {
+	query := `UPDATE merchants SET logo_url=? , modified_by=? , modified_date=? WHERE id = ? AND is_deleted = 0`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, url, modified_by, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
 // DecodeCursor will decode cursor from user for mysql
 func DecodeCursor(encodedTime string) (time.Time, error) {
 	byt, err := base64.StdEncoding.DecodeString(encodedTime)
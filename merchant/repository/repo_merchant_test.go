@@ -1 +1,161 @@
 package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	merchantRepo "github.com/merchant/repository"
+	"github.com/models"
+)
+
+var merchantColumns = []string{"id", "created_by", "created_date", "modified_by", "modified_date", "deleted_by",
+	"deleted_date", "is_deleted", "is_active", "merchant_name", "merchant_desc", "merchant_email", "logo_url", "balance"}
+
+func TestFetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	now := time.Now()
+	rows := sqlmock.NewRows(merchantColumns).
+		AddRow("m1", "admin", now, nil, nil, nil, nil, 0, 1, "Toko 50%", "", "toko@mail.com", "", 0)
+
+	query := "SELECT .+ FROM merchants WHERE created_date > \\? AND is_deleted = 0 AND is_active = \\? AND \\(merchant_name LIKE \\? OR merchant_email LIKE \\?\\) ORDER BY created_date LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), 1, `%50\%%`, `%50\%%`, int64(1)).WillReturnRows(rows)
+
+	a := merchantRepo.NewmerchantRepository(db)
+	isActive := 1
+	list, nextCursor, err := a.Fetch(context.TODO(), &models.MerchantFilter{IsActive: &isActive, Search: "50%"}, "", 1)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, merchantRepo.EncodeCursor(list[0].CreatedDate), nextCursor)
+}
+
+func TestSetActive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "UPDATE merchants SET is_active=\\? , modified_by=\\? , modified_date=\\? WHERE id = \\? AND is_deleted = 0"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(0, "admin", sqlmock.AnyArg(), "m1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := merchantRepo.NewmerchantRepository(db)
+	err = a.SetActive(context.TODO(), "m1", 0, "admin")
+	assert.NoError(t, err)
+
+	prep = mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(1, "admin", sqlmock.AnyArg(), "m2").WillReturnResult(sqlmock.NewResult(0, 0))
+	err = a.SetActive(context.TODO(), "m2", 1, "admin")
+	assert.Equal(t, models.ErrNotFound, err)
+}
+
+// TestSetActiveUnchanged covers a merchant already in the given state, changed again within the second of
+// modified_date. MySQL changes no row then, the DSN sets clientFoundRows so the update reports the row it
+// matched and the merchant is not taken for a missing one.
+func TestSetActiveUnchanged(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "UPDATE merchants SET is_active=\\? , modified_by=\\? , modified_date=\\? WHERE id = \\? AND is_deleted = 0"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(0, "admin", sqlmock.AnyArg(), "m1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := merchantRepo.NewmerchantRepository(db)
+	err = a.SetActive(context.TODO(), "m1", 0, "admin")
+	assert.NoError(t, err)
+}
+
+func TestGetByMerchantEmailScope(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	a := merchantRepo.NewmerchantRepository(db)
+
+	mock.ExpectQuery("SELECT .+ FROM merchants WHERE merchant_email = \\? AND is_deleted = 0 AND is_active = 1").
+		WithArgs("toko@mail.com").WillReturnRows(sqlmock.NewRows(merchantColumns))
+	_, err = a.GetByMerchantEmail(context.TODO(), "toko@mail.com")
+	assert.Equal(t, models.ErrNotFound, err)
+
+	rows := sqlmock.NewRows(merchantColumns).
+		AddRow("m1", "admin", time.Now(), nil, nil, "admin", time.Now(), 1, 0, "Toko", "", "toko@mail.com", "", 0)
+	mock.ExpectQuery("SELECT .+ FROM merchants WHERE merchant_email = \\?$").
+		WithArgs("toko@mail.com").WillReturnRows(rows)
+	res, err := a.GetByMerchantEmailIncludeDeleted(context.TODO(), "toko@mail.com")
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.IsDeleted)
+}
+
+func TestRestore(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "UPDATE merchants SET deleted_by=NULL , deleted_date=NULL , is_deleted=0 , is_active=1 , modified_by=\\? , modified_date=\\? WHERE id = \\? AND is_deleted = 1"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs("admin", sqlmock.AnyArg(), "m1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := merchantRepo.NewmerchantRepository(db)
+	err = a.Restore(context.TODO(), "m1", "admin")
+	assert.NoError(t, err)
+}
+
+func TestGetBalanceForUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "SELECT balance FROM merchants WHERE id = \\? AND is_deleted = 0 FOR UPDATE"
+	mock.ExpectQuery(query).WithArgs("m1").WillReturnRows(sqlmock.NewRows([]string{"balance"}).AddRow(1500))
+	mock.ExpectQuery(query).WithArgs("m2").WillReturnRows(sqlmock.NewRows([]string{"balance"}))
+
+	a := merchantRepo.NewmerchantRepository(db)
+	balance, err := a.GetBalanceForUpdate(context.TODO(), "m1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), balance)
+
+	_, err = a.GetBalanceForUpdate(context.TODO(), "m2")
+	assert.Equal(t, models.ErrNotFound, err)
+}
@@ -1,6 +1,8 @@
 package merchant
 
 import (
+	"io"
+
 	"github.com/models"
 	"golang.org/x/net/context"
 )
@@ -8,7 +10,15 @@ import (
 type Usecase interface {
 	Update(ctx context.Context, ar *models.NewCommandMerchant, user string) error
 	Create(ctx context.Context, ar *models.NewCommandMerchant, user string) error
-	Login(ctx context.Context, ar *models.Login) (*models.GetToken,error)
-	ValidateTokenMerchant(ctx context.Context, token string) (*string,error)
-	GetMerchantInfo(ctx context.Context, token string) (*models.MerchantInfoDto,error)
-}
\ No newline at end of file
+	Login(ctx context.Context, ar *models.Login) (*models.GetToken, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*models.GetToken, error)
+	Logout(ctx context.Context, principal *models.Principal, refreshToken string) error
+	ValidateTokenMerchant(ctx context.Context, token string) (*models.Principal, error)
+	GetMerchantInfo(ctx context.Context, token string) (*models.MerchantInfoDto, error)
+	GetByID(ctx context.Context, id string) (*models.MerchantInfoDto, error)
+	Fetch(ctx context.Context, filter *models.MerchantFilter, cursor string, num int64) ([]*models.MerchantInfoDto, string, error)
+	Delete(ctx context.Context, id string, user string) error
+	Restore(ctx context.Context, id string, user string) error
+	SetActive(ctx context.Context, id string, active bool, user string) error
+	UploadLogo(ctx context.Context, id string, file io.Reader, user string) (*models.Picture, error)
+}
@@ -2,90 +2,307 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"github.com/identityserver"
+	"io"
 	"time"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/merchant"
 	"github.com/models"
+	"github.com/picture"
+	"github.com/transaction"
 )
 
 type merchantUsecase struct {
-	merchantRepo   merchant.Repository
+	merchantRepo     merchant.Repository
 	identityServerUc identityserver.Usecase
-	contextTimeout time.Duration
+	tokenRepo        identityserver.Repository
+	pictureUploader  picture.Uploader
+	txManager        transaction.Manager
+	contextTimeout   time.Duration
 }
 
 // NewmerchantUsecase will create new an merchantUsecase object representation of merchant.Usecase interface
-func NewmerchantUsecase(a merchant.Repository, is identityserver.Usecase,timeout time.Duration) merchant.Usecase {
+func NewmerchantUsecase(a merchant.Repository, is identityserver.Usecase, tokenRepo identityserver.Repository, pictureUploader picture.Uploader, txManager transaction.Manager, timeout time.Duration) merchant.Usecase {
 	return &merchantUsecase{
-		merchantRepo:   a,
-		identityServerUc:is,
-		contextTimeout: timeout,
+		merchantRepo:     a,
+		identityServerUc: is,
+		tokenRepo:        tokenRepo,
+		pictureUploader:  pictureUploader,
+		txManager:        txManager,
+		contextTimeout:   timeout,
 	}
 }
 func (m merchantUsecase) Login(ctx context.Context, ar *models.Login) (*models.GetToken, error) {
 	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
 	defer cancel()
 
-	requestToken ,err:= m.identityServerUc.GetToken(ar.Email,ar.Password)
-	if err != nil{
-		return nil,err
+	requestToken, err := m.identityServerUc.GetToken(ctx, ar.Email, ar.Password)
+	if err != nil {
+		return nil, err
 	}
-	existedMerchant, _ := m.merchantRepo.GetByMerchantEmail(ctx, ar.Email)
-	if existedMerchant == nil {
-		return nil,models.ErrNotFound
+	existedMerchant, _ := m.merchantRepo.GetByMerchantEmailIncludeDeleted(ctx, ar.Email)
+	if existedMerchant == nil || existedMerchant.IsDeleted == 1 {
+		return nil, models.ErrNotFound
+	}
+	if existedMerchant.IsActive == 0 {
+		return nil, models.ErrForbidden
 	}
-	return  requestToken,err
+	if err := m.storeRefreshToken(ctx, requestToken.RefreshToken, existedMerchant.Id); err != nil {
+		return nil, err
+	}
+	return requestToken, err
 }
 
-func (m merchantUsecase) ValidateTokenMerchant(ctx context.Context, token string) (*string, error) {
+func (m merchantUsecase) RefreshToken(ctx context.Context, refreshToken string) (*models.GetToken, error) {
 	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
 	defer cancel()
 
-	getInfoToIs ,err := m.identityServerUc.GetUserInfo(token)
-	if err != nil{
-		return nil,err
+	stored, err := m.tokenRepo.GetRefreshToken(ctx, identityserver.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, models.ErrUnAuthorize
+		}
+		return nil, err
+	}
+	if stored.IsRevoked == 1 || stored.AccountType != models.PrincipalMerchant {
+		return nil, models.ErrUnAuthorize
+	}
+	if _, err := m.merchantRepo.GetByID(ctx, stored.AccountId); err != nil {
+		return nil, models.ErrUnAuthorize
+	}
+
+	requestToken, err := m.identityServerUc.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	// the identity server may rotate the refresh token, the previous one must not be accepted again
+	if requestToken.RefreshToken != "" && requestToken.RefreshToken != refreshToken {
+		if err := m.tokenRepo.RevokeRefreshToken(ctx, stored.TokenHash); err != nil {
+			return nil, err
+		}
+		if err := m.storeRefreshToken(ctx, requestToken.RefreshToken, stored.AccountId); err != nil {
+			return nil, err
+		}
+	}
+	return requestToken, nil
+}
+
+func (m merchantUsecase) Logout(ctx context.Context, principal *models.Principal, refreshToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	if refreshToken != "" {
+		stored, err := m.tokenRepo.GetRefreshToken(ctx, identityserver.HashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				return models.ErrBadParamInput
+			}
+			return err
+		}
+		if stored.AccountId != principal.Id || stored.AccountType != models.PrincipalMerchant {
+			return models.ErrForbidden
+		}
+		if err := m.identityServerUc.RevokeToken(ctx, refreshToken, identityserver.TokenTypeRefresh); err != nil {
+			return err
+		}
+		if err := m.tokenRepo.RevokeRefreshToken(ctx, stored.TokenHash); err != nil {
+			return err
+		}
+	}
+
+	err := m.identityServerUc.RevokeToken(ctx, principal.Token, identityserver.TokenTypeAccess)
+	var validationErr *identityserver.ValidationError
+	if errors.As(err, &validationErr) {
+		// self-contained access tokens can not be revoked, they expire on their own
+		return nil
+	}
+	return err
+}
+
+func (m merchantUsecase) storeRefreshToken(ctx context.Context, refreshToken string, accountId string) error {
+	if refreshToken == "" {
+		return nil
+	}
+	return m.tokenRepo.StoreRefreshToken(ctx, &models.RefreshToken{
+		TokenHash:   identityserver.HashToken(refreshToken),
+		AccountId:   accountId,
+		AccountType: models.PrincipalMerchant,
+		CreatedDate: time.Now(),
+	})
+}
+
+func (m merchantUsecase) ValidateTokenMerchant(ctx context.Context, token string) (*models.Principal, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	getInfoToIs, err := m.identityServerUc.GetUserInfo(ctx, token)
+	if err != nil {
+		return nil, err
 	}
 	existedMerchant, _ := m.merchantRepo.GetByMerchantEmail(ctx, getInfoToIs.Email)
 	if existedMerchant == nil {
-		return nil,models.ErrNotFound
+		return nil, models.ErrNotFound
 	}
-	currentUser := getInfoToIs.Username
-	return &currentUser,nil
+	return &models.Principal{
+		Id:       existedMerchant.Id,
+		Username: getInfoToIs.Username,
+		Email:    getInfoToIs.Email,
+		Type:     models.PrincipalMerchant,
+		Token:    token,
+	}, nil
 }
 
 func (m merchantUsecase) GetMerchantInfo(ctx context.Context, token string) (*models.MerchantInfoDto, error) {
 	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
 	defer cancel()
 
-	getInfoToIs ,err := m.identityServerUc.GetUserInfo(token)
-	if err != nil{
-		return nil,err
+	getInfoToIs, err := m.identityServerUc.GetUserInfo(ctx, token)
+	if err != nil {
+		return nil, err
 	}
 	existedMerchant, _ := m.merchantRepo.GetByMerchantEmail(ctx, getInfoToIs.Email)
 	if existedMerchant == nil {
-		return nil,models.ErrNotFound
+		return nil, models.ErrNotFound
+	}
+
+	return toMerchantInfoDto(existedMerchant), nil
+}
+
+func (m merchantUsecase) GetByID(c context.Context, id string) (*models.MerchantInfoDto, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	res, err := m.merchantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toMerchantInfoDto(res), nil
+}
+
+func (m merchantUsecase) Fetch(c context.Context, filter *models.MerchantFilter, cursor string, num int64) ([]*models.MerchantInfoDto, string, error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	fetch := m.merchantRepo.Fetch
+	if filter != nil && filter.IncludeDeleted {
+		fetch = m.merchantRepo.FetchIncludeDeleted
+	}
+	listMerchant, nextCursor, err := fetch(ctx, filter, cursor, num)
+	if err != nil {
+		return nil, "", err
 	}
-	merchantInfo := models.MerchantInfoDto{
-		Id:            existedMerchant.Id,
-		MerchantName:  existedMerchant.MerchantName,
-		MerchantDesc:  existedMerchant.MerchantDesc,
-		MerchantEmail: existedMerchant.MerchantEmail,
-		Balance:       existedMerchant.Balance,
+
+	result := make([]*models.MerchantInfoDto, len(listMerchant))
+	for i, merchant := range listMerchant {
+		result[i] = toMerchantInfoDto(merchant)
 	}
+	return result, nextCursor, nil
+}
 
-	return &merchantInfo,nil
+// Delete will soft delete the merchant and disable its account at the identity server
+// The account is enabled again when the merchant can not be deleted.
+func (m merchantUsecase) Delete(c context.Context, id string, user string) error {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	existedMerchant, err := m.merchantRepo.GetByIDIncludeDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existedMerchant.IsDeleted == 1 {
+		return models.ErrNotFound
+	}
+	if err := m.identityServerUc.DisableUser(ctx, id); err != nil {
+		return err
+	}
+	err = m.merchantRepo.Delete(ctx, id, user)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		// not found means it was deleted meanwhile, its account stays disabled
+		identityserver.EnableAccount(m.identityServerUc, id, m.contextTimeout)
+	}
+	return err
 }
 
+// Restore will undo the soft delete of the merchant and enable its account at the identity server again
+func (m merchantUsecase) Restore(c context.Context, id string, user string) error {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	existedMerchant, err := m.merchantRepo.GetByIDIncludeDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existedMerchant.IsDeleted == 0 {
+		return nil
+	}
+	if err := m.identityServerUc.EnableUser(ctx, id); err != nil {
+		return err
+	}
+	return m.merchantRepo.Restore(ctx, id, user)
+}
 
-func (m merchantUsecase) Update(c context.Context, ar *models.NewCommandMerchant ,user string) error {
+// SetActive will activate or deactivate the merchant, a deactivated merchant can not login
+func (m merchantUsecase) SetActive(c context.Context, id string, active bool, user string) error {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	isActive := 0
+	if active {
+		isActive = 1
+	}
+	return m.merchantRepo.SetActive(ctx, id, isActive, user)
+}
+
+// UploadLogo will store the thumbnails of the uploaded logo and save the url of the largest one on the merchant.
+// The thumbnails of the previous logo are deleted.
+func (m merchantUsecase) UploadLogo(c context.Context, id string, file io.Reader, user string) (*models.Picture, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	existed, err := m.merchantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	prefix := "merchants/" + id + "/logo"
+	res, err := m.pictureUploader.Upload(ctx, prefix, file)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.merchantRepo.UpdateLogoUrl(ctx, id, res.Url, user); err != nil {
+		if res.Url != existed.LogoUrl {
+			m.removePicture(ctx, prefix, res.Url)
+		}
+		return nil, err
+	}
+	if existed.LogoUrl != "" && existed.LogoUrl != res.Url {
+		m.removePicture(ctx, prefix, existed.LogoUrl)
+	}
+	return res, nil
+}
+
+// removePicture will delete the thumbnails of a picture nobody points to, a failure only leaves files behind
+func (m merchantUsecase) removePicture(ctx context.Context, prefix string, url string) {
+	if err := m.pictureUploader.Remove(ctx, prefix, url); err != nil {
+		logrus.WithError(err).WithField("url", url).Warn("remove the thumbnails of a replaced picture")
+	}
+}
+
+// Update will update the profile of the merchant at the identity server and locally.
+// The password and the logo are left as is, they change through the password usecase and UploadLogo.
+func (m merchantUsecase) Update(c context.Context, ar *models.NewCommandMerchant, user string) error {
 	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
 	defer cancel()
 
 	updateUser := models.RegisterAndUpdateUser{
 		Id:            ar.Id,
 		Username:      ar.MerchantEmail,
-		Password:      ar.MerchantPassword,
 		Name:          ar.MerchantName,
 		GivenName:     "",
 		FamilyName:    "",
@@ -94,8 +311,8 @@ func (m merchantUsecase) Update(c context.Context, ar *models.NewCommandMerchant
 		Website:       "",
 		Address:       "",
 	}
-	_ ,err:= m.identityServerUc.UpdateUser(&updateUser)
-	if err != nil{
+	_, err := m.identityServerUc.UpdateUser(ctx, &updateUser)
+	if err != nil {
 		return err
 	}
 
@@ -105,14 +322,13 @@ func (m merchantUsecase) Update(c context.Context, ar *models.NewCommandMerchant
 	merchant.MerchantName = ar.MerchantName
 	merchant.MerchantDesc = ar.MerchantDesc
 	merchant.MerchantEmail = ar.MerchantEmail
-	merchant.Balance = ar.Balance
 	return m.merchantRepo.Update(ctx, &merchant)
 }
 
-func (m merchantUsecase) Create(c context.Context, ar *models.NewCommandMerchant,user string) error {
+func (m merchantUsecase) Create(c context.Context, ar *models.NewCommandMerchant, user string) error {
 	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
 	defer cancel()
-	existedMerchant, _ := m.merchantRepo.GetByMerchantEmail(ctx, ar.MerchantEmail)
+	existedMerchant, _ := m.merchantRepo.GetByMerchantEmailIncludeDeleted(ctx, ar.MerchantEmail)
 	if existedMerchant != nil {
 		return models.ErrConflict
 	}
@@ -128,27 +344,40 @@ func (m merchantUsecase) Create(c context.Context, ar *models.NewCommandMerchant
 		Website:       "",
 		Address:       "",
 	}
-	isUser ,errorIs:= m.identityServerUc.CreateUser(&registerUser)
-	ar.Id = isUser.Id
-	if errorIs != nil{
+	isUser, errorIs := m.identityServerUc.CreateUser(ctx, &registerUser)
+	if errorIs != nil {
 		return errorIs
 	}
+	ar.Id = isUser.Id
 	merchant := models.Merchant{}
 	merchant.Id = isUser.Id
-	merchant.CreatedBy = ar.MerchantEmail
+	merchant.CreatedBy = user
 	merchant.MerchantName = ar.MerchantName
 	merchant.MerchantDesc = ar.MerchantDesc
 	merchant.MerchantEmail = ar.MerchantEmail
-	merchant.Balance = ar.Balance
-	err := m.merchantRepo.Insert(ctx, &merchant)
+	err := m.txManager.Do(ctx, func(ctx context.Context) error {
+		return m.merchantRepo.Insert(ctx, &merchant)
+	})
 	if err != nil {
+		identityserver.RemoveAccount(m.identityServerUc, isUser.Id, m.contextTimeout)
 		return err
 	}
 
-
 	return nil
 }
 
+func toMerchantInfoDto(a *models.Merchant) *models.MerchantInfoDto {
+	return &models.MerchantInfoDto{
+		Id:            a.Id,
+		MerchantName:  a.MerchantName,
+		MerchantDesc:  a.MerchantDesc,
+		MerchantEmail: a.MerchantEmail,
+		LogoUrl:       a.LogoUrl,
+		Balance:       a.Balance,
+		IsActive:      a.IsActive,
+		IsDeleted:     a.IsDeleted,
+	}
+}
 
 /*
 * In this function below, I'm using errgroup with the pipeline pattern
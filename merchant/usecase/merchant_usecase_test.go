@@ -1 +1,137 @@
 package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	_isMock "github.com/identityserver/mocks"
+	"github.com/merchant/mocks"
+	ucase "github.com/merchant/usecase"
+	"github.com/models"
+	_pictureMock "github.com/picture/mocks"
+	_txMock "github.com/transaction/mocks"
+)
+
+func TestLogin(t *testing.T) {
+	login := &models.Login{Email: "shop@mail.com", Password: "secret", Type: models.PrincipalMerchant}
+
+	t.Run("deactivated", func(t *testing.T) {
+		mockMerchantRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockTokenRepo := new(_isMock.Repository)
+		mockIs.On("GetToken", mock.Anything, login.Email, login.Password).
+			Return(&models.GetToken{AccessToken: "access", RefreshToken: "refresh"}, nil).Once()
+		mockMerchantRepo.On("GetByMerchantEmailIncludeDeleted", mock.Anything, login.Email).
+			Return(&models.Merchant{Id: "m1", IsActive: 0}, nil).Once()
+
+		u := ucase.NewmerchantUsecase(mockMerchantRepo, mockIs, mockTokenRepo, new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		_, err := u.Login(context.TODO(), login)
+		assert.Equal(t, models.ErrForbidden, err)
+		mockTokenRepo.AssertNotCalled(t, "StoreRefreshToken", mock.Anything, mock.Anything)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		mockMerchantRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockIs.On("GetToken", mock.Anything, login.Email, login.Password).
+			Return(&models.GetToken{AccessToken: "access"}, nil).Once()
+		mockMerchantRepo.On("GetByMerchantEmailIncludeDeleted", mock.Anything, login.Email).
+			Return(&models.Merchant{Id: "m1", IsDeleted: 1}, nil).Once()
+
+		u := ucase.NewmerchantUsecase(mockMerchantRepo, mockIs, new(_isMock.Repository), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		_, err := u.Login(context.TODO(), login)
+		assert.Equal(t, models.ErrNotFound, err)
+	})
+
+	t.Run("active", func(t *testing.T) {
+		mockMerchantRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockTokenRepo := new(_isMock.Repository)
+		mockIs.On("GetToken", mock.Anything, login.Email, login.Password).
+			Return(&models.GetToken{AccessToken: "access", RefreshToken: "refresh"}, nil).Once()
+		mockMerchantRepo.On("GetByMerchantEmailIncludeDeleted", mock.Anything, login.Email).
+			Return(&models.Merchant{Id: "m1", IsActive: 1}, nil).Once()
+		mockTokenRepo.On("StoreRefreshToken", mock.Anything, mock.AnythingOfType("*models.RefreshToken")).Return(nil).Once()
+
+		u := ucase.NewmerchantUsecase(mockMerchantRepo, mockIs, mockTokenRepo, new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		token, err := u.Login(context.TODO(), login)
+		assert.NoError(t, err)
+		assert.Equal(t, "access", token.AccessToken)
+		mockTokenRepo.AssertExpectations(t)
+	})
+}
+
+func TestSetActive(t *testing.T) {
+	mockMerchantRepo := new(mocks.Repository)
+	mockMerchantRepo.On("SetActive", mock.Anything, "m1", 0, "admin").Return(nil).Once()
+
+	u := ucase.NewmerchantUsecase(mockMerchantRepo, new(_isMock.Usecase), new(_isMock.Repository), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+	err := u.SetActive(context.TODO(), "m1", false, "admin")
+	assert.NoError(t, err)
+	mockMerchantRepo.AssertExpectations(t)
+}
+
+func TestCreate(t *testing.T) {
+	inTransaction := func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) }
+
+	t.Run("success", func(t *testing.T) {
+		mockMerchantRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockTx := new(_txMock.Manager)
+		mockMerchantRepo.On("GetByMerchantEmailIncludeDeleted", mock.Anything, "shop@mail.com").Return(nil, models.ErrNotFound).Once()
+		mockIs.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.RegisterAndUpdateUser")).
+			Return(&models.RegisterAndUpdateUser{Id: "m1"}, nil).Once()
+		mockTx.On("Do", mock.Anything, mock.Anything).Return(inTransaction).Once()
+		mockMerchantRepo.On("Insert", mock.Anything, mock.MatchedBy(func(m *models.Merchant) bool { return m.Id == "m1" })).Return(nil).Once()
+
+		u := ucase.NewmerchantUsecase(mockMerchantRepo, mockIs, new(_isMock.Repository), new(_pictureMock.Uploader), mockTx, time.Second*2)
+		ar := &models.NewCommandMerchant{MerchantEmail: "shop@mail.com", MerchantPassword: "secret", MerchantName: "Shop"}
+		assert.NoError(t, u.Create(context.TODO(), ar, "admin"))
+		assert.Equal(t, "m1", ar.Id)
+		mockMerchantRepo.AssertExpectations(t)
+		mockIs.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("insert-failed", func(t *testing.T) {
+		mockMerchantRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockTx := new(_txMock.Manager)
+		mockMerchantRepo.On("GetByMerchantEmailIncludeDeleted", mock.Anything, "shop@mail.com").Return(nil, models.ErrNotFound).Once()
+		mockIs.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.RegisterAndUpdateUser")).
+			Return(&models.RegisterAndUpdateUser{Id: "m1"}, nil).Once()
+		mockTx.On("Do", mock.Anything, mock.Anything).Return(inTransaction).Once()
+		mockMerchantRepo.On("Insert", mock.Anything, mock.Anything).Return(models.ErrInternalServerError).Once()
+		mockIs.On("DeleteUser", mock.Anything, "m1").Return(nil).Once()
+
+		u := ucase.NewmerchantUsecase(mockMerchantRepo, mockIs, new(_isMock.Repository), new(_pictureMock.Uploader), mockTx, time.Second*2)
+		err := u.Create(context.TODO(), &models.NewCommandMerchant{MerchantEmail: "shop@mail.com"}, "admin")
+		assert.Equal(t, models.ErrInternalServerError, err)
+		mockIs.AssertExpectations(t)
+	})
+}
+
+func TestUploadLogo(t *testing.T) {
+	mockMerchantRepo := new(mocks.Repository)
+	mockUploader := new(_pictureMock.Uploader)
+	pict := &models.Picture{Url: "/uploads/merchants/m1/logo-9f86d081884c7d65-512.png"}
+	previous := "/uploads/merchants/m1/logo-512.png"
+	file := strings.NewReader("png")
+	mockMerchantRepo.On("GetByID", mock.Anything, "m1").Return(&models.Merchant{Id: "m1", LogoUrl: previous}, nil).Once()
+	mockUploader.On("Upload", mock.Anything, "merchants/m1/logo", file).Return(pict, nil).Once()
+	mockMerchantRepo.On("UpdateLogoUrl", mock.Anything, "m1", pict.Url, "shop@mail.com").Return(nil).Once()
+	mockUploader.On("Remove", mock.Anything, "merchants/m1/logo", previous).Return(errors.New("Unexpected Error")).Once()
+
+	u := ucase.NewmerchantUsecase(mockMerchantRepo, new(_isMock.Usecase), new(_isMock.Repository), mockUploader, new(_txMock.Manager), time.Second*2)
+	res, err := u.UploadLogo(context.TODO(), "m1", file, "shop@mail.com")
+	// the previous thumbnails left behind do not fail the upload
+	assert.NoError(t, err)
+	assert.Equal(t, pict, res)
+	mockMerchantRepo.AssertExpectations(t)
+	mockUploader.AssertExpectations(t)
+}
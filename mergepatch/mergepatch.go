@@ -0,0 +1,64 @@
+// Package mergepatch applies a JSON Merge Patch (RFC 7396) to a JSON document.
+//
+// The members of the patch object replace those of the document, a null member removes it
+// and a nested object is merged member by member. Any other patch value, arrays included,
+// replaces the document as a whole.
+package mergepatch
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/models"
+)
+
+// MediaType is the content type of a JSON Merge Patch request body
+const MediaType = "application/merge-patch+json"
+
+// ErrInvalidPatch will throw if the patch is not a JSON document
+var ErrInvalidPatch = models.NewBadParam("The patch is not a valid JSON document")
+
+// Apply will return the document doc once patch is merged into it
+func Apply(doc []byte, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := decode(doc, &target); err != nil {
+		return nil, err
+	}
+	var p interface{}
+	if err := decode(patch, &p); err != nil {
+		return nil, ErrInvalidPatch
+	}
+	return json.Marshal(merge(target, p))
+}
+
+// decode will read a single JSON value, keeping the numbers as they are written
+func decode(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return ErrInvalidPatch
+	}
+	return nil
+}
+
+func merge(target interface{}, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+	for name, value := range p {
+		if value == nil {
+			delete(t, name)
+			continue
+		}
+		t[name] = merge(t[name], value)
+	}
+	return t
+}
@@ -0,0 +1,54 @@
+package mergepatch_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mergepatch"
+	"github.com/models"
+)
+
+func TestApply(t *testing.T) {
+	// the examples of the appendix A of RFC 7396
+	cases := []struct {
+		doc, patch, result string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, c := range cases {
+		res, err := mergepatch.Apply([]byte(c.doc), []byte(c.patch))
+		require.NoError(t, err, c.patch)
+		assert.JSONEq(t, c.result, string(res), c.patch)
+	}
+}
+
+func TestApplyKeepsNumbers(t *testing.T) {
+	res, err := mergepatch.Apply([]byte(`{"id":9007199254740993,"title":"a"}`), []byte(`{"title":"b"}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":9007199254740993,"title":"b"}`, string(res))
+}
+
+func TestApplyInvalid(t *testing.T) {
+	for _, patch := range []string{``, `{"a":`, `{"a":1} {"b":2}`} {
+		_, err := mergepatch.Apply([]byte(`{}`), []byte(patch))
+		assert.Equal(t, mergepatch.ErrInvalidPatch, err, patch)
+		assert.True(t, errors.Is(err, models.ErrBadParamInput))
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+
+	"github.com/models"
+)
+
+// Policy represent the authorization rules of a route, it is declared next to the route registration
+type Policy struct {
+	// Roles lists the roles allowed to call the route, empty means any caller including anonymous ones
+	Roles []string
+	// OwnerParam names the path param holding the account id of the resource,
+	// callers that are not admin may only reach their own account
+	OwnerParam string
+	// AdminFields lists the request fields only an admin may set
+	AdminFields []string
+}
+
+// Authorize will enforce the given policy on the principal stored by Authenticate
+// before the handler, and so the usecase, is called
+func (m *GoMiddleware) Authorize(policy Policy) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal := GetPrincipal(c)
+			isAdmin := principal.HasRole(models.RoleAdmin)
+
+			if len(policy.Roles) > 0 && !hasAnyRole(principal, policy.Roles) {
+				if principal == nil {
+					return c.JSON(http.StatusUnauthorized, ResponseError{Message: models.ErrUnAuthorize.Error()})
+				}
+				return c.JSON(http.StatusForbidden, ResponseError{Message: models.ErrForbidden.Error()})
+			}
+
+			if policy.OwnerParam != "" && !isAdmin {
+				if principal == nil || principal.Id != c.Param(policy.OwnerParam) {
+					return c.JSON(http.StatusForbidden, ResponseError{Message: models.ErrForbidden.Error()})
+				}
+			}
+
+			if len(policy.AdminFields) > 0 && !isAdmin {
+				fields, err := requestFields(c)
+				if err != nil {
+					return c.JSON(http.StatusBadRequest, ResponseError{Message: models.ErrBadParamInput.Error()})
+				}
+				for _, field := range policy.AdminFields {
+					if fields[field] {
+						return c.JSON(http.StatusForbidden, ResponseError{Message: "Only an admin may set " + field})
+					}
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func hasAnyRole(principal *models.Principal, roles []string) bool {
+	for _, role := range roles {
+		if principal.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestFields will list the fields sent in the query, the form or the JSON body of the request.
+// A JSON body is restored after being read so the handler can still bind it.
+func requestFields(c echo.Context) (map[string]bool, error) {
+	fields := map[string]bool{}
+	for key := range c.QueryParams() {
+		fields[key] = true
+	}
+
+	req := c.Request()
+	if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		if req.Body == nil {
+			return fields, nil
+		}
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if len(bytes.TrimSpace(body)) == 0 {
+			return fields, nil
+		}
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, err
+		}
+		for key := range payload {
+			fields[key] = true
+		}
+		return fields, nil
+	}
+
+	form, err := c.FormParams()
+	if err != nil {
+		return nil, err
+	}
+	for key := range form {
+		fields[key] = true
+	}
+	return fields, nil
+}
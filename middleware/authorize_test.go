@@ -0,0 +1,121 @@
+package middleware_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	test "net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/middleware"
+	"github.com/middleware/middlewaretest"
+	"github.com/models"
+)
+
+func serveWithPrincipal(t *testing.T, principal *models.Principal, policy middleware.Policy, req *http.Request, id string) *test.ResponseRecorder {
+	e := echo.New()
+	res := test.NewRecorder()
+	c := e.NewContext(req, res)
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	if principal != nil {
+		c.Set("principal", principal)
+	}
+
+	m := middleware.InitMiddleware(nil, nil, nil)
+	h := m.Authorize(policy)(func(c echo.Context) error {
+		body, err := ioutil.ReadAll(c.Request().Body)
+		require.NoError(t, err)
+		return c.String(http.StatusOK, string(body))
+	})
+	require.NoError(t, h(c))
+	return res
+}
+
+func TestAuthorizeRoles(t *testing.T) {
+	policy := middleware.Policy{Roles: []string{models.RoleUser, models.RoleAdmin}}
+
+	res := serveWithPrincipal(t, &models.Principal{Id: "m1", Roles: []string{models.RoleMerchant}}, policy,
+		test.NewRequest(echo.PUT, "/", nil), "m1")
+	assert.Equal(t, http.StatusForbidden, res.Code)
+
+	res = serveWithPrincipal(t, nil, policy, test.NewRequest(echo.PUT, "/", nil), "u1")
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+
+	res = serveWithPrincipal(t, &models.Principal{Id: "u1", Roles: []string{models.RoleUser}}, policy,
+		test.NewRequest(echo.PUT, "/", nil), "u1")
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestAuthorizeOwner(t *testing.T) {
+	policy := middleware.Policy{Roles: []string{models.RoleUser, models.RoleAdmin}, OwnerParam: "id"}
+
+	res := serveWithPrincipal(t, &models.Principal{Id: "u1", Roles: []string{models.RoleUser}}, policy,
+		test.NewRequest(echo.PUT, "/", nil), "u2")
+	assert.Equal(t, http.StatusForbidden, res.Code)
+
+	res = serveWithPrincipal(t, &models.Principal{Id: "a1", Roles: []string{models.RoleUser, models.RoleAdmin}}, policy,
+		test.NewRequest(echo.PUT, "/", nil), "u2")
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestAuthorizeAdminFields(t *testing.T) {
+	policy := middleware.Policy{AdminFields: []string{"points"}}
+	user := &models.Principal{Id: "u1", Roles: []string{models.RoleUser}}
+	admin := &models.Principal{Id: "a1", Roles: []string{models.RoleAdmin}}
+
+	t.Run("form", func(t *testing.T) {
+		req := test.NewRequest(echo.PUT, "/", strings.NewReader("full_name=John&points=1000"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		res := serveWithPrincipal(t, user, policy, req, "u1")
+		assert.Equal(t, http.StatusForbidden, res.Code)
+
+		req = test.NewRequest(echo.PUT, "/", strings.NewReader("full_name=John"))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		res = serveWithPrincipal(t, user, policy, req, "u1")
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		req := test.NewRequest(echo.PUT, "/", strings.NewReader(`{"points": 1000}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res := serveWithPrincipal(t, nil, policy, req, "u1")
+		assert.Equal(t, http.StatusForbidden, res.Code)
+
+		req = test.NewRequest(echo.PUT, "/", strings.NewReader(`{"points": 1000}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res = serveWithPrincipal(t, admin, policy, req, "u1")
+		assert.Equal(t, http.StatusOK, res.Code)
+
+		req = test.NewRequest(echo.PUT, "/", strings.NewReader(`{"full_name": "John"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		res = serveWithPrincipal(t, user, policy, req, "u1")
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, `{"full_name": "John"}`, res.Body.String())
+	})
+}
+
+func TestAuthorizeRoute(t *testing.T) {
+	e := echo.New()
+	mw := middlewaretest.New()
+	adminOnly := mw.Authorize(middleware.Policy{Roles: []string{models.RoleAdmin}})
+	e.PUT("/items/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}, mw.Authenticate, adminOnly)
+
+	res := middlewaretest.Serve(e, middlewaretest.NewRequest(echo.PUT, "/items/1", ""), middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusNoContent, res.Code)
+
+	res = middlewaretest.Serve(e, middlewaretest.NewRequest(echo.PUT, "/items/1", ""), middlewaretest.UserToken)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+
+	res = middlewaretest.Serve(e, middlewaretest.NewRequest(echo.PUT, "/items/1", ""), middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusForbidden, res.Code)
+
+	res = middlewaretest.Serve(e, middlewaretest.NewRequest(echo.PUT, "/items/1", ""), "expired")
+	assert.Equal(t, http.StatusUnauthorized, res.Code)
+}
@@ -1,10 +1,31 @@
 package middleware
 
-import "github.com/labstack/echo"
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+
+	"github.com/identityserver"
+	"github.com/merchant"
+	"github.com/models"
+	"github.com/user"
+)
+
+// principalKey is the echo.Context key holding the authenticated *models.Principal
+const principalKey = "principal"
 
 // GoMiddleware represent the data-struct for middleware
 type GoMiddleware struct {
-	// another stuff , may be needed by middleware
+	userUsecase     user.Usecase
+	merchantUsecase merchant.Usecase
+	admins          map[string]bool
+}
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
 }
 
 // CORS will handle the CORS middleware
@@ -15,7 +36,80 @@ func (m *GoMiddleware) CORS(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
-// InitMiddleware intialize the middleware
-func InitMiddleware() *GoMiddleware {
-	return &GoMiddleware{}
+// Authenticate will validate the bearer token of the request against the identity server,
+// resolve whether the caller is a user or a merchant and store the principal in the echo.Context
+func (m *GoMiddleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token, ok := BearerToken(c.Request().Header.Get(echo.HeaderAuthorization))
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, ResponseError{Message: models.ErrUnAuthorize.Error()})
+		}
+
+		principal, err := m.resolve(c, token)
+		if err != nil {
+			switch {
+			case errors.Is(err, models.ErrUnAuthorize) || errors.Is(err, models.ErrNotFound):
+				return c.JSON(http.StatusUnauthorized, ResponseError{Message: models.ErrUnAuthorize.Error()})
+			case errors.Is(err, identityserver.ErrUnreachable):
+				return c.JSON(http.StatusBadGateway, ResponseError{Message: err.Error()})
+			default:
+				return err
+			}
+		}
+
+		principal.Roles = m.roles(principal)
+		c.Set(principalKey, principal)
+		return next(c)
+	}
+}
+
+func (m *GoMiddleware) resolve(c echo.Context, token string) (*models.Principal, error) {
+	ctx := c.Request().Context()
+
+	principal, err := m.userUsecase.ValidateTokenUser(ctx, token)
+	if err == nil {
+		return principal, nil
+	}
+	if !errors.Is(err, models.ErrNotFound) {
+		return nil, err
+	}
+	return m.merchantUsecase.ValidateTokenMerchant(ctx, token)
+}
+
+// roles will grant the role of the account type, and admin to the accounts listed in auth.admins
+func (m *GoMiddleware) roles(principal *models.Principal) []string {
+	roles := []string{principal.Type}
+	if m.admins[strings.ToLower(principal.Email)] || m.admins[strings.ToLower(principal.Username)] {
+		roles = append(roles, models.RoleAdmin)
+	}
+	return roles
+}
+
+// GetPrincipal will return the principal stored by Authenticate, or nil for an anonymous request
+func GetPrincipal(c echo.Context) *models.Principal {
+	principal, _ := c.Get(principalKey).(*models.Principal)
+	return principal
+}
+
+// BearerToken will extract the token of a "Bearer <token>" Authorization header
+func BearerToken(header string) (string, bool) {
+	const prefix = "bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	token := strings.TrimSpace(header[len(prefix):])
+	return token, token != ""
+}
+
+// InitMiddleware intialize the middleware, admins lists the emails or usernames granted the admin role
+func InitMiddleware(u user.Usecase, m merchant.Usecase, admins []string) *GoMiddleware {
+	adminSet := make(map[string]bool, len(admins))
+	for _, admin := range admins {
+		adminSet[strings.ToLower(strings.TrimSpace(admin))] = true
+	}
+	return &GoMiddleware{
+		userUsecase:     u,
+		merchantUsecase: m,
+		admins:          adminSet,
+	}
 }
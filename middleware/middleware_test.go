@@ -7,9 +7,13 @@ import (
 
 	"github.com/labstack/echo"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
-	"github.com/bxcodec/go-clean-arch/middleware"
+	_merchantMock "github.com/merchant/mocks"
+	"github.com/middleware"
+	"github.com/models"
+	_userMock "github.com/user/mocks"
 )
 
 func TestCORS(t *testing.T) {
@@ -17,7 +21,7 @@ func TestCORS(t *testing.T) {
 	req := test.NewRequest(echo.GET, "/", nil)
 	res := test.NewRecorder()
 	c := e.NewContext(req, res)
-	m := middleware.InitMiddleware()
+	m := middleware.InitMiddleware(nil, nil, nil)
 
 	h := m.CORS(echo.HandlerFunc(func(c echo.Context) error {
 		return c.NoContent(http.StatusOK)
@@ -27,3 +31,110 @@ func TestCORS(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "*", res.Header().Get("Access-Control-Allow-Origin"))
 }
+
+func TestAuthenticate(t *testing.T) {
+	okHandler := echo.HandlerFunc(func(c echo.Context) error {
+		return c.JSON(http.StatusOK, middleware.GetPrincipal(c))
+	})
+
+	t.Run("missing-token", func(t *testing.T) {
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		res := test.NewRecorder()
+		m := middleware.InitMiddleware(new(_userMock.Usecase), new(_merchantMock.Usecase), nil)
+
+		err := m.Authenticate(okHandler)(e.NewContext(req, res))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+
+	t.Run("user", func(t *testing.T) {
+		mockUserUcase := new(_userMock.Usecase)
+		mockUserUcase.On("ValidateTokenUser", mock.Anything, "user-token").
+			Return(&models.Principal{Id: "u1", Type: models.PrincipalUser, Token: "user-token"}, nil).Once()
+
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer user-token")
+		res := test.NewRecorder()
+		m := middleware.InitMiddleware(mockUserUcase, new(_merchantMock.Usecase), nil)
+
+		err := m.Authenticate(okHandler)(e.NewContext(req, res))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Contains(t, res.Body.String(), `"id":"u1"`)
+		mockUserUcase.AssertExpectations(t)
+	})
+
+	t.Run("merchant", func(t *testing.T) {
+		mockUserUcase := new(_userMock.Usecase)
+		mockUserUcase.On("ValidateTokenUser", mock.Anything, "merchant-token").
+			Return(nil, models.ErrNotFound).Once()
+		mockMerchantUcase := new(_merchantMock.Usecase)
+		mockMerchantUcase.On("ValidateTokenMerchant", mock.Anything, "merchant-token").
+			Return(&models.Principal{Id: "m1", Type: models.PrincipalMerchant}, nil).Once()
+
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, "bearer merchant-token")
+		res := test.NewRecorder()
+		m := middleware.InitMiddleware(mockUserUcase, mockMerchantUcase, nil)
+
+		err := m.Authenticate(okHandler)(e.NewContext(req, res))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Contains(t, res.Body.String(), `"type":"merchant"`)
+		mockUserUcase.AssertExpectations(t)
+		mockMerchantUcase.AssertExpectations(t)
+	})
+
+	t.Run("invalid-token", func(t *testing.T) {
+		mockUserUcase := new(_userMock.Usecase)
+		mockUserUcase.On("ValidateTokenUser", mock.Anything, "expired").
+			Return(nil, models.ErrUnAuthorize).Once()
+
+		e := echo.New()
+		req := test.NewRequest(echo.GET, "/", nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer expired")
+		res := test.NewRecorder()
+		m := middleware.InitMiddleware(mockUserUcase, new(_merchantMock.Usecase), nil)
+
+		err := m.Authenticate(okHandler)(e.NewContext(req, res))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, res.Code)
+	})
+}
+
+func TestBearerToken(t *testing.T) {
+	token, ok := middleware.BearerToken("Bearer abc")
+	assert.True(t, ok)
+	assert.Equal(t, "abc", token)
+
+	_, ok = middleware.BearerToken("abc")
+	assert.False(t, ok)
+	_, ok = middleware.BearerToken("Basic abc")
+	assert.False(t, ok)
+	_, ok = middleware.BearerToken("Bearer ")
+	assert.False(t, ok)
+}
+
+func TestAuthenticateGrantsAdmin(t *testing.T) {
+	mockUserUcase := new(_userMock.Usecase)
+	mockUserUcase.On("ValidateTokenUser", mock.Anything, "admin-token").
+		Return(&models.Principal{Id: "u1", Email: "Admin@cgo.id", Type: models.PrincipalUser}, nil).Once()
+
+	e := echo.New()
+	req := test.NewRequest(echo.GET, "/", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer admin-token")
+	res := test.NewRecorder()
+	m := middleware.InitMiddleware(mockUserUcase, new(_merchantMock.Usecase), []string{"admin@cgo.id"})
+
+	var principal *models.Principal
+	err := m.Authenticate(func(c echo.Context) error {
+		principal = middleware.GetPrincipal(c)
+		return nil
+	})(e.NewContext(req, res))
+	require.NoError(t, err)
+	assert.True(t, principal.HasRole(models.RoleUser))
+	assert.True(t, principal.HasRole(models.RoleAdmin))
+}
@@ -0,0 +1,72 @@
+// Package middlewaretest serves the handlers in their tests behind the authentication middleware, with a token
+// for an admin, a user and a merchant.
+package middlewaretest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/mock"
+
+	_merchantMock "github.com/merchant/mocks"
+	"github.com/middleware"
+	"github.com/models"
+	_userMock "github.com/user/mocks"
+)
+
+// The tokens the middleware of New accepts, any other token is unauthorized
+const (
+	AdminToken    = "admin-token"
+	UserToken     = "user-token"
+	MerchantToken = "merchant-token"
+)
+
+// The principals of the tokens
+var (
+	Admin    = models.Principal{Id: "a1", Username: "admin@mail.com", Email: "admin@mail.com", Type: models.PrincipalUser}
+	User     = models.Principal{Id: "u1", Username: "john@mail.com", Email: "john@mail.com", Type: models.PrincipalUser}
+	Merchant = models.Principal{Id: "m1", Username: "shop@mail.com", Email: "shop@mail.com", Type: models.PrincipalMerchant}
+)
+
+// New will create the middleware validating the tokens, with the admin as the only admin
+func New() *middleware.GoMiddleware {
+	mockUserUCase := new(_userMock.Usecase)
+	mockMerchantUCase := new(_merchantMock.Usecase)
+	mockUserUCase.On("ValidateTokenUser", mock.Anything, AdminToken).Return(principal(Admin), nil)
+	mockUserUCase.On("ValidateTokenUser", mock.Anything, UserToken).Return(principal(User), nil)
+	mockUserUCase.On("ValidateTokenUser", mock.Anything, mock.Anything).Return(nil, models.ErrNotFound)
+	mockMerchantUCase.On("ValidateTokenMerchant", mock.Anything, MerchantToken).Return(principal(Merchant), nil)
+	mockMerchantUCase.On("ValidateTokenMerchant", mock.Anything, mock.Anything).Return(nil, models.ErrUnAuthorize)
+
+	return middleware.InitMiddleware(mockUserUCase, mockMerchantUCase, []string{Admin.Email})
+}
+
+// principal will return a copy of p for every validation, so the roles set by the middleware stay in the request
+func principal(p models.Principal) func(context.Context, string) *models.Principal {
+	return func(context.Context, string) *models.Principal {
+		res := p
+		return &res
+	}
+}
+
+// NewRequest will create a request to target, a body is sent as JSON
+func NewRequest(method string, target string, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	return req
+}
+
+// Serve will send the request to e with the token as its bearer, an empty token sends none
+func Serve(e *echo.Echo, req *http.Request, token string) *httptest.ResponseRecorder {
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
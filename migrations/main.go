@@ -1,21 +1,113 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"flag"
 	"fmt"
-	"github.com/jinzhu/gorm"
-	model "github.com/models"
-	_ "github.com/jinzhu/gorm/dialects/mysql"
+	"log"
+	"os"
+	"strconv"
+
+	_ "github.com/go-sql-driver/mysql"
+
+	"github.com/config"
+	"github.com/migrations/migrate"
+)
+
+var (
+	configPath = flag.String("config", "config.json", "path to the config file")
+	dir        = flag.String("dir", "migrations/sql", "directory of the migration files")
 )
 
+const usage = `Usage: migrations [-config config.json] [-dir migrations/sql] <command>
+
+Commands:
+  up             apply every pending migration
+  down N         roll back the last N applied migrations
+  status         list the migrations and when they were applied
+  create NAME    write a new pair of up and down files
+`
+
 func main() {
-	db, err := gorm.Open("mysql", "AdminCgo@api-blog-cgo-mysqldbserver:Standar123.@(api-blog-cgo-mysqldbserver.mysql.database.azure.com)/cgo_indonesia?charset=utf8&parseTime=True&loc=Local")
-	if err != nil{
-		fmt.Println(err)
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
 	}
-	user := model.User{}
-	db.AutoMigrate(&user)
 
-	fmt.Println("test")
-	db.Close()
+	if args[0] == "create" {
+		if len(args) != 2 {
+			log.Fatal("create needs the name of the migration")
+		}
+		migration, err := migrate.Create(*dir, args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(migration.UpFile)
+		fmt.Println(migration.DownFile)
+		return
+	}
+
+	database, err := config.LoadDatabase(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	dbConn, err := sql.Open(`mysql`, database.DSN())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer dbConn.Close()
+
+	m := migrate.New(dbConn, *dir)
+	ctx := context.Background()
 
+	switch args[0] {
+	case "up":
+		done, err := m.Up(ctx)
+		for _, migration := range done {
+			fmt.Printf("applied %06d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(done) == 0 {
+			fmt.Println("no pending migration")
+		}
+	case "down":
+		if len(args) != 2 {
+			log.Fatal("down needs the number of migrations to roll back")
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Fatalf("invalid number of migrations %q", args[1])
+		}
+		done, err := m.Down(ctx, n)
+		for _, migration := range done {
+			fmt.Printf("rolled back %06d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range status {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%06d_%-40s %s\n", s.Version, s.Name, appliedAt)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
 }
@@ -0,0 +1,286 @@
+// Package migrate applies the versioned SQL migrations of the schema.
+//
+// A migration is a pair of files named <version>_<name>.up.sql and <version>_<name>.down.sql.
+// Migrations are applied in version order and the applied versions are recorded in the
+// schema_migrations table, so every database can be brought to the same schema.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const createVersionTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+  version BIGINT NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  applied_at DATETIME NOT NULL,
+  PRIMARY KEY (version)
+)`
+
+var (
+	fileName    = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+	invalidName = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Migration represent a pair of up and down SQL files
+type Migration struct {
+	Version  int64
+	Name     string
+	UpFile   string
+	DownFile string
+}
+
+// Status represent a migration and when it was applied, AppliedAt is nil for pending migrations
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator will apply and roll back the migrations found in a directory
+type Migrator struct {
+	db  *sql.DB
+	dir string
+}
+
+// New will create a Migrator running the migrations of dir against db
+func New(db *sql.DB, dir string) *Migrator {
+	return &Migrator{db: db, dir: dir}
+}
+
+// Load will read the migrations of dir ordered by version.
+// Every version needs both an up and a down file and versions must be unique.
+func Load(dir string) ([]Migration, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".sql") {
+			continue
+		}
+		match := fileName.FindStringSubmatch(f.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s: name must look like <version>_<name>.up.sql", f.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is used by both %s and %s", version, m.Name, match[2])
+		}
+		path := filepath.Join(dir, f.Name())
+		if match[3] == "up" {
+			m.UpFile = path
+		} else {
+			m.DownFile = path
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpFile == "" || m.DownFile == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up will apply every pending migration and return the applied ones
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	migrations, applied, err := m.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := m.run(ctx, migration.UpFile,
+			`INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+			migration.Version, migration.Name, time.Now())
+		if err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down will roll back the last n applied migrations and return the rolled back ones
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	if n <= 0 {
+		return nil, errors.New("the number of migrations to roll back must be greater than zero")
+	}
+	migrations, applied, err := m.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[int64]Migration{}
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+	}
+	versions := make([]int64, 0, len(applied))
+	for version := range applied {
+		if _, ok := known[version]; !ok {
+			return nil, fmt.Errorf("migration %d is applied but its files are missing from %s", version, m.dir)
+		}
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+	if n > len(versions) {
+		n = len(versions)
+	}
+
+	var done []Migration
+	for _, version := range versions[:n] {
+		migration := known[version]
+		err := m.run(ctx, migration.DownFile, `DELETE FROM schema_migrations WHERE version = ?`, migration.Version)
+		if err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Status will list every migration with the date it was applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	migrations, applied, err := m.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, len(migrations))
+	for i, migration := range migrations {
+		result[i] = Status{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			appliedAt := appliedAt
+			result[i].AppliedAt = &appliedAt
+		}
+	}
+	return result, nil
+}
+
+// Create will write an empty pair of migration files to dir, versioned after the last migration
+func Create(dir string, name string) (*Migration, error) {
+	name = strings.Trim(invalidName.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, errors.New("the migration needs a name")
+	}
+	migrations, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	version := int64(1)
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
+
+	migration := &Migration{
+		Version:  version,
+		Name:     name,
+		UpFile:   filepath.Join(dir, fmt.Sprintf("%06d_%s.up.sql", version, name)),
+		DownFile: filepath.Join(dir, fmt.Sprintf("%06d_%s.down.sql", version, name)),
+	}
+	for _, path := range []string{migration.UpFile, migration.DownFile} {
+		if err := ioutil.WriteFile(path, []byte("-- "+filepath.Base(path)+"\n"), 0644); err != nil {
+			return nil, err
+		}
+	}
+	return migration, nil
+}
+
+// load will return the migrations of the directory and the applied versions, creating the version table when needed
+func (m *Migrator) load(ctx context.Context) ([]Migration, map[int64]time.Time, error) {
+	migrations, err := Load(m.dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := m.db.ExecContext(ctx, createVersionTable); err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return migrations, applied, rows.Err()
+}
+
+// run will execute the statements of the file and the bookkeeping query in one transaction.
+// MySQL commits DDL statements implicitly, so a failing file may leave its earlier statements applied.
+func (m *Migrator) run(ctx context.Context, file string, record string, args ...interface{}) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, statement := range SplitStatements(string(content)) {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%s: %v", filepath.Base(file), err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// SplitStatements will split a SQL file into its statements.
+// A statement ends with a semicolon at the end of a line, lines starting with -- are comments.
+func SplitStatements(content string) []string {
+	var statements []string
+	var current []string
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current = append(current, strings.TrimRight(line, " \t\r"))
+		if strings.HasSuffix(trimmed, ";") {
+			statement := strings.TrimSuffix(strings.TrimSpace(strings.Join(current, "\n")), ";")
+			statements = append(statements, statement)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		statements = append(statements, strings.TrimSpace(strings.Join(current, "\n")))
+	}
+	return statements
+}
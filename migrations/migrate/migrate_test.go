@@ -0,0 +1,185 @@
+package migrate_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/migrations/migrate"
+)
+
+func writeMigrations(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "migrations")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("ordered", func(t *testing.T) {
+		dir := writeMigrations(t, map[string]string{
+			"000002_create_b.up.sql":   "CREATE TABLE b (id INT);",
+			"000002_create_b.down.sql": "DROP TABLE b;",
+			"000001_create_a.up.sql":   "CREATE TABLE a (id INT);",
+			"000001_create_a.down.sql": "DROP TABLE a;",
+			"README.md":                "ignored",
+		})
+		migrations, err := migrate.Load(dir)
+		require.NoError(t, err)
+		require.Len(t, migrations, 2)
+		assert.Equal(t, int64(1), migrations[0].Version)
+		assert.Equal(t, "create_a", migrations[0].Name)
+		assert.Equal(t, int64(2), migrations[1].Version)
+	})
+
+	t.Run("missing-down", func(t *testing.T) {
+		dir := writeMigrations(t, map[string]string{"000001_create_a.up.sql": "CREATE TABLE a (id INT);"})
+		_, err := migrate.Load(dir)
+		assert.Error(t, err)
+	})
+
+	t.Run("duplicate-version", func(t *testing.T) {
+		dir := writeMigrations(t, map[string]string{
+			"000001_create_a.up.sql":   "",
+			"000001_create_a.down.sql": "",
+			"000001_create_b.up.sql":   "",
+			"000001_create_b.down.sql": "",
+		})
+		_, err := migrate.Load(dir)
+		assert.Error(t, err)
+	})
+
+	t.Run("schema", func(t *testing.T) {
+		migrations, err := migrate.Load("../sql")
+		require.NoError(t, err)
+		assert.NotEmpty(t, migrations)
+	})
+}
+
+func TestSplitStatements(t *testing.T) {
+	statements := migrate.SplitStatements(`-- create the tables
+CREATE TABLE a (
+  id INT
+);
+
+DROP TABLE b;
+UPDATE c SET name = 'x;y'`)
+	assert.Equal(t, []string{"CREATE TABLE a (\n  id INT\n)", "DROP TABLE b", "UPDATE c SET name = 'x;y'"}, statements)
+}
+
+func TestUp(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"000001_create_a.up.sql":   "CREATE TABLE a (id INT);",
+		"000001_create_a.down.sql": "DROP TABLE a;",
+		"000002_create_b.up.sql":   "CREATE TABLE b (id INT);\nCREATE INDEX idx_b ON b (id);",
+		"000002_create_b.down.sql": "DROP TABLE b;",
+	})
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE b \\(id INT\\)").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE INDEX idx_b ON b \\(id\\)").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").WithArgs(2, "create_b", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	done, err := migrate.New(db, dir).Up(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, done, 1)
+	assert.Equal(t, "create_b", done[0].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDown(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"000001_create_a.up.sql":   "CREATE TABLE a (id INT);",
+		"000001_create_a.down.sql": "DROP TABLE a;",
+		"000002_create_b.up.sql":   "CREATE TABLE b (id INT);",
+		"000002_create_b.down.sql": "DROP TABLE b;",
+	})
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()).AddRow(2, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP TABLE b").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations WHERE version = \\?").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	done, err := migrate.New(db, dir).Down(context.TODO(), 1)
+	require.NoError(t, err)
+	require.Len(t, done, 1)
+	assert.Equal(t, int64(2), done[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = migrate.New(db, dir).Down(context.TODO(), 0)
+	assert.Error(t, err)
+}
+
+func TestStatus(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"000001_create_a.up.sql":   "CREATE TABLE a (id INT);",
+		"000001_create_a.down.sql": "DROP TABLE a;",
+		"000002_create_b.up.sql":   "CREATE TABLE b (id INT);",
+		"000002_create_b.down.sql": "DROP TABLE b;",
+	})
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+
+	status, err := migrate.New(db, dir).Status(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, status, 2)
+	assert.NotNil(t, status[0].AppliedAt)
+	assert.Nil(t, status[1].AppliedAt)
+}
+
+func TestCreate(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"000007_create_a.up.sql":   "",
+		"000007_create_a.down.sql": "",
+	})
+
+	migration, err := migrate.Create(dir, "Add Points Ledger")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), migration.Version)
+	assert.Equal(t, filepath.Join(dir, "000008_add_points_ledger.up.sql"), migration.UpFile)
+	assert.FileExists(t, migration.DownFile)
+
+	_, err = migrate.Create(dir, "  ")
+	assert.Error(t, err)
+}
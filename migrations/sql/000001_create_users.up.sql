@@ -0,0 +1,27 @@
+CREATE TABLE users (
+  id VARCHAR(64) NOT NULL,
+  created_by VARCHAR(255) NOT NULL,
+  created_date DATETIME NOT NULL,
+  modified_by VARCHAR(255) NULL,
+  modified_date DATETIME NULL,
+  deleted_by VARCHAR(255) NULL,
+  deleted_date DATETIME NULL,
+  is_deleted TINYINT(1) NOT NULL DEFAULT 0,
+  is_active TINYINT(1) NOT NULL DEFAULT 1,
+  user_email VARCHAR(255) NOT NULL,
+  full_name VARCHAR(255) NOT NULL DEFAULT '',
+  phone_number BIGINT NOT NULL DEFAULT 0,
+  verification_send_date DATETIME NOT NULL,
+  verification_code INT NOT NULL DEFAULT 0,
+  profile_pict_url VARCHAR(2048) NOT NULL DEFAULT '',
+  address VARCHAR(1000) NOT NULL DEFAULT '',
+  dob DATETIME NOT NULL,
+  gender INT NOT NULL DEFAULT 0,
+  id_type INT NOT NULL DEFAULT 0,
+  id_number VARCHAR(64) NOT NULL DEFAULT '',
+  referral_code INT NOT NULL DEFAULT 0,
+  points INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_users_user_email (user_email),
+  KEY idx_users_created_date (created_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,19 @@
+CREATE TABLE merchants (
+  id VARCHAR(64) NOT NULL,
+  created_by VARCHAR(255) NOT NULL,
+  created_date DATETIME NOT NULL,
+  modified_by VARCHAR(255) NULL,
+  modified_date DATETIME NULL,
+  deleted_by VARCHAR(255) NULL,
+  deleted_date DATETIME NULL,
+  is_deleted TINYINT(1) NOT NULL DEFAULT 0,
+  is_active TINYINT(1) NOT NULL DEFAULT 1,
+  merchant_name VARCHAR(255) NOT NULL,
+  merchant_desc VARCHAR(1000) NOT NULL DEFAULT '',
+  merchant_email VARCHAR(255) NOT NULL,
+  balance DECIMAL(18,2) NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_merchants_merchant_email (merchant_email),
+  KEY idx_merchants_created_date (created_date),
+  KEY idx_merchants_is_active (is_active)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
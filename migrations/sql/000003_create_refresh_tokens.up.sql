@@ -0,0 +1,11 @@
+-- refresh tokens are stored as the hex sha256 of the token
+CREATE TABLE refresh_tokens (
+  token_hash CHAR(64) NOT NULL,
+  account_id VARCHAR(64) NOT NULL,
+  account_type VARCHAR(16) NOT NULL,
+  created_date DATETIME NOT NULL,
+  revoked_date DATETIME NULL,
+  is_revoked TINYINT(1) NOT NULL DEFAULT 0,
+  PRIMARY KEY (token_hash),
+  KEY idx_refresh_tokens_account (account_type, account_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,12 @@
+CREATE TABLE article (
+  id INT NOT NULL AUTO_INCREMENT,
+  title VARCHAR(255) NOT NULL,
+  content LONGTEXT NOT NULL,
+  author_id INT UNSIGNED NOT NULL DEFAULT 0,
+  updated_at DATETIME NOT NULL,
+  created_at DATETIME NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_article_title (title),
+  KEY idx_article_created_at (created_at),
+  KEY idx_article_author_id (author_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,20 @@
+CREATE TABLE category (
+  id INT NOT NULL AUTO_INCREMENT,
+  name VARCHAR(45) NOT NULL,
+  tag VARCHAR(45) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_category_tag (tag)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE article_category (
+  id INT NOT NULL AUTO_INCREMENT,
+  article_id INT NOT NULL,
+  category_id INT NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_article_category (article_id, category_id),
+  KEY idx_article_category_category_id (category_id),
+  CONSTRAINT fk_article_category_article FOREIGN KEY (article_id) REFERENCES article (id) ON DELETE CASCADE,
+  CONSTRAINT fk_article_category_category FOREIGN KEY (category_id) REFERENCES category (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
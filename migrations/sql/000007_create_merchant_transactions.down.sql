@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS merchant_transactions;
+
+ALTER TABLE merchants MODIFY balance DECIMAL(20,2) NOT NULL DEFAULT 0;
+UPDATE merchants SET balance = balance / 100;
+ALTER TABLE merchants MODIFY balance DECIMAL(18,2) NOT NULL DEFAULT 0;
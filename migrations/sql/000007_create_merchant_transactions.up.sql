@@ -0,0 +1,28 @@
+-- balances are kept in minor units of IDR (1/100 rupiah) as the sum of the merchant's ledger entries
+ALTER TABLE merchants MODIFY balance DECIMAL(20,2) NOT NULL DEFAULT 0;
+UPDATE merchants SET balance = balance * 100;
+ALTER TABLE merchants MODIFY balance BIGINT NOT NULL DEFAULT 0;
+
+-- amount is always positive, the type tells whether it is added to (credit) or taken from (debit, refund, payout) the balance
+CREATE TABLE merchant_transactions (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  merchant_id VARCHAR(64) NOT NULL,
+  type VARCHAR(16) NOT NULL,
+  amount BIGINT NOT NULL,
+  balance_after BIGINT NOT NULL,
+  currency CHAR(3) NOT NULL DEFAULT 'IDR',
+  idempotency_key VARCHAR(255) NOT NULL,
+  reference VARCHAR(255) NOT NULL DEFAULT '',
+  description VARCHAR(1000) NOT NULL DEFAULT '',
+  created_by VARCHAR(255) NOT NULL,
+  created_date DATETIME NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_merchant_transactions_idempotency_key (merchant_id, idempotency_key),
+  KEY idx_merchant_transactions_merchant (merchant_id, id),
+  CONSTRAINT fk_merchant_transactions_merchant FOREIGN KEY (merchant_id) REFERENCES merchants (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- open the ledger of the existing merchants so their balance matches the sum of their entries
+INSERT INTO merchant_transactions (merchant_id, type, amount, balance_after, currency, idempotency_key, description, created_by, created_date)
+SELECT id, 'credit', balance, balance, 'IDR', CONCAT('opening-balance-', id), 'opening balance', 'migration', NOW()
+FROM merchants WHERE balance > 0;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS payout_status_history;
+DROP TABLE IF EXISTS payouts;
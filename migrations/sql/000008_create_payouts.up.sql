@@ -0,0 +1,33 @@
+-- a payout moves from requested to approved and paid, or to rejected; approving it records a payout entry in the ledger
+CREATE TABLE payouts (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  merchant_id VARCHAR(64) NOT NULL,
+  amount BIGINT NOT NULL,
+  currency CHAR(3) NOT NULL DEFAULT 'IDR',
+  bank_name VARCHAR(255) NOT NULL,
+  bank_account_number VARCHAR(64) NOT NULL,
+  bank_account_name VARCHAR(255) NOT NULL,
+  status VARCHAR(16) NOT NULL,
+  transaction_id BIGINT NULL,
+  created_by VARCHAR(255) NOT NULL,
+  created_date DATETIME NOT NULL,
+  modified_by VARCHAR(255) NULL,
+  modified_date DATETIME NULL,
+  PRIMARY KEY (id),
+  KEY idx_payouts_merchant_status (merchant_id, status),
+  KEY idx_payouts_status (status),
+  CONSTRAINT fk_payouts_merchant FOREIGN KEY (merchant_id) REFERENCES merchants (id),
+  CONSTRAINT fk_payouts_transaction FOREIGN KEY (transaction_id) REFERENCES merchant_transactions (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE payout_status_history (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  payout_id BIGINT NOT NULL,
+  status VARCHAR(16) NOT NULL,
+  actor VARCHAR(255) NOT NULL,
+  note VARCHAR(1000) NOT NULL DEFAULT '',
+  created_date DATETIME NOT NULL,
+  PRIMARY KEY (id),
+  KEY idx_payout_status_history_payout (payout_id, id),
+  CONSTRAINT fk_payout_status_history_payout FOREIGN KEY (payout_id) REFERENCES payouts (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
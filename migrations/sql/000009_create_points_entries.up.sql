@@ -0,0 +1,24 @@
+-- points is signed, remaining is the part of a positive entry that was neither redeemed nor expired yet
+CREATE TABLE points_entries (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  user_id VARCHAR(64) NOT NULL,
+  type VARCHAR(16) NOT NULL,
+  points BIGINT NOT NULL,
+  remaining BIGINT NOT NULL DEFAULT 0,
+  balance_after BIGINT NOT NULL,
+  reason VARCHAR(255) NOT NULL,
+  reference VARCHAR(255) NOT NULL DEFAULT '',
+  expires_at DATETIME NULL,
+  created_by VARCHAR(255) NOT NULL,
+  created_date DATETIME NOT NULL,
+  PRIMARY KEY (id),
+  KEY idx_points_entries_user (user_id, id),
+  KEY idx_points_entries_reference (user_id, type, reference),
+  KEY idx_points_entries_expires_at (expires_at),
+  CONSTRAINT fk_points_entries_user FOREIGN KEY (user_id) REFERENCES users (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- open the ledger of the existing users, the points they already have never expire
+INSERT INTO points_entries (user_id, type, points, remaining, balance_after, reason, reference, created_by, created_date)
+SELECT id, 'adjust', points, points, points, 'opening balance', 'opening-balance', 'migration', NOW()
+FROM users WHERE points > 0;
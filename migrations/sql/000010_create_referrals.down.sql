@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS referrals;
+
+ALTER TABLE users DROP INDEX uq_users_referral_code;
+UPDATE users SET referral_code = '0';
+ALTER TABLE users MODIFY referral_code INT NOT NULL DEFAULT 0;
@@ -0,0 +1,22 @@
+-- the referral codes are generated by the server from now on, the codes typed in by the clients are dropped
+ALTER TABLE users MODIFY referral_code VARCHAR(16) NULL;
+UPDATE users SET referral_code = NULL;
+ALTER TABLE users ADD UNIQUE KEY uq_users_referral_code (referral_code);
+
+-- a user is referred at most once, both sides are rewarded once the referee is verified
+CREATE TABLE referrals (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  referrer_id VARCHAR(64) NOT NULL,
+  referee_id VARCHAR(64) NOT NULL,
+  code VARCHAR(16) NOT NULL,
+  status VARCHAR(16) NOT NULL,
+  referrer_points BIGINT NOT NULL DEFAULT 0,
+  referee_points BIGINT NOT NULL DEFAULT 0,
+  created_date DATETIME NOT NULL,
+  rewarded_date DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_referrals_referee (referee_id),
+  KEY idx_referrals_referrer (referrer_id, status),
+  CONSTRAINT fk_referrals_referrer FOREIGN KEY (referrer_id) REFERENCES users (id),
+  CONSTRAINT fk_referrals_referee FOREIGN KEY (referee_id) REFERENCES users (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,7 @@
+ALTER TABLE users
+  DROP COLUMN phone_verified,
+  DROP COLUMN email_verified,
+  DROP COLUMN verification_attempts,
+  DROP COLUMN verification_channel;
+UPDATE users SET verification_send_date = created_date WHERE verification_send_date IS NULL;
+ALTER TABLE users MODIFY verification_send_date DATETIME NOT NULL;
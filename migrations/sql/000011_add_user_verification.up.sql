@@ -0,0 +1,8 @@
+-- the verification codes are generated by the server, a code is valid for a limited time and a limited number of attempts
+ALTER TABLE users MODIFY verification_send_date DATETIME NULL;
+UPDATE users SET verification_send_date = NULL, verification_code = 0;
+ALTER TABLE users
+  ADD verification_channel VARCHAR(16) NOT NULL DEFAULT '' AFTER verification_code,
+  ADD verification_attempts INT NOT NULL DEFAULT 0 AFTER verification_channel,
+  ADD email_verified TINYINT(1) NOT NULL DEFAULT 0 AFTER verification_attempts,
+  ADD phone_verified TINYINT(1) NOT NULL DEFAULT 0 AFTER email_verified;
@@ -0,0 +1,13 @@
+-- the reset tokens are stored hashed, a token is used once and only the latest token of an account is valid
+CREATE TABLE password_resets (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  account_id VARCHAR(64) NOT NULL,
+  account_type VARCHAR(16) NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  created_date DATETIME NOT NULL,
+  expires_date DATETIME NOT NULL,
+  used_date DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_password_resets_token (token_hash),
+  KEY idx_password_resets_account (account_type, account_id, used_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
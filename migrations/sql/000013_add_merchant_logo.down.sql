@@ -0,0 +1 @@
+ALTER TABLE merchants DROP COLUMN logo_url;
@@ -0,0 +1,3 @@
+-- the logo is uploaded through PUT /merchants/:id/logo, the column keeps the url of its largest thumbnail
+ALTER TABLE merchants ADD logo_url VARCHAR(2048) NOT NULL DEFAULT '' AFTER merchant_email;
+UPDATE users SET profile_pict_url = '' WHERE profile_pict_url = '#';
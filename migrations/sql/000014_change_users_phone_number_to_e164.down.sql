@@ -0,0 +1,4 @@
+UPDATE users SET phone_number = '0' WHERE phone_number NOT REGEXP '^\\+[0-9]+$';
+UPDATE users SET phone_number = SUBSTRING(phone_number, 2) WHERE phone_number LIKE '+%';
+UPDATE users SET phone_number = '0' WHERE phone_number = '';
+ALTER TABLE users MODIFY phone_number BIGINT NOT NULL DEFAULT 0;
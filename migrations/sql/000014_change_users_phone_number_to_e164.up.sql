@@ -0,0 +1,8 @@
+-- the phone numbers are stored in the E.164 format, an integer dropped the leading zeros and the + of the country code.
+-- The numbers stored so far are Indonesian: 62 prefixed numbers get the +, the others lost their leading 0.
+ALTER TABLE users MODIFY phone_number VARCHAR(16) NOT NULL DEFAULT '';
+UPDATE users SET phone_number = CASE
+    WHEN phone_number = '0' THEN ''
+    WHEN phone_number LIKE '62%' THEN CONCAT('+', phone_number)
+    ELSE CONCAT('+62', phone_number)
+  END;
@@ -0,0 +1,2 @@
+-- the version is the ETag of the article, an update only applies to the version it was read at
+ALTER TABLE article ADD version INT UNSIGNED NOT NULL DEFAULT 1 AFTER author_id;
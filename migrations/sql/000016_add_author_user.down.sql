@@ -0,0 +1 @@
+ALTER TABLE author DROP FOREIGN KEY fk_author_user, DROP INDEX uq_author_user_id, DROP COLUMN user_id;
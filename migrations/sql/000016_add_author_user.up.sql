@@ -0,0 +1,4 @@
+-- an author may be linked to a users account, that user then publishes articles as the author
+ALTER TABLE author ADD user_id VARCHAR(64) NULL AFTER name,
+  ADD UNIQUE KEY uq_author_user_id (user_id),
+  ADD CONSTRAINT fk_author_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL;
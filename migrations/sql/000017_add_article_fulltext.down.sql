@@ -0,0 +1 @@
+ALTER TABLE article DROP INDEX ft_article_title_content;
@@ -0,0 +1,2 @@
+-- the article search ranks the articles by MATCH(title, content), which needs a FULLTEXT index of both columns
+ALTER TABLE article ADD FULLTEXT INDEX ft_article_title_content (title, content);
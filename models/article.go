@@ -6,10 +6,37 @@ import (
 
 // Article represent the article model
 type Article struct {
-	ID        int64     `json:"id"`
-	Title     string    `json:"title" validate:"required"`
-	Content   string    `json:"content" validate:"required"`
-	Author    Author    `json:"author"`
+	ID      int64  `json:"id"`
+	Title   string `json:"title" validate:"required"`
+	Content string `json:"content" validate:"required"`
+	// Author is given by its id, its other fields are not validated
+	Author    Author    `json:"author" validate:"-"`
 	UpdatedAt time.Time `json:"updated_at"`
 	CreatedAt time.Time `json:"created_at"`
+	// Version is sent as the ETag of the article and checked by the updates
+	Version int64 `json:"-"`
+	// Categories are given by their tag when the article is stored or updated,
+	// an update without categories leaves them as is
+	Categories []Category `json:"categories"`
+}
+
+// ArticleFilter represent the optional criteria of the article listing
+type ArticleFilter struct {
+	Category string
+	AuthorID int64
+}
+
+// ArticleQuery represent a full-text search of the articles, the filters are left out when zero
+type ArticleQuery struct {
+	Text       string
+	AuthorID   int64
+	CategoryID int64
+}
+
+// ArticleHit represent an article matching a search, Snippet is the HTML escaped part of the content
+// around the words searched for, each of them wrapped in <mark>
+type ArticleHit struct {
+	Article
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
 }
@@ -1,9 +1,25 @@
 package models
 
+import (
+	"time"
+)
+
 // Author represent the author model
 type Author struct {
-	ID        int64  `json:"id"`
-	Name      string `json:"name"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID   int64  `json:"id"`
+	Name string `json:"name" validate:"required,max=200"`
+	// UserId is the users account linked to the author, that user publishes its articles as the author.
+	// It is an account id of the identity server, only the admins see it through AuthorAdminDto
+	UserId    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AuthorAdminDto represent an author as the admins send and receive it, with the users account linked to it
+type AuthorAdminDto struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name" validate:"required,max=200"`
+	UserId    string    `json:"user_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
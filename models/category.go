@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// Category represent the category model, the articles are filtered by its tag
+type Category struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name" validate:"required,max=45"`
+	Tag       string    `json:"tag" validate:"required,max=45,slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -7,10 +7,54 @@ var (
 	ErrInternalServerError = errors.New("Internal Server Error")
 	// ErrNotFound will throw if the requested item is not exists
 	ErrNotFound = errors.New("Your requested Item is not found")
-	// ErrConflict will throw if the current action already exists
+	// ErrUnAuthorize will throw if the caller is not authenticated
 	ErrUnAuthorize = errors.New("Unauthorize")
+	// ErrForbidden will throw if the caller is not allowed to perform the action
+	ErrForbidden = errors.New("You are not allowed to perform this action")
 	// ErrConflict will throw if the current action already exists
 	ErrConflict = errors.New("Your Item already exist")
+	// ErrPreconditionFailed will throw if the item was changed since the version the caller read
+	ErrPreconditionFailed = errors.New("Your Item was changed since it was read")
 	// ErrBadParamInput will throw if the given request-body or params is not valid
 	ErrBadParamInput = errors.New("Given Param is not valid")
+	// ErrInsufficientBalance will throw if an entry would take the merchant balance below zero
+	ErrInsufficientBalance = errors.New("Insufficient balance")
 )
+
+// domainError is an error of a domain package with its own message, it matches the error of this package it
+// refines with errors.Is so the handlers map it to the same status
+type domainError struct {
+	message string
+	kind    error
+}
+
+func (e *domainError) Error() string {
+	return e.message
+}
+
+func (e *domainError) Unwrap() error {
+	return e.kind
+}
+
+// NewBadParam will create an error with the given message that matches ErrBadParamInput with errors.Is
+func NewBadParam(message string) error {
+	return &domainError{message: message, kind: ErrBadParamInput}
+}
+
+// NewConflict will create an error with the given message that matches ErrConflict with errors.Is
+func NewConflict(message string) error {
+	return &domainError{message: message, kind: ErrConflict}
+}
+
+// NewForbidden will create an error with the given message that matches ErrForbidden with errors.Is
+func NewForbidden(message string) error {
+	return &domainError{message: message, kind: ErrForbidden}
+}
+
+// BadParam is embedded by the error types carrying more than a message, it makes them match ErrBadParamInput
+// with errors.Is
+type BadParam struct{}
+
+func (BadParam) Unwrap() error {
+	return ErrBadParamInput
+}
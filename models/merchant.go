@@ -1,43 +1,55 @@
 package models
 
-import(
+import (
 	"time"
 )
 
 type Merchant struct {
-	Id                   string    `json:"id" validate:"required"`
-	CreatedBy            string    `json:"created_by":"required"`
-	CreatedDate          time.Time `json:"created_date" validate:"required"`
-	ModifiedBy           *string    `json:"modified_by"`
-	ModifiedDate         *time.Time `json:"modified_date"`
-	DeletedBy            *string    `json:"deleted_by"`
-	DeletedDate          *time.Time `json:"deleted_date"`
-	IsDeleted            int       `json:"is_deleted" validate:"required"`
-	IsActive             int       `json:"is_active" validate:"required"`
-	MerchantName		 string		`json:"merchant_name" validate:"required"`
-	MerchantDesc		 string		`json:"merchant_desc"`
-	MerchantEmail		 string		`json:"merchant_email" validate:"required"`
-	Balance				 float64	`json:"balance"`
+	Id            string     `json:"id" validate:"required"`
+	CreatedBy     string     `json:"created_by" validate:"required"`
+	CreatedDate   time.Time  `json:"created_date" validate:"required"`
+	ModifiedBy    *string    `json:"modified_by"`
+	ModifiedDate  *time.Time `json:"modified_date"`
+	DeletedBy     *string    `json:"deleted_by"`
+	DeletedDate   *time.Time `json:"deleted_date"`
+	IsDeleted     int        `json:"is_deleted" validate:"required"`
+	IsActive      int        `json:"is_active" validate:"required"`
+	MerchantName  string     `json:"merchant_name" validate:"required"`
+	MerchantDesc  string     `json:"merchant_desc"`
+	MerchantEmail string     `json:"merchant_email" validate:"required"`
+	LogoUrl       string     `json:"logo_url"`
+	Balance       int64      `json:"balance"`
 }
 
+// NewCommandMerchant represent the profile sent to register or update a merchant
 type NewCommandMerchant struct {
-	Id                   string    `json:"id"`
-	MerchantName		 string		`json:"merchant_name" validate:"required"`
-	MerchantDesc		 string		`json:"merchant_desc"`
-	MerchantEmail		 string		`json:"merchant_email" validate:"required"`
-	MerchantPassword	 string		`json:"merchant_password"`
-	Balance				 float64	`json:"balance"`
+	Id               string `json:"id"`
+	MerchantName     string `json:"merchant_name" validate:"required"`
+	MerchantDesc     string `json:"merchant_desc"`
+	MerchantEmail    string `json:"merchant_email" validate:"required,email"`
+	MerchantPassword string `json:"password,omitempty"`
 }
 
 type MerchantInfoDto struct {
-	Id                   string    `json:"id"`
-	MerchantName		 string		`json:"merchant_name" validate:"required"`
-	MerchantDesc		 string		`json:"merchant_desc"`
-	MerchantEmail		 string		`json:"merchant_email" validate:"required"`
-	Balance				 float64	`json:"balance"`
+	Id            string `json:"id"`
+	MerchantName  string `json:"merchant_name" validate:"required"`
+	MerchantDesc  string `json:"merchant_desc"`
+	MerchantEmail string `json:"merchant_email" validate:"required"`
+	LogoUrl       string `json:"logo_url"`
+	Balance       int64  `json:"balance"`
+	IsActive      int    `json:"is_active"`
+	IsDeleted     int    `json:"is_deleted"`
 }
+
+// MerchantFilter represent the optional criteria of the merchant listing
+type MerchantFilter struct {
+	IsActive       *int
+	Search         string
+	IncludeDeleted bool
+}
+
 type LoginMerchant struct {
-	MerchantEmail		string		`json:"merchant_email"`
-	Password			string		`json:"password"`
-	Type 				string		`json:"type"`
+	MerchantEmail string `json:"merchant_email"`
+	Password      string `json:"password"`
+	Type          string `json:"type"`
 }
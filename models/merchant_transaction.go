@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+)
+
+// CurrencyIDR is the currency of the merchant balances, amounts are expressed in its minor unit (1/100 rupiah)
+const CurrencyIDR = "IDR"
+
+const (
+	// TransactionCredit adds the amount to the merchant balance, e.g. the proceeds of a sale
+	TransactionCredit = "credit"
+	// TransactionDebit takes the amount from the merchant balance, e.g. a fee
+	TransactionDebit = "debit"
+	// TransactionRefund takes the amount refunded to a customer from the merchant balance
+	TransactionRefund = "refund"
+	// TransactionPayout takes the amount paid out to the merchant bank account from the merchant balance
+	TransactionPayout = "payout"
+)
+
+// MerchantTransaction represent an entry of the merchant ledger, the entries are never updated
+type MerchantTransaction struct {
+	Id             int64     `json:"id"`
+	MerchantId     string    `json:"merchant_id"`
+	Type           string    `json:"type"`
+	Amount         int64     `json:"amount"`
+	BalanceAfter   int64     `json:"balance_after"`
+	Currency       string    `json:"currency"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	Reference      string    `json:"reference"`
+	Description    string    `json:"description"`
+	CreatedBy      string    `json:"created_by"`
+	CreatedDate    time.Time `json:"created_date"`
+}
+
+// Delta will return the signed change the entry makes to the merchant balance
+func (t *MerchantTransaction) Delta() int64 {
+	if t.Type == TransactionCredit {
+		return t.Amount
+	}
+	return -t.Amount
+}
+
+type NewCommandMerchantTransaction struct {
+	MerchantId     string `json:"merchant_id"`
+	Type           string `json:"type" validate:"required,oneof=credit debit refund payout"`
+	Amount         int64  `json:"amount" validate:"required,gt=0"`
+	IdempotencyKey string `json:"idempotency_key" validate:"required,max=255"`
+	Reference      string `json:"reference" validate:"max=255"`
+	Description    string `json:"description" validate:"max=1000"`
+}
+
+// MerchantBalance represent the stored balance of a merchant next to the sum of its ledger entries
+type MerchantBalance struct {
+	MerchantId    string `json:"merchant_id"`
+	Balance       int64  `json:"balance"`
+	LedgerBalance int64  `json:"ledger_balance"`
+	Currency      string `json:"currency"`
+	Reconciled    bool   `json:"reconciled"`
+}
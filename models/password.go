@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// PasswordReset represent a reset token issued to a user or a merchant, only its hash is stored
+type PasswordReset struct {
+	Id          int64      `json:"id"`
+	AccountId   string     `json:"account_id"`
+	AccountType string     `json:"account_type"`
+	TokenHash   string     `json:"-"`
+	CreatedDate time.Time  `json:"created_date"`
+	ExpiresDate time.Time  `json:"expires_date"`
+	UsedDate    *time.Time `json:"used_date"`
+}
+
+type ForgotPassword struct {
+	Email string `json:"email" validate:"required,email"`
+	Type  string `json:"type" validate:"required,oneof=user merchant"`
+}
+
+type ResetPassword struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,nefield=CurrentPassword"`
+}
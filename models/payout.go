@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	// PayoutRequested is the status of a payout waiting for an admin decision
+	PayoutRequested = "requested"
+	// PayoutApproved is the status of a payout whose amount was debited from the merchant balance
+	PayoutApproved = "approved"
+	// PayoutPaid is the status of an approved payout once the bank transfer is done
+	PayoutPaid = "paid"
+	// PayoutRejected is the status of a payout refused by an admin, the balance is left untouched
+	PayoutRejected = "rejected"
+)
+
+// Payout represent a request of a merchant to withdraw its balance to a bank account
+type Payout struct {
+	Id                int64          `json:"id"`
+	MerchantId        string         `json:"merchant_id"`
+	Amount            int64          `json:"amount"`
+	Currency          string         `json:"currency"`
+	BankName          string         `json:"bank_name"`
+	BankAccountNumber string         `json:"bank_account_number"`
+	BankAccountName   string         `json:"bank_account_name"`
+	Status            string         `json:"status"`
+	TransactionId     *int64         `json:"transaction_id"`
+	CreatedBy         string         `json:"created_by"`
+	CreatedDate       time.Time      `json:"created_date"`
+	ModifiedBy        *string        `json:"modified_by"`
+	ModifiedDate      *time.Time     `json:"modified_date"`
+	History           []PayoutStatus `json:"history,omitempty"`
+}
+
+// PayoutStatus represent a status change of a payout and who made it
+type PayoutStatus struct {
+	Id          int64     `json:"id"`
+	PayoutId    int64     `json:"payout_id"`
+	Status      string    `json:"status"`
+	Actor       string    `json:"actor"`
+	Note        string    `json:"note"`
+	CreatedDate time.Time `json:"created_date"`
+}
+
+type NewCommandPayout struct {
+	MerchantId        string `json:"merchant_id"`
+	Amount            int64  `json:"amount" validate:"required,gt=0"`
+	BankName          string `json:"bank_name" validate:"required,max=255"`
+	BankAccountNumber string `json:"bank_account_number" validate:"required,numeric,max=64"`
+	BankAccountName   string `json:"bank_account_name" validate:"required,max=255"`
+	Note              string `json:"note" validate:"max=1000"`
+}
+
+// PayoutFilter represent the optional criteria of the payout listing
+type PayoutFilter struct {
+	MerchantId string
+	Status     string
+}
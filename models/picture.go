@@ -0,0 +1,13 @@
+package models
+
+// Picture represent the thumbnails stored for an uploaded profile picture or logo, Url is the one of the largest
+type Picture struct {
+	Url        string      `json:"url"`
+	Thumbnails []Thumbnail `json:"thumbnails"`
+}
+
+// Thumbnail represent a square thumbnail of Size pixels
+type Thumbnail struct {
+	Size int    `json:"size"`
+	Url  string `json:"url"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	// PointsEarn adds the points awarded by an earning rule
+	PointsEarn = "earn"
+	// PointsRedeem takes the points spent by the user
+	PointsRedeem = "redeem"
+	// PointsExpire takes the points that were not spent before their expiry date
+	PointsExpire = "expire"
+	// PointsAdjust adds or takes points by hand, e.g. a correction made by an admin
+	PointsAdjust = "adjust"
+)
+
+// EventRegistration is the earning rule event awarding the points of a new user
+const EventRegistration = "registration"
+
+// PointsEntry represent an entry of the points ledger of a user, the entries are never updated
+// except Remaining, the part of a positive entry that was neither redeemed nor expired yet.
+// Positive entries are consumed oldest expiry first.
+type PointsEntry struct {
+	Id           int64      `json:"id"`
+	UserId       string     `json:"user_id"`
+	Type         string     `json:"type"`
+	Points       int64      `json:"points"`
+	Remaining    int64      `json:"remaining"`
+	BalanceAfter int64      `json:"balance_after"`
+	Reason       string     `json:"reason"`
+	Reference    string     `json:"reference"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	CreatedBy    string     `json:"created_by"`
+	CreatedDate  time.Time  `json:"created_date"`
+}
+
+type NewCommandPoints struct {
+	UserId    string `json:"user_id"`
+	Points    int64  `json:"points" validate:"required"`
+	Reason    string `json:"reason" validate:"required,max=255"`
+	Reference string `json:"reference" validate:"max=255"`
+}
+
+// PointsEvent represent something the user did that may be worth points according to the earning rules
+type PointsEvent struct {
+	UserId    string `json:"user_id"`
+	Event     string `json:"event" validate:"required,max=64"`
+	Amount    int64  `json:"amount" validate:"gte=0"`
+	Reference string `json:"reference" validate:"max=255"`
+}
+
+// EarningRule represent how many points an event is worth.
+// Points are awarded once per event, or for every PerAmount of the event amount when PerAmount is set.
+type EarningRule struct {
+	Event     string `json:"event"`
+	Points    int64  `json:"points"`
+	PerAmount int64  `json:"per_amount"`
+}
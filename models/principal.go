@@ -0,0 +1,40 @@
+package models
+
+const (
+	// PrincipalUser is the type of a principal authenticated as a user account
+	PrincipalUser = "user"
+	// PrincipalMerchant is the type of a principal authenticated as a merchant account
+	PrincipalMerchant = "merchant"
+)
+
+const (
+	// RoleUser is granted to every user account
+	RoleUser = "user"
+	// RoleMerchant is granted to every merchant account
+	RoleMerchant = "merchant"
+	// RoleAdmin is granted to the accounts listed in the auth.admins config
+	RoleAdmin = "admin"
+)
+
+// Principal represent the authenticated caller of a request
+type Principal struct {
+	Id       string   `json:"id"`
+	Username string   `json:"username"`
+	Email    string   `json:"email"`
+	Type     string   `json:"type"`
+	Roles    []string `json:"roles"`
+	Token    string   `json:"-"`
+}
+
+// HasRole will tell whether the principal was granted the given role
+func (p *Principal) HasRole(role string) bool {
+	if p == nil {
+		return false
+	}
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	// ReferralPending is the status of a referral whose referee is not verified yet
+	ReferralPending = "pending"
+	// ReferralRewarded is the status of a referral whose both sides received their points
+	ReferralRewarded = "rewarded"
+)
+
+const (
+	// EventReferrer is the earning rule event awarding the points of the user who referred a verified user
+	EventReferrer = "referral_referrer"
+	// EventReferee is the earning rule event awarding the points of a verified user who was referred
+	EventReferee = "referral_referee"
+)
+
+// Referral represent a user who registered with the referral code of another user
+type Referral struct {
+	Id             int64      `json:"id"`
+	ReferrerId     string     `json:"referrer_id"`
+	RefereeId      string     `json:"referee_id"`
+	Code           string     `json:"code"`
+	Status         string     `json:"status"`
+	ReferrerPoints int64      `json:"referrer_points"`
+	RefereePoints  int64      `json:"referee_points"`
+	CreatedDate    time.Time  `json:"created_date"`
+	RewardedDate   *time.Time `json:"rewarded_date"`
+}
+
+// ReferralStats represent the referral code of a user and how many users it referred
+type ReferralStats struct {
+	UserId       string  `json:"user_id"`
+	ReferralCode string  `json:"referral_code"`
+	ReferredBy   *string `json:"referred_by"`
+	Total        int     `json:"total"`
+	Pending      int     `json:"pending"`
+	Rewarded     int     `json:"rewarded"`
+	PointsEarned int64   `json:"points_earned"`
+}
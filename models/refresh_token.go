@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// RefreshToken represent a refresh token issued to an account at login.
+// Only the sha256 of the token is stored, so a database leak does not leak usable tokens.
+type RefreshToken struct {
+	TokenHash   string     `json:"token_hash"`
+	AccountId   string     `json:"account_id"`
+	AccountType string     `json:"account_type"`
+	CreatedDate time.Time  `json:"created_date"`
+	RevokedDate *time.Time `json:"revoked_date"`
+	IsRevoked   int        `json:"is_revoked"`
+}
@@ -5,50 +5,58 @@ import (
 )
 
 type User struct {
-	Id                   string    `json:"id" validate:"required"`
-	CreatedBy            string    `json:"created_by" validate:"required"`
-	CreatedDate          time.Time `json:"created_date" validate:"required"`
+	Id                   string     `json:"id" validate:"required"`
+	CreatedBy            string     `json:"created_by" validate:"required"`
+	CreatedDate          time.Time  `json:"created_date" validate:"required"`
 	ModifiedBy           *string    `json:"modified_by"`
 	ModifiedDate         *time.Time `json:"modified_date"`
 	DeletedBy            *string    `json:"deleted_by"`
 	DeletedDate          *time.Time `json:"deleted_date"`
-	IsDeleted            int       `json:"is_deleted" validate:"required"`
-	IsActive             int       `json:"is_active" validate:"required"`
-	UserEmail            string    `json:"user_email" validate:"required"`
-	FullName             string    `json:"full_name"`
-	PhoneNumber          int       `json:"phone_number" validate:"required"`
-	VerificationSendDate time.Time `json:"verification_send_date"`
-	VerificationCode     int       `json:"verification_code"`
-	ProfilePictUrl       string    `json:"profile_pict_url"`
-	Address              string    `json:"address" validate:"required"`
-	Dob                  time.Time `json:"dob" validate:"required"`
-	Gender               int       `json:"gender" validate:"required"`
-	IdType               int       `json:"id_type"`
-	IdNumber             string    `json:"id_number"`
-	ReferralCode         int       `json:"referral_code"`
-	Points               int       `json:"points"`
+	IsDeleted            int        `json:"is_deleted" validate:"required"`
+	IsActive             int        `json:"is_active" validate:"required"`
+	UserEmail            string     `json:"user_email" validate:"required"`
+	FullName             string     `json:"full_name"`
+	PhoneNumber          string     `json:"phone_number" validate:"required"`
+	VerificationSendDate *time.Time `json:"verification_send_date"`
+	VerificationCode     int        `json:"-"`
+	VerificationChannel  string     `json:"verification_channel"`
+	VerificationAttempts int        `json:"verification_attempts"`
+	EmailVerified        int        `json:"email_verified"`
+	PhoneVerified        int        `json:"phone_verified"`
+	ProfilePictUrl       string     `json:"profile_pict_url"`
+	Address              string     `json:"address" validate:"required"`
+	Dob                  time.Time  `json:"dob" validate:"required"`
+	Gender               int        `json:"gender" validate:"required"`
+	IdType               int        `json:"id_type"`
+	IdNumber             string     `json:"id_number"`
+	ReferralCode         *string    `json:"referral_code"`
+	Points               int        `json:"points"`
 }
+
+// NewCommandUser represent the profile sent to register or update a user, the phone number is in the E.164 format
 type NewCommandUser struct {
-	Id                   string    `json:"id"`
-	UserEmail            string    `json:"user_email" validate:"required"`
-	Password			 string 	`json:"password"`
-	FullName             string    `json:"full_name"`
-	PhoneNumber          int       `json:"phone_number" validate:"required"`
-	VerificationSendDate string `json:"verification_send_date"`
-	VerificationCode     int       `json:"verification_code"`
-	ProfilePictUrl       string    `json:"profile_pict_url"`
-	Address              string    `json:"address" validate:"required"`
-	Dob                  string `json:"dob" validate:"required"`
-	Gender               int       `json:"gender" validate:"required"`
-	IdType               int       `json:"id_type"`
-	IdNumber             string    `json:"id_number"`
-	ReferralCode         int       `json:"referral_code"`
-	Points               int       `json:"points"`
+	Id           string `json:"id"`
+	UserEmail    string `json:"user_email" validate:"required,email"`
+	Password     string `json:"password,omitempty"`
+	FullName     string `json:"full_name"`
+	PhoneNumber  string `json:"phone_number" validate:"required,e164"`
+	Address      string `json:"address" validate:"required"`
+	Dob          string `json:"dob" validate:"required,datetime=2006-01-02 15:04:05"`
+	Gender       int    `json:"gender" validate:"required"`
+	IdType       int    `json:"id_type"`
+	IdNumber     string `json:"id_number"`
+	ReferrerCode string `json:"referrer_code" validate:"max=16"`
 }
 type UserInfoDto struct {
-	Id                   string    `json:"id"`
-	UserEmail            string    `json:"user_email" validate:"required"`
-	FullName             string    `json:"full_name"`
-	PhoneNumber          int       `json:"phone_number" validate:"required"`
-	ProfilePictUrl       string    `json:"profile_pict_url"`
-}
\ No newline at end of file
+	Id             string `json:"id"`
+	UserEmail      string `json:"user_email" validate:"required"`
+	FullName       string `json:"full_name"`
+	PhoneNumber    string `json:"phone_number" validate:"required"`
+	ProfilePictUrl string `json:"profile_pict_url"`
+	ReferralCode   string `json:"referral_code"`
+	Points         int    `json:"points"`
+	EmailVerified  int    `json:"email_verified"`
+	PhoneVerified  int    `json:"phone_verified"`
+	IsActive       int    `json:"is_active"`
+	IsDeleted      int    `json:"is_deleted"`
+}
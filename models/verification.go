@@ -0,0 +1,17 @@
+package models
+
+const (
+	// VerificationEmail sends the verification code to the email address of the user
+	VerificationEmail = "email"
+	// VerificationSMS sends the verification code to the phone number of the user
+	VerificationSMS = "sms"
+)
+
+type NewCommandVerification struct {
+	Channel string `json:"channel" validate:"required,oneof=email sms"`
+}
+
+type VerifyUser struct {
+	Channel string `json:"channel" validate:"required,oneof=email sms"`
+	Code    string `json:"code" validate:"required,len=6,numeric"`
+}
@@ -0,0 +1,9 @@
+// Package mysqlerr holds the numbers of the MySQL errors the repositories turn into the errors of their domain
+package mysqlerr
+
+const (
+	// DuplicateEntry is the MySQL error number of a unique key violation
+	DuplicateEntry = 1062
+	// NoReferencedRow is the MySQL error number of a foreign key pointing to a missing row
+	NoReferencedRow = 1452
+)
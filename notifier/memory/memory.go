@@ -0,0 +1,47 @@
+// Package memory keeps the messages in memory instead of delivering them, for the tests and local development.
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/notifier"
+)
+
+// Notifier will record every message it is given
+type Notifier struct {
+	mu       sync.Mutex
+	messages []notifier.Message
+}
+
+// New will create an empty in-memory notifier
+func New() *Notifier {
+	return &Notifier{}
+}
+
+func (n *Notifier) Send(ctx context.Context, msg *notifier.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.messages = append(n.messages, *msg)
+	return nil
+}
+
+// Messages will return a copy of the messages sent so far, the oldest first
+func (n *Notifier) Messages() []notifier.Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]notifier.Message(nil), n.messages...)
+}
+
+// Last will return the last message sent to the recipient, or nil when it received none
+func (n *Notifier) Last(to string) *notifier.Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for i := len(n.messages) - 1; i >= 0; i-- {
+		if n.messages[i].To == to {
+			msg := n.messages[i]
+			return &msg
+		}
+	}
+	return nil
+}
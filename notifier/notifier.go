@@ -0,0 +1,18 @@
+// Package notifier sends short messages to the users, such as the verification codes.
+package notifier
+
+import (
+	"context"
+)
+
+// Message represent a message sent to a single recipient, an email address or a phone number
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier represent a channel the messages are delivered through
+type Notifier interface {
+	Send(ctx context.Context, msg *Message) error
+}
@@ -0,0 +1,70 @@
+// Package sms delivers the messages as text messages through an HTTP SMS gateway.
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/notifier"
+)
+
+// maxErrorBody limits how much of an error response is read to build the error message
+const maxErrorBody = 1024
+
+// Options represent the SMS gateway. The message is posted as JSON {"from", "to", "message"}
+// with the API key as a bearer token.
+type Options struct {
+	URL    string
+	APIKey string
+	Sender string
+}
+
+type smsNotifier struct {
+	opts   Options
+	client *http.Client
+}
+
+// New will create a notifier.Notifier sending text messages through the gateway,
+// http.DefaultClient is used when client is nil
+func New(opts Options, client *http.Client) notifier.Notifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &smsNotifier{opts: opts, client: client}
+}
+
+func (n *smsNotifier) Send(ctx context.Context, msg *notifier.Message) error {
+	data, err := json.Marshal(map[string]string{
+		"from":    n.opts.Sender,
+		"to":      msg.To,
+		"message": msg.Body,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", n.opts.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.opts.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+n.opts.APIKey)
+	}
+
+	resp, err := n.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("sms gateway responded with status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return nil
+}
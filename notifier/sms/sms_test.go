@@ -0,0 +1,44 @@
+package sms_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/notifier"
+	"github.com/notifier/sms"
+)
+
+func TestSend(t *testing.T) {
+	var got map[string]string
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	n := sms.New(sms.Options{URL: server.URL, APIKey: "key", Sender: "CGO"}, server.Client())
+	err := n.Send(context.TODO(), &notifier.Message{To: "+6281234567890", Body: "Your code is 123456"})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer key", auth)
+	assert.Equal(t, map[string]string{"from": "CGO", "to": "+6281234567890", "message": "Your code is 123456"}, got)
+}
+
+func TestSendFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid number", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	n := sms.New(sms.Options{URL: server.URL}, server.Client())
+	err := n.Send(context.TODO(), &notifier.Message{To: "+62", Body: "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid number")
+}
@@ -0,0 +1,88 @@
+package smtp_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/notifier"
+	"github.com/notifier/smtp"
+)
+
+// fakeServer will accept one SMTP session and return the envelope and the data it received
+func fakeServer(t *testing.T) (net.Listener, chan []string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		var lines []string
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost ESMTP")
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				received <- lines
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+			switch {
+			case inData && line == ".":
+				inData = false
+				reply("250 queued")
+			case inData:
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250-localhost")
+				reply("250 8BITMIME")
+			case strings.HasPrefix(line, "DATA"):
+				inData = true
+				reply("354 go ahead")
+			case strings.HasPrefix(line, "QUIT"):
+				reply("221 bye")
+				received <- lines
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return l, received
+}
+
+func TestSend(t *testing.T) {
+	l, received := fakeServer(t)
+	defer l.Close()
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+
+	n := smtp.New(smtp.Options{Host: host, Port: port, From: "no-reply@cgo.local"})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := n.Send(ctx, &notifier.Message{To: "john@mail.com", Subject: "Your code", Body: "Your code is 123456"})
+	require.NoError(t, err)
+
+	lines := <-received
+	session := strings.Join(lines, "\n")
+	assert.Contains(t, session, "MAIL FROM:<no-reply@cgo.local>")
+	assert.Contains(t, session, "RCPT TO:<john@mail.com>")
+	assert.Contains(t, session, "Subject: Your code")
+	assert.Contains(t, session, "Your code is 123456")
+}
+
+func TestSendRejectsHeaderInjection(t *testing.T) {
+	n := smtp.New(smtp.Options{Host: "127.0.0.1", Port: "1", From: "no-reply@cgo.local"})
+	err := n.Send(context.TODO(), &notifier.Message{To: "john@mail.com\r\nBcc: all@mail.com", Body: "x"})
+	assert.Error(t, err)
+}
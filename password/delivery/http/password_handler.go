@@ -0,0 +1,139 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/identityserver"
+	"github.com/middleware"
+	"github.com/models"
+	"github.com/password"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// passwordHandler  represent the httphandler for the passwords of the users and the merchants
+type passwordHandler struct {
+	PasswordUsecase password.Usecase
+}
+
+// NewpasswordHandler will initialize the account/password resources endpoint
+func NewpasswordHandler(e *echo.Echo, us password.Usecase, mw *middleware.GoMiddleware) {
+	handler := &passwordHandler{
+		PasswordUsecase: us,
+	}
+	e.POST("/account/password/forgot", handler.Forgot)
+	e.POST("/account/password/reset", handler.Reset)
+	e.POST("/account/password/change", handler.Change, mw.Authenticate)
+}
+
+func isRequestValid(m interface{}) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Forgot will email a reset token to the account, it answers the same whether the email address is known or not
+func (a *passwordHandler) Forgot(c echo.Context) error {
+	var command models.ForgotPassword
+	err := c.Bind(&command)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+	}
+
+	if ok, err := isRequestValid(&command); !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = a.PasswordUsecase.Forgot(ctx, &command)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusAccepted)
+}
+
+// Reset will set a new password with a reset token
+func (a *passwordHandler) Reset(c echo.Context) error {
+	var command models.ResetPassword
+	err := c.Bind(&command)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+	}
+
+	if ok, err := isRequestValid(&command); !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = a.PasswordUsecase.Reset(ctx, &command)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Change will set a new password for the authenticated user or merchant given its current password
+func (a *passwordHandler) Change(c echo.Context) error {
+	var command models.ChangePassword
+	err := c.Bind(&command)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+	}
+
+	if ok, err := isRequestValid(&command); !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = a.PasswordUsecase.Change(ctx, middleware.GetPrincipal(c), &command)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	logrus.Error(err)
+	switch {
+	case errors.Is(err, models.ErrInternalServerError):
+		return http.StatusInternalServerError
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrUnAuthorize):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrBadParamInput):
+		return http.StatusBadRequest
+	case errors.Is(err, identityserver.ErrUnreachable):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,72 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/middleware/middlewaretest"
+	"github.com/models"
+	"github.com/password"
+	passwordHttp "github.com/password/delivery/http"
+	"github.com/password/mocks"
+)
+
+func newServer(mockUCase *mocks.Usecase) *echo.Echo {
+	e := echo.New()
+	passwordHttp.NewpasswordHandler(e, mockUCase, middlewaretest.New())
+	return e
+}
+
+func serve(e *echo.Echo, path string, body string, token string) *httptest.ResponseRecorder {
+	return middlewaretest.Serve(e, middlewaretest.NewRequest(echo.POST, path, body), token)
+}
+
+func TestForgot(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Forgot", mock.Anything, &models.ForgotPassword{Email: "john@mail.com", Type: "user"}).Return(nil).Once()
+
+	rec := serve(e, "/account/password/forgot", `{"email":"john@mail.com","type":"user"}`, "")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	rec = serve(e, "/account/password/forgot", `{"email":"john@mail.com","type":"admin"}`, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestReset(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Reset", mock.Anything, &models.ResetPassword{Token: "abc", NewPassword: "new-secret"}).
+		Return(password.ErrInvalidResetToken).Once()
+
+	rec := serve(e, "/account/password/reset", `{"token":"abc","new_password":"new-secret"}`, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), password.ErrInvalidResetToken.Error())
+
+	rec = serve(e, "/account/password/reset", `{"token":"abc","new_password":"short"}`, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestChange(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Change", mock.Anything, mock.MatchedBy(func(p *models.Principal) bool { return p.Id == "u1" }),
+		&models.ChangePassword{CurrentPassword: "old-secret", NewPassword: "new-secret"}).Return(nil).Once()
+
+	rec := serve(e, "/account/password/change", `{"current_password":"old-secret","new_password":"new-secret"}`, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = serve(e, "/account/password/change", `{"current_password":"old-secret","new_password":"new-secret"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = serve(e, "/account/password/change", `{"current_password":"same-secret","new_password":"same-secret"}`, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
@@ -0,0 +1,12 @@
+package password
+
+import (
+	"github.com/models"
+)
+
+var (
+	// ErrInvalidResetToken will throw if the reset token is unknown, expired or already used
+	ErrInvalidResetToken = models.NewBadParam("Invalid or expired password reset token")
+	// ErrWrongPassword will throw if the current password given to change the password is not the right one
+	ErrWrongPassword = models.NewBadParam("The current password is wrong")
+)
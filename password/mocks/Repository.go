@@ -0,0 +1,100 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import time "time"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// Consume provides a mock function with given fields: ctx, id, usedAt
+func (_m *Repository) Consume(ctx context.Context, id int64, usedAt time.Time) error {
+	ret := _m.Called(ctx, id, usedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) error); ok {
+		r0 = rf(ctx, id, usedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByTokenHash provides a mock function with given fields: ctx, tokenHash
+func (_m *Repository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.PasswordReset, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	var r0 *models.PasswordReset
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.PasswordReset); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PasswordReset)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestForAccount provides a mock function with given fields: ctx, accountType, accountId
+func (_m *Repository) GetLatestForAccount(ctx context.Context, accountType string, accountId string) (*models.PasswordReset, error) {
+	ret := _m.Called(ctx, accountType, accountId)
+
+	var r0 *models.PasswordReset
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.PasswordReset); ok {
+		r0 = rf(ctx, accountType, accountId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PasswordReset)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, accountType, accountId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, r
+func (_m *Repository) Insert(ctx context.Context, r *models.PasswordReset) error {
+	ret := _m.Called(ctx, r)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PasswordReset) error); ok {
+		r0 = rf(ctx, r)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeForAccount provides a mock function with given fields: ctx, accountType, accountId, usedAt
+func (_m *Repository) RevokeForAccount(ctx context.Context, accountType string, accountId string, usedAt time.Time) error {
+	ret := _m.Called(ctx, accountType, accountId, usedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, accountType, accountId, usedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,53 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Change provides a mock function with given fields: ctx, principal, ar
+func (_m *Usecase) Change(ctx context.Context, principal *models.Principal, ar *models.ChangePassword) error {
+	ret := _m.Called(ctx, principal, ar)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Principal, *models.ChangePassword) error); ok {
+		r0 = rf(ctx, principal, ar)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Forgot provides a mock function with given fields: ctx, ar
+func (_m *Usecase) Forgot(ctx context.Context, ar *models.ForgotPassword) error {
+	ret := _m.Called(ctx, ar)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ForgotPassword) error); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reset provides a mock function with given fields: ctx, ar
+func (_m *Usecase) Reset(ctx context.Context, ar *models.ResetPassword) error {
+	ret := _m.Called(ctx, ar)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ResetPassword) error); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
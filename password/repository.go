@@ -0,0 +1,19 @@
+package password
+
+import (
+	"context"
+	"time"
+
+	"github.com/models"
+)
+
+// Repository represent the password reset token's repository contract.
+// Consume only uses a token that was not used yet, so a token resets the password once.
+// GetLatestForAccount returns the token issued last to the account, it throttles the reset requests.
+type Repository interface {
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.PasswordReset, error)
+	GetLatestForAccount(ctx context.Context, accountType string, accountId string) (*models.PasswordReset, error)
+	Insert(ctx context.Context, r *models.PasswordReset) error
+	Consume(ctx context.Context, id int64, usedAt time.Time) error
+	RevokeForAccount(ctx context.Context, accountType string, accountId string, usedAt time.Time) error
+}
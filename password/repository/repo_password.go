@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/models"
+	"github.com/password"
+	"github.com/transaction"
+)
+
+type passwordRepository struct {
+	Conn *sql.DB
+}
+
+// NewpasswordRepository will create an object that represent the password.Repository interface
+func NewpasswordRepository(Conn *sql.DB) password.Repository {
+	return &passwordRepository{Conn}
+}
+
+// conn will return the transaction of the unit of work ctx belongs to, or the connection pool
+func (m *passwordRepository) conn(ctx context.Context) transaction.DBTX {
+	return transaction.Conn(ctx, m.Conn)
+}
+
+func (m *passwordRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.PasswordReset, error) {
+	query := `SELECT id, account_id, account_type, token_hash, created_date, expires_date, used_date
+  FROM password_resets WHERE token_hash = ?`
+
+	r := new(models.PasswordReset)
+	err := m.conn(ctx).QueryRowContext(ctx, query, tokenHash).Scan(
+		&r.Id,
+		&r.AccountId,
+		&r.AccountType,
+		&r.TokenHash,
+		&r.CreatedDate,
+		&r.ExpiresDate,
+		&r.UsedDate,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetLatestForAccount will return the token issued last to the account
+func (m *passwordRepository) GetLatestForAccount(ctx context.Context, accountType string, accountId string) (*models.PasswordReset, error) {
+	query := `SELECT id, account_id, account_type, token_hash, created_date, expires_date, used_date
+  FROM password_resets WHERE account_type = ? AND account_id = ? ORDER BY created_date DESC, id DESC LIMIT 1`
+
+	r := new(models.PasswordReset)
+	err := m.conn(ctx).QueryRowContext(ctx, query, accountType, accountId).Scan(
+		&r.Id,
+		&r.AccountId,
+		&r.AccountType,
+		&r.TokenHash,
+		&r.CreatedDate,
+		&r.ExpiresDate,
+		&r.UsedDate,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return r, nil
+}
+
+func (m *passwordRepository) Insert(ctx context.Context, r *models.PasswordReset) error {
+	query := `INSERT password_resets SET account_id=? , account_type=? , token_hash=? , created_date=? , expires_date=?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, r.AccountId, r.AccountType, r.TokenHash, r.CreatedDate, r.ExpiresDate)
+	if err != nil {
+		return err
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	r.Id = lastID
+	return nil
+}
+
+// Consume will mark the token used, a token that was already used fails with models.ErrConflict
+func (m *passwordRepository) Consume(ctx context.Context, id int64, usedAt time.Time) error {
+	query := `UPDATE password_resets SET used_date=? WHERE id = ? AND used_date IS NULL`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, usedAt, id)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrConflict
+	}
+	return nil
+}
+
+// RevokeForAccount will mark every token of the account that was not used yet as used
+func (m *passwordRepository) RevokeForAccount(ctx context.Context, accountType string, accountId string, usedAt time.Time) error {
+	query := `UPDATE password_resets SET used_date=? WHERE account_type = ? AND account_id = ? AND used_date IS NULL`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, usedAt, accountType, accountId)
+	return err
+}
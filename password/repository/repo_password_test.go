@@ -0,0 +1,87 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/models"
+	passwordRepo "github.com/password/repository"
+)
+
+func TestGetByTokenHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	columns := []string{"id", "account_id", "account_type", "token_hash", "created_date", "expires_date", "used_date"}
+	query := "SELECT .+ FROM password_resets WHERE token_hash = \\?"
+	mock.ExpectQuery(query).WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(3, "u1", "user", "hash", time.Now(), time.Now().Add(time.Hour), nil))
+	mock.ExpectQuery(query).WithArgs("unknown").WillReturnRows(sqlmock.NewRows(columns))
+
+	a := passwordRepo.NewpasswordRepository(db)
+	r, err := a.GetByTokenHash(context.TODO(), "hash")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", r.AccountId)
+	assert.Nil(t, r.UsedDate)
+
+	_, err = a.GetByTokenHash(context.TODO(), "unknown")
+	assert.Equal(t, models.ErrNotFound, err)
+}
+
+func TestGetLatestForAccount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	columns := []string{"id", "account_id", "account_type", "token_hash", "created_date", "expires_date", "used_date"}
+	query := "SELECT .+ FROM password_resets WHERE account_type = \\? AND account_id = \\? ORDER BY created_date DESC, id DESC LIMIT 1"
+	mock.ExpectQuery(query).WithArgs("user", "u1").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(4, "u1", "user", "hash", time.Now(), time.Now().Add(time.Hour), nil))
+	mock.ExpectQuery(query).WithArgs("user", "u2").WillReturnRows(sqlmock.NewRows(columns))
+
+	a := passwordRepo.NewpasswordRepository(db)
+	r, err := a.GetLatestForAccount(context.TODO(), "user", "u1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), r.Id)
+
+	_, err = a.GetLatestForAccount(context.TODO(), "user", "u2")
+	assert.Equal(t, models.ErrNotFound, err)
+}
+
+func TestConsume(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "UPDATE password_resets SET used_date=\\? WHERE id = \\? AND used_date IS NULL"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(sqlmock.AnyArg(), 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(sqlmock.AnyArg(), 3).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := passwordRepo.NewpasswordRepository(db)
+	assert.NoError(t, a.Consume(context.TODO(), 3, time.Now()))
+	assert.Equal(t, models.ErrConflict, a.Consume(context.TODO(), 3, time.Now()))
+}
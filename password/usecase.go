@@ -0,0 +1,14 @@
+package password
+
+import (
+	"context"
+
+	"github.com/models"
+)
+
+// Usecase represent the password reset and change usecases of the users and the merchants
+type Usecase interface {
+	Forgot(ctx context.Context, ar *models.ForgotPassword) error
+	Reset(ctx context.Context, ar *models.ResetPassword) error
+	Change(ctx context.Context, principal *models.Principal, ar *models.ChangePassword) error
+}
@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/identityserver"
+	"github.com/merchant"
+	"github.com/models"
+	"github.com/notifier"
+	"github.com/password"
+	"github.com/transaction"
+	"github.com/user"
+)
+
+// tokenBytes is the number of random bytes of a reset token
+const tokenBytes = 32
+
+// Options represent how long the reset tokens are valid, the page the reset link points to and how often
+// an account may be sent one. The token is sent alone when ResetURL is empty.
+type Options struct {
+	ResetTokenTTL  time.Duration
+	ResetURL       string
+	ForgotInterval time.Duration
+}
+
+type passwordUsecase struct {
+	passwordRepo     password.Repository
+	tokenRepo        identityserver.Repository
+	userRepo         user.Repository
+	merchantRepo     merchant.Repository
+	identityServerUc identityserver.Usecase
+	email            notifier.Notifier
+	txManager        transaction.Manager
+	opts             Options
+	contextTimeout   time.Duration
+}
+
+// NewpasswordUsecase will create new an passwordUsecase object representation of password.Usecase interface
+func NewpasswordUsecase(p password.Repository, tokenRepo identityserver.Repository, ur user.Repository, mr merchant.Repository, is identityserver.Usecase, email notifier.Notifier, txManager transaction.Manager, opts Options, timeout time.Duration) password.Usecase {
+	return &passwordUsecase{
+		passwordRepo:     p,
+		tokenRepo:        tokenRepo,
+		userRepo:         ur,
+		merchantRepo:     mr,
+		identityServerUc: is,
+		email:            email,
+		txManager:        txManager,
+		opts:             opts,
+		contextTimeout:   timeout,
+	}
+}
+
+// Forgot will email a reset token to the account of the given type owning the email address.
+// The tokens issued before are revoked. An unknown email address is not an error, so the accounts can not be discovered,
+// and neither is a request made within ForgotInterval of the last token, which is not sent.
+func (m passwordUsecase) Forgot(c context.Context, ar *models.ForgotPassword) error {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	accountId, err := m.accountIdByEmail(ctx, ar.Type, ar.Email)
+	if errors.Is(err, models.ErrNotFound) {
+		logrus.WithField("type", ar.Type).Info("password reset requested for an unknown email address")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	last, err := m.passwordRepo.GetLatestForAccount(ctx, ar.Type, accountId)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		return err
+	}
+	if last != nil && now.Sub(last.CreatedDate) < m.opts.ForgotInterval {
+		logrus.WithField("type", ar.Type).Info("password reset requested again too soon")
+		return nil
+	}
+
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	reset := &models.PasswordReset{
+		AccountId:   accountId,
+		AccountType: ar.Type,
+		TokenHash:   identityserver.HashToken(token),
+		CreatedDate: now,
+		ExpiresDate: now.Add(m.opts.ResetTokenTTL),
+	}
+	err = m.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := m.passwordRepo.RevokeForAccount(ctx, ar.Type, accountId, now); err != nil {
+			return err
+		}
+		return m.passwordRepo.Insert(ctx, reset)
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.email.Send(ctx, &notifier.Message{
+		To:      ar.Email,
+		Subject: "Reset your password",
+		Body:    m.resetBody(token),
+	})
+}
+
+func (m passwordUsecase) resetBody(token string) string {
+	minutes := int(m.opts.ResetTokenTTL.Minutes())
+	if m.opts.ResetURL == "" {
+		return fmt.Sprintf("Your password reset token is %s. It expires in %d minutes.", token, minutes)
+	}
+	link := m.opts.ResetURL
+	if u, err := url.Parse(link); err == nil {
+		q := u.Query()
+		q.Set("token", token)
+		u.RawQuery = q.Encode()
+		link = u.String()
+	}
+	return fmt.Sprintf("Reset your password with this link, it expires in %d minutes:\n%s", minutes, link)
+}
+
+// Reset will set the new password of the account the token was issued to.
+// The token is used once, a failure of the identity server leaves it usable. The refresh tokens of the account are revoked.
+func (m passwordUsecase) Reset(c context.Context, ar *models.ResetPassword) error {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	reset, err := m.passwordRepo.GetByTokenHash(ctx, identityserver.HashToken(ar.Token))
+	if errors.Is(err, models.ErrNotFound) {
+		return password.ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if reset.UsedDate != nil || now.After(reset.ExpiresDate) {
+		return password.ErrInvalidResetToken
+	}
+	account, err := m.account(ctx, reset.AccountType, reset.AccountId)
+	if err != nil {
+		return err
+	}
+
+	return m.txManager.Do(ctx, func(ctx context.Context) error {
+		err := m.passwordRepo.Consume(ctx, reset.Id, now)
+		if errors.Is(err, models.ErrConflict) {
+			return password.ErrInvalidResetToken
+		}
+		if err != nil {
+			return err
+		}
+		if err := m.passwordRepo.RevokeForAccount(ctx, reset.AccountType, reset.AccountId, now); err != nil {
+			return err
+		}
+		if err := m.tokenRepo.RevokeForAccount(ctx, reset.AccountType, reset.AccountId, now); err != nil {
+			return err
+		}
+		account.Password = ar.NewPassword
+		_, err = m.identityServerUc.UpdateUser(ctx, account)
+		return err
+	})
+}
+
+// Change will set the new password of the authenticated account once the current one is checked with a password grant.
+// The pending reset tokens and the refresh tokens of the account are revoked.
+func (m passwordUsecase) Change(c context.Context, principal *models.Principal, ar *models.ChangePassword) error {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	account, err := m.account(ctx, principal.Type, principal.Id)
+	if err != nil {
+		return err
+	}
+	token, err := m.identityServerUc.GetToken(ctx, account.Username, ar.CurrentPassword)
+	if errors.Is(err, models.ErrUnAuthorize) {
+		return password.ErrWrongPassword
+	}
+	if err != nil {
+		return err
+	}
+	if token.RefreshToken != "" {
+		// the grant was only made to check the password
+		if err := m.identityServerUc.RevokeToken(ctx, token.RefreshToken, identityserver.TokenTypeRefresh); err != nil {
+			logrus.WithError(err).Warn("revoke the refresh token of the password check")
+		}
+	}
+
+	account.Password = ar.NewPassword
+	if _, err := m.identityServerUc.UpdateUser(ctx, account); err != nil {
+		return err
+	}
+	now := time.Now()
+	return m.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := m.passwordRepo.RevokeForAccount(ctx, principal.Type, principal.Id, now); err != nil {
+			return err
+		}
+		return m.tokenRepo.RevokeForAccount(ctx, principal.Type, principal.Id, now)
+	})
+}
+
+// accountIdByEmail will return the id of the active user or merchant owning the email address
+func (m passwordUsecase) accountIdByEmail(ctx context.Context, accountType string, email string) (string, error) {
+	switch accountType {
+	case models.PrincipalUser:
+		u, err := m.userRepo.GetByUserEmail(ctx, email)
+		if err != nil {
+			return "", err
+		}
+		return u.Id, nil
+	case models.PrincipalMerchant:
+		mc, err := m.merchantRepo.GetByMerchantEmail(ctx, email)
+		if err != nil {
+			return "", err
+		}
+		return mc.Id, nil
+	default:
+		return "", models.ErrBadParamInput
+	}
+}
+
+// account will build the identity server profile of the active user or merchant, so only the password changes
+func (m passwordUsecase) account(ctx context.Context, accountType string, id string) (*models.RegisterAndUpdateUser, error) {
+	switch accountType {
+	case models.PrincipalUser:
+		u, err := m.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return &models.RegisterAndUpdateUser{
+			Id:            u.Id,
+			Username:      u.UserEmail,
+			Name:          u.FullName,
+			Email:         u.UserEmail,
+			EmailVerified: u.EmailVerified == 1,
+		}, nil
+	case models.PrincipalMerchant:
+		mc, err := m.merchantRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return &models.RegisterAndUpdateUser{
+			Id:       mc.Id,
+			Username: mc.MerchantEmail,
+			Name:     mc.MerchantName,
+			Email:    mc.MerchantEmail,
+		}, nil
+	default:
+		return nil, models.ErrBadParamInput
+	}
+}
@@ -0,0 +1,190 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/identityserver"
+	_isMock "github.com/identityserver/mocks"
+	_merchantMock "github.com/merchant/mocks"
+	"github.com/models"
+	"github.com/notifier/memory"
+	"github.com/password"
+	"github.com/password/mocks"
+	ucase "github.com/password/usecase"
+	_txMock "github.com/transaction/mocks"
+	_userMock "github.com/user/mocks"
+)
+
+var opts = ucase.Options{ResetTokenTTL: time.Hour, ResetURL: "https://cgo.local/reset", ForgotInterval: time.Minute}
+
+func inTransaction() *_txMock.Manager {
+	mockTx := new(_txMock.Manager)
+	mockTx.On("Do", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+	return mockTx
+}
+
+func TestForgot(t *testing.T) {
+	t.Run("user", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByUserEmail", mock.Anything, "john@mail.com").Return(&models.User{Id: "u1"}, nil).Once()
+		mockRepo.On("GetLatestForAccount", mock.Anything, models.PrincipalUser, "u1").
+			Return(&models.PasswordReset{CreatedDate: time.Now().Add(-2 * time.Minute)}, nil).Once()
+		mockRepo.On("RevokeForAccount", mock.Anything, models.PrincipalUser, "u1", mock.Anything).Return(nil).Once()
+		var stored *models.PasswordReset
+		mockRepo.On("Insert", mock.Anything, mock.AnythingOfType("*models.PasswordReset")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*models.PasswordReset) }).Return(nil).Once()
+		email := memory.New()
+
+		u := ucase.NewpasswordUsecase(mockRepo, new(_isMock.Repository), mockUserRepo, new(_merchantMock.Repository), new(_isMock.Usecase), email, inTransaction(), opts, time.Second*2)
+		err := u.Forgot(context.TODO(), &models.ForgotPassword{Email: "john@mail.com", Type: models.PrincipalUser})
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+
+		msg := email.Last("john@mail.com")
+		require.NotNil(t, msg)
+		i := strings.Index(msg.Body, "token=")
+		require.True(t, i > 0)
+		token := msg.Body[i+len("token="):]
+		assert.Equal(t, identityserver.HashToken(token), stored.TokenHash)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresDate, time.Minute)
+	})
+
+	t.Run("too-soon", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByUserEmail", mock.Anything, "john@mail.com").Return(&models.User{Id: "u1"}, nil).Twice()
+		mockRepo.On("GetLatestForAccount", mock.Anything, models.PrincipalUser, "u1").Return(nil, models.ErrNotFound).Once()
+		mockRepo.On("RevokeForAccount", mock.Anything, models.PrincipalUser, "u1", mock.Anything).Return(nil).Once()
+		var stored *models.PasswordReset
+		mockRepo.On("Insert", mock.Anything, mock.AnythingOfType("*models.PasswordReset")).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*models.PasswordReset) }).Return(nil).Once()
+		mockRepo.On("GetLatestForAccount", mock.Anything, models.PrincipalUser, "u1").
+			Return(func(context.Context, string, string) *models.PasswordReset { return stored }, nil).Once()
+		email := memory.New()
+
+		u := ucase.NewpasswordUsecase(mockRepo, new(_isMock.Repository), mockUserRepo, new(_merchantMock.Repository), new(_isMock.Usecase), email, inTransaction(), opts, time.Second*2)
+		forgot := &models.ForgotPassword{Email: "john@mail.com", Type: models.PrincipalUser}
+		require.NoError(t, u.Forgot(context.TODO(), forgot))
+		require.NoError(t, u.Forgot(context.TODO(), forgot))
+		assert.Len(t, email.Messages(), 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown-email", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		mockMerchantRepo := new(_merchantMock.Repository)
+		mockMerchantRepo.On("GetByMerchantEmail", mock.Anything, "shop@mail.com").Return(nil, models.ErrNotFound).Once()
+		email := memory.New()
+
+		u := ucase.NewpasswordUsecase(mockRepo, new(_isMock.Repository), new(_userMock.Repository), mockMerchantRepo, new(_isMock.Usecase), email, inTransaction(), opts, time.Second*2)
+		err := u.Forgot(context.TODO(), &models.ForgotPassword{Email: "shop@mail.com", Type: models.PrincipalMerchant})
+		require.NoError(t, err)
+		assert.Empty(t, email.Messages())
+		mockRepo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+	})
+}
+
+func TestReset(t *testing.T) {
+	reset := func(expires time.Time, used *time.Time) *models.PasswordReset {
+		return &models.PasswordReset{Id: 3, AccountId: "m1", AccountType: models.PrincipalMerchant, ExpiresDate: expires, UsedDate: used}
+	}
+	hash := identityserver.HashToken("token")
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		mockMerchantRepo := new(_merchantMock.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockRepo.On("GetByTokenHash", mock.Anything, hash).Return(reset(time.Now().Add(time.Minute), nil), nil).Once()
+		mockMerchantRepo.On("GetByID", mock.Anything, "m1").
+			Return(&models.Merchant{Id: "m1", MerchantName: "Shop", MerchantEmail: "shop@mail.com"}, nil).Once()
+		mockRepo.On("Consume", mock.Anything, int64(3), mock.Anything).Return(nil).Once()
+		mockRepo.On("RevokeForAccount", mock.Anything, models.PrincipalMerchant, "m1", mock.Anything).Return(nil).Once()
+		mockTokenRepo := new(_isMock.Repository)
+		mockTokenRepo.On("RevokeForAccount", mock.Anything, models.PrincipalMerchant, "m1", mock.Anything).Return(nil).Once()
+		mockIs.On("UpdateUser", mock.Anything, &models.RegisterAndUpdateUser{
+			Id: "m1", Username: "shop@mail.com", Name: "Shop", Email: "shop@mail.com", Password: "new-secret",
+		}).Return(&models.RegisterAndUpdateUser{}, nil).Once()
+
+		u := ucase.NewpasswordUsecase(mockRepo, mockTokenRepo, new(_userMock.Repository), mockMerchantRepo, mockIs, memory.New(), inTransaction(), opts, time.Second*2)
+		err := u.Reset(context.TODO(), &models.ResetPassword{Token: "token", NewPassword: "new-secret"})
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockTokenRepo.AssertExpectations(t)
+		mockIs.AssertExpectations(t)
+	})
+
+	used := time.Now()
+	invalid := map[string]*models.PasswordReset{
+		"expired": reset(time.Now().Add(-time.Minute), nil),
+		"used":    reset(time.Now().Add(time.Minute), &used),
+	}
+	for name, r := range invalid {
+		r := r
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(mocks.Repository)
+			mockRepo.On("GetByTokenHash", mock.Anything, hash).Return(r, nil).Once()
+
+			u := ucase.NewpasswordUsecase(mockRepo, new(_isMock.Repository), new(_userMock.Repository), new(_merchantMock.Repository), new(_isMock.Usecase), memory.New(), inTransaction(), opts, time.Second*2)
+			err := u.Reset(context.TODO(), &models.ResetPassword{Token: "token", NewPassword: "new-secret"})
+			assert.Equal(t, password.ErrInvalidResetToken, err)
+			mockRepo.AssertNotCalled(t, "Consume", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		mockRepo.On("GetByTokenHash", mock.Anything, hash).Return(nil, models.ErrNotFound).Once()
+
+		u := ucase.NewpasswordUsecase(mockRepo, new(_isMock.Repository), new(_userMock.Repository), new(_merchantMock.Repository), new(_isMock.Usecase), memory.New(), inTransaction(), opts, time.Second*2)
+		err := u.Reset(context.TODO(), &models.ResetPassword{Token: "token", NewPassword: "new-secret"})
+		assert.Equal(t, password.ErrInvalidResetToken, err)
+	})
+}
+
+func TestChange(t *testing.T) {
+	principal := &models.Principal{Id: "u1", Type: models.PrincipalUser}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").
+			Return(&models.User{Id: "u1", UserEmail: "john@mail.com", FullName: "John", EmailVerified: 1}, nil).Once()
+		mockIs.On("GetToken", mock.Anything, "john@mail.com", "old-secret").Return(&models.GetToken{RefreshToken: "rt"}, nil).Once()
+		mockIs.On("RevokeToken", mock.Anything, "rt", identityserver.TokenTypeRefresh).Return(nil).Once()
+		mockIs.On("UpdateUser", mock.Anything, mock.MatchedBy(func(ar *models.RegisterAndUpdateUser) bool {
+			return ar.Id == "u1" && ar.Password == "new-secret" && ar.EmailVerified
+		})).Return(&models.RegisterAndUpdateUser{}, nil).Once()
+		mockRepo.On("RevokeForAccount", mock.Anything, models.PrincipalUser, "u1", mock.Anything).Return(nil).Once()
+		mockTokenRepo := new(_isMock.Repository)
+		mockTokenRepo.On("RevokeForAccount", mock.Anything, models.PrincipalUser, "u1", mock.Anything).Return(nil).Once()
+
+		u := ucase.NewpasswordUsecase(mockRepo, mockTokenRepo, mockUserRepo, new(_merchantMock.Repository), mockIs, memory.New(), inTransaction(), opts, time.Second*2)
+		err := u.Change(context.TODO(), principal, &models.ChangePassword{CurrentPassword: "old-secret", NewPassword: "new-secret"})
+		require.NoError(t, err)
+		mockIs.AssertExpectations(t)
+		mockRepo.AssertExpectations(t)
+		mockTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("wrong-password", func(t *testing.T) {
+		mockUserRepo := new(_userMock.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(&models.User{Id: "u1", UserEmail: "john@mail.com"}, nil).Once()
+		mockIs.On("GetToken", mock.Anything, "john@mail.com", "guess").Return(nil, models.ErrUnAuthorize).Once()
+
+		u := ucase.NewpasswordUsecase(new(mocks.Repository), new(_isMock.Repository), mockUserRepo, new(_merchantMock.Repository), mockIs, memory.New(), inTransaction(), opts, time.Second*2)
+		err := u.Change(context.TODO(), principal, &models.ChangePassword{CurrentPassword: "guess", NewPassword: "new-secret"})
+		assert.Equal(t, password.ErrWrongPassword, err)
+		mockIs.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+	})
+}
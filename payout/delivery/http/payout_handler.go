@@ -0,0 +1,200 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/middleware"
+	"github.com/models"
+	"github.com/payout"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// payoutDecision represent the request body of the admin decisions
+type payoutDecision struct {
+	Note string `json:"note" form:"note"`
+}
+
+// payoutHandler  represent the httphandler for payout
+type payoutHandler struct {
+	PayoutUsecase payout.Usecase
+}
+
+// NewpayoutHandler will initialize the payouts/ resources endpoint
+func NewpayoutHandler(e *echo.Echo, us payout.Usecase, mw *middleware.GoMiddleware) {
+	handler := &payoutHandler{
+		PayoutUsecase: us,
+	}
+	adminOnly := mw.Authorize(middleware.Policy{Roles: []string{models.RoleAdmin}})
+	ownerOrAdmin := mw.Authorize(middleware.Policy{
+		Roles:      []string{models.RoleMerchant, models.RoleAdmin},
+		OwnerParam: "id",
+	})
+	merchantOrAdmin := mw.Authorize(middleware.Policy{Roles: []string{models.RoleMerchant, models.RoleAdmin}})
+	e.POST("/merchants/:id/payouts", handler.Request, mw.Authenticate, ownerOrAdmin)
+	e.GET("/merchants/:id/payouts", handler.FetchMerchantPayout, mw.Authenticate, ownerOrAdmin)
+	e.GET("/payouts", handler.FetchPayout, mw.Authenticate, adminOnly)
+	e.GET("/payouts/:id", handler.GetByID, mw.Authenticate, merchantOrAdmin)
+	e.POST("/payouts/:id/approve", handler.Approve, mw.Authenticate, adminOnly)
+	e.POST("/payouts/:id/reject", handler.Reject, mw.Authenticate, adminOnly)
+	e.POST("/payouts/:id/paid", handler.MarkPaid, mw.Authenticate, adminOnly)
+}
+
+func isRequestValid(m *models.NewCommandPayout) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Request will submit a payout of the merchant by given request body
+func (a *payoutHandler) Request(c echo.Context) error {
+	var command models.NewCommandPayout
+	err := c.Bind(&command)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+	}
+	command.MerchantId = c.Param("id")
+
+	if ok, err := isRequestValid(&command); !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := a.PayoutUsecase.Request(ctx, &command, middleware.GetPrincipal(c).Username)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// FetchMerchantPayout will list the payouts of the merchant, optionally filtered by status
+func (a *payoutHandler) FetchMerchantPayout(c echo.Context) error {
+	return a.fetch(c, c.Param("id"))
+}
+
+// FetchPayout will list the payouts of every merchant, filtered by status and merchant_id
+func (a *payoutHandler) FetchPayout(c echo.Context) error {
+	return a.fetch(c, c.QueryParam("merchant_id"))
+}
+
+func (a *payoutHandler) fetch(c echo.Context, merchantId string) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
+	filter := &models.PayoutFilter{MerchantId: merchantId, Status: c.QueryParam("status")}
+	switch filter.Status {
+	case "", models.PayoutRequested, models.PayoutApproved, models.PayoutPaid, models.PayoutRejected:
+	default:
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: "status must be requested, approved, paid or rejected"})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	list, nextCursor, err := a.PayoutUsecase.Fetch(ctx, filter, cursor, int64(num))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, list)
+}
+
+// GetByID will get the payout and its history by given id, a merchant only reaches its own payouts
+func (a *payoutHandler) GetByID(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: models.ErrNotFound.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := a.PayoutUsecase.GetByID(ctx, id)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	principal := middleware.GetPrincipal(c)
+	if !principal.HasRole(models.RoleAdmin) && principal.Id != res.MerchantId {
+		return c.JSON(http.StatusForbidden, ResponseError{Message: models.ErrForbidden.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// Approve will debit the merchant balance and approve the payout
+func (a *payoutHandler) Approve(c echo.Context) error {
+	return a.decide(c, a.PayoutUsecase.Approve)
+}
+
+// Reject will refuse the payout
+func (a *payoutHandler) Reject(c echo.Context) error {
+	return a.decide(c, a.PayoutUsecase.Reject)
+}
+
+// MarkPaid will record that the bank transfer of the payout is done
+func (a *payoutHandler) MarkPaid(c echo.Context) error {
+	return a.decide(c, a.PayoutUsecase.MarkPaid)
+}
+
+func (a *payoutHandler) decide(c echo.Context, decision func(ctx context.Context, id int64, user string, note string) (*models.Payout, error)) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: models.ErrNotFound.Error()})
+	}
+	var body payoutDecision
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&body); err != nil {
+			return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+		}
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := decision(ctx, id, middleware.GetPrincipal(c).Username, body.Note)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	logrus.Error(err)
+	switch {
+	case errors.Is(err, models.ErrInternalServerError):
+		return http.StatusInternalServerError
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrUnAuthorize):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrConflict), errors.Is(err, models.ErrInsufficientBalance):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrBadParamInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,95 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/middleware/middlewaretest"
+	"github.com/models"
+	payoutHttp "github.com/payout/delivery/http"
+	"github.com/payout/mocks"
+)
+
+func newServer(mockUCase *mocks.Usecase) *echo.Echo {
+	e := echo.New()
+	payoutHttp.NewpayoutHandler(e, mockUCase, middlewaretest.New())
+	return e
+}
+
+func serve(e *echo.Echo, method string, target string, body string, token string) *httptest.ResponseRecorder {
+	return middlewaretest.Serve(e, middlewaretest.NewRequest(method, target, body), token)
+}
+
+func TestRequest(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Request", mock.Anything, mock.MatchedBy(func(ar *models.NewCommandPayout) bool {
+		return ar.MerchantId == "m1" && ar.Amount == 600
+	}), "shop@mail.com").Return(&models.Payout{Id: 7, MerchantId: "m1", Status: models.PayoutRequested}, nil).Once()
+
+	body := `{"amount":600,"bank_name":"BCA","bank_account_number":"123","bank_account_name":"Shop"}`
+	rec := serve(e, echo.POST, "/merchants/m1/payouts", body, middlewaretest.MerchantToken)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = serve(e, echo.POST, "/merchants/m2/payouts", body, middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(e, echo.POST, "/merchants/m1/payouts", `{"amount":600}`, middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestFetchPayout(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Fetch", mock.Anything, &models.PayoutFilter{Status: models.PayoutRequested}, "", int64(0)).
+		Return([]*models.Payout{{Id: 7}}, "", nil).Once()
+
+	rec := serve(e, echo.GET, "/payouts?status=requested", "", middlewaretest.AdminToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(e, echo.GET, "/payouts?status=cancelled", "", middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(e, echo.GET, "/payouts", "", middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestGetByID(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("GetByID", mock.Anything, int64(7)).Return(&models.Payout{Id: 7, MerchantId: "m1"}, nil).Once()
+	mockUCase.On("GetByID", mock.Anything, int64(8)).Return(&models.Payout{Id: 8, MerchantId: "m2"}, nil).Once()
+
+	rec := serve(e, echo.GET, "/payouts/7", "", middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(e, echo.GET, "/payouts/8", "", middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestApprove(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Approve", mock.Anything, int64(7), "admin@mail.com", "checked").
+		Return(&models.Payout{Id: 7, Status: models.PayoutApproved}, nil).Once()
+	mockUCase.On("Approve", mock.Anything, int64(8), "admin@mail.com", "").Return(nil, models.ErrInsufficientBalance).Once()
+
+	rec := serve(e, echo.POST, "/payouts/7/approve", `{"note":"checked"}`, middlewaretest.MerchantToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(e, echo.POST, "/payouts/7/approve", `{"note":"checked"}`, middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(e, echo.POST, "/payouts/8/approve", "", middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
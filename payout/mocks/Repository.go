@@ -0,0 +1,173 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *Repository) Fetch(ctx context.Context, filter *models.PayoutFilter, cursor string, num int64) ([]*models.Payout, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
+
+	var r0 []*models.Payout
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PayoutFilter, string, int64) []*models.Payout); ok {
+		r0 = rf(ctx, filter, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Payout)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, *models.PayoutFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *models.PayoutFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchHistory provides a mock function with given fields: ctx, payoutId
+func (_m *Repository) FetchHistory(ctx context.Context, payoutId int64) ([]models.PayoutStatus, error) {
+	ret := _m.Called(ctx, payoutId)
+
+	var r0 []models.PayoutStatus
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.PayoutStatus); ok {
+		r0 = rf(ctx, payoutId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.PayoutStatus)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, payoutId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByID(ctx context.Context, id int64) (*models.Payout, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Payout
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Payout); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Payout)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByIDForUpdate provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByIDForUpdate(ctx context.Context, id int64) (*models.Payout, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Payout
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Payout); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Payout)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, p
+func (_m *Repository) Insert(ctx context.Context, p *models.Payout) error {
+	ret := _m.Called(ctx, p)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Payout) error); ok {
+		r0 = rf(ctx, p)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertHistory provides a mock function with given fields: ctx, h
+func (_m *Repository) InsertHistory(ctx context.Context, h *models.PayoutStatus) error {
+	ret := _m.Called(ctx, h)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PayoutStatus) error); ok {
+		r0 = rf(ctx, h)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SumByStatus provides a mock function with given fields: ctx, merchantId, status
+func (_m *Repository) SumByStatus(ctx context.Context, merchantId string, status string) (int64, error) {
+	ret := _m.Called(ctx, merchantId, status)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) int64); ok {
+		r0 = rf(ctx, merchantId, status)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, merchantId, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, p, from
+func (_m *Repository) UpdateStatus(ctx context.Context, p *models.Payout, from string) error {
+	ret := _m.Called(ctx, p, from)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Payout, string) error); ok {
+		r0 = rf(ctx, p, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,156 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Approve provides a mock function with given fields: ctx, id, user, note
+func (_m *Usecase) Approve(ctx context.Context, id int64, user string, note string) (*models.Payout, error) {
+	ret := _m.Called(ctx, id, user, note)
+
+	var r0 *models.Payout
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *models.Payout); ok {
+		r0 = rf(ctx, id, user, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Payout)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, id, user, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *Usecase) Fetch(ctx context.Context, filter *models.PayoutFilter, cursor string, num int64) ([]*models.Payout, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
+
+	var r0 []*models.Payout
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PayoutFilter, string, int64) []*models.Payout); ok {
+		r0 = rf(ctx, filter, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Payout)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, *models.PayoutFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *models.PayoutFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Usecase) GetByID(ctx context.Context, id int64) (*models.Payout, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.Payout
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *models.Payout); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Payout)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkPaid provides a mock function with given fields: ctx, id, user, note
+func (_m *Usecase) MarkPaid(ctx context.Context, id int64, user string, note string) (*models.Payout, error) {
+	ret := _m.Called(ctx, id, user, note)
+
+	var r0 *models.Payout
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *models.Payout); ok {
+		r0 = rf(ctx, id, user, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Payout)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, id, user, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reject provides a mock function with given fields: ctx, id, user, note
+func (_m *Usecase) Reject(ctx context.Context, id int64, user string, note string) (*models.Payout, error) {
+	ret := _m.Called(ctx, id, user, note)
+
+	var r0 *models.Payout
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *models.Payout); ok {
+		r0 = rf(ctx, id, user, note)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Payout)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, id, user, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Request provides a mock function with given fields: ctx, ar, user
+func (_m *Usecase) Request(ctx context.Context, ar *models.NewCommandPayout, user string) (*models.Payout, error) {
+	ret := _m.Called(ctx, ar, user)
+
+	var r0 *models.Payout
+	if rf, ok := ret.Get(0).(func(context.Context, *models.NewCommandPayout, string) *models.Payout); ok {
+		r0 = rf(ctx, ar, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Payout)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.NewCommandPayout, string) error); ok {
+		r1 = rf(ctx, ar, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
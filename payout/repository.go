@@ -0,0 +1,20 @@
+package payout
+
+import (
+	"context"
+
+	"github.com/models"
+)
+
+// Repository represent the payout's repository contract.
+// UpdateStatus only moves a payout that is still in the given status, so a decision can not be made twice.
+type Repository interface {
+	Fetch(ctx context.Context, filter *models.PayoutFilter, cursor string, num int64) (res []*models.Payout, nextCursor string, err error)
+	GetByID(ctx context.Context, id int64) (*models.Payout, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*models.Payout, error)
+	Insert(ctx context.Context, p *models.Payout) error
+	UpdateStatus(ctx context.Context, p *models.Payout, from string) error
+	SumByStatus(ctx context.Context, merchantId string, status string) (int64, error)
+	FetchHistory(ctx context.Context, payoutId int64) ([]models.PayoutStatus, error)
+	InsertHistory(ctx context.Context, h *models.PayoutStatus) error
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/models"
+	"github.com/payout"
+	"github.com/transaction"
+)
+
+const (
+	// payoutColumns lists the columns in the order they are scanned by fetch
+	payoutColumns = `id, merchant_id, amount, currency, bank_name, bank_account_number, bank_account_name, status, transaction_id, created_by, created_date, modified_by, modified_date`
+)
+
+type payoutRepository struct {
+	Conn *sql.DB
+}
+
+// NewpayoutRepository will create an object that represent the payout.Repository interface
+func NewpayoutRepository(Conn *sql.DB) payout.Repository {
+	return &payoutRepository{Conn}
+}
+
+// conn will return the transaction of the unit of work ctx belongs to, or the connection pool
+func (m *payoutRepository) conn(ctx context.Context) transaction.DBTX {
+	return transaction.Conn(ctx, m.Conn)
+}
+
+func (m *payoutRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*models.Payout, error) {
+	rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}()
+
+	result := make([]*models.Payout, 0)
+	for rows.Next() {
+		t := new(models.Payout)
+		err = rows.Scan(
+			&t.Id,
+			&t.MerchantId,
+			&t.Amount,
+			&t.Currency,
+			&t.BankName,
+			&t.BankAccountNumber,
+			&t.BankAccountName,
+			&t.Status,
+			&t.TransactionId,
+			&t.CreatedBy,
+			&t.CreatedDate,
+			&t.ModifiedBy,
+			&t.ModifiedDate,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+// Fetch will list the payouts matching the filter, the most recent first
+func (m *payoutRepository) Fetch(ctx context.Context, filter *models.PayoutFilter, cursor string, num int64) ([]*models.Payout, string, error) {
+	before := int64(math.MaxInt64)
+	if cursor != "" {
+		decodedCursor, err := DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", models.ErrBadParamInput
+		}
+		before = decodedCursor
+	}
+
+	query := `SELECT ` + payoutColumns + ` FROM payouts WHERE id < ?`
+	args := []interface{}{before}
+	if filter != nil && filter.MerchantId != "" {
+		query += ` AND merchant_id = ?`
+		args = append(args, filter.MerchantId)
+	}
+	if filter != nil && filter.Status != "" {
+		query += ` AND status = ?`
+		args = append(args, filter.Status)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, num)
+
+	res, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(res) == int(num) {
+		nextCursor = EncodeCursor(res[len(res)-1].Id)
+	}
+
+	return res, nextCursor, nil
+}
+
+func (m *payoutRepository) GetByID(ctx context.Context, id int64) (*models.Payout, error) {
+	return m.getOne(ctx, `SELECT `+payoutColumns+` FROM payouts WHERE id = ?`, id)
+}
+
+// GetByIDForUpdate will return the payout and lock its row until the unit of work of ctx ends
+func (m *payoutRepository) GetByIDForUpdate(ctx context.Context, id int64) (*models.Payout, error) {
+	return m.getOne(ctx, `SELECT `+payoutColumns+` FROM payouts WHERE id = ? FOR UPDATE`, id)
+}
+
+func (m *payoutRepository) getOne(ctx context.Context, query string, args ...interface{}) (*models.Payout, error) {
+	list, err := m.fetch(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, models.ErrNotFound
+	}
+	return list[0], nil
+}
+
+func (m *payoutRepository) Insert(ctx context.Context, a *models.Payout) error {
+	query := `INSERT payouts SET merchant_id=? , amount=? , currency=? , bank_name=? , bank_account_number=? , bank_account_name=? , status=? , transaction_id=? , created_by=? , created_date=?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	a.CreatedDate = time.Now()
+	res, err := stmt.ExecContext(ctx, a.MerchantId, a.Amount, a.Currency, a.BankName, a.BankAccountNumber, a.BankAccountName,
+		a.Status, a.TransactionId, a.CreatedBy, a.CreatedDate)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.Id = lastID
+	return nil
+}
+
+func (m *payoutRepository) UpdateStatus(ctx context.Context, a *models.Payout, from string) error {
+	query := `UPDATE payouts SET status=? , transaction_id=? , modified_by=? , modified_date=? WHERE id = ? AND status = ?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, a.Status, a.TransactionId, a.ModifiedBy, now, a.Id, from)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrConflict
+	}
+	a.ModifiedDate = &now
+	return nil
+}
+
+// SumByStatus will return the total amount of the payouts of the merchant in the given status
+func (m *payoutRepository) SumByStatus(ctx context.Context, merchantId string, status string) (int64, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM payouts WHERE merchant_id = ? AND status = ?`
+
+	var sum int64
+	err := m.conn(ctx).QueryRowContext(ctx, query, merchantId, status).Scan(&sum)
+	if err != nil {
+		logrus.Error(err)
+		return 0, err
+	}
+	return sum, nil
+}
+
+// FetchHistory will list the status changes of the payout in the order they happened
+func (m *payoutRepository) FetchHistory(ctx context.Context, payoutId int64) ([]models.PayoutStatus, error) {
+	query := `SELECT id, payout_id, status, actor, note, created_date FROM payout_status_history WHERE payout_id = ? ORDER BY id`
+	rows, err := m.conn(ctx).QueryContext(ctx, query, payoutId)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}()
+
+	result := make([]models.PayoutStatus, 0)
+	for rows.Next() {
+		var h models.PayoutStatus
+		if err := rows.Scan(&h.Id, &h.PayoutId, &h.Status, &h.Actor, &h.Note, &h.CreatedDate); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, h)
+	}
+	return result, rows.Err()
+}
+
+func (m *payoutRepository) InsertHistory(ctx context.Context, h *models.PayoutStatus) error {
+	query := `INSERT payout_status_history SET payout_id=? , status=? , actor=? , note=? , created_date=?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	h.CreatedDate = time.Now()
+	res, err := stmt.ExecContext(ctx, h.PayoutId, h.Status, h.Actor, h.Note, h.CreatedDate)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	h.Id = lastID
+	return nil
+}
+
+// DecodeCursor will decode the id of the last payout of a page
+func DecodeCursor(cursor string) (int64, error) {
+	byt, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(byt), 10, 64)
+}
+
+// EncodeCursor will encode the id of the last payout of a page
+func EncodeCursor(id int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
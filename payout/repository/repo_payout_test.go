@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/models"
+	payoutRepo "github.com/payout/repository"
+)
+
+var payoutColumns = []string{"id", "merchant_id", "amount", "currency", "bank_name", "bank_account_number", "bank_account_name",
+	"status", "transaction_id", "created_by", "created_date", "modified_by", "modified_date"}
+
+func TestFetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	rows := sqlmock.NewRows(payoutColumns).
+		AddRow(7, "m1", 600, "IDR", "BCA", "123", "Shop", "requested", nil, "shop@mail.com", time.Now(), nil, nil)
+
+	query := "SELECT .+ FROM payouts WHERE id < \\? AND merchant_id = \\? AND status = \\? ORDER BY id DESC LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(sqlmock.AnyArg(), "m1", "requested", int64(1)).WillReturnRows(rows)
+
+	a := payoutRepo.NewpayoutRepository(db)
+	list, nextCursor, err := a.Fetch(context.TODO(), &models.PayoutFilter{MerchantId: "m1", Status: models.PayoutRequested}, "", 1)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Nil(t, list[0].TransactionId)
+	assert.Equal(t, payoutRepo.EncodeCursor(7), nextCursor)
+}
+
+func TestUpdateStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "UPDATE payouts SET status=\\? , transaction_id=\\? , modified_by=\\? , modified_date=\\? WHERE id = \\? AND status = \\?"
+	transactionId := int64(31)
+	admin := "admin@mail.com"
+	p := &models.Payout{Id: 7, Status: models.PayoutApproved, TransactionId: &transactionId, ModifiedBy: &admin}
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs("approved", &transactionId, &admin, sqlmock.AnyArg(), int64(7), "requested").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	prep = mock.ExpectPrepare(query)
+	prep.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := payoutRepo.NewpayoutRepository(db)
+	require.NoError(t, a.UpdateStatus(context.TODO(), p, models.PayoutRequested))
+	assert.NotNil(t, p.ModifiedDate)
+
+	err = a.UpdateStatus(context.TODO(), p, models.PayoutRequested)
+	assert.Equal(t, models.ErrConflict, err)
+}
+
+func TestSumByStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "SELECT COALESCE\\(SUM\\(amount\\), 0\\) FROM payouts WHERE merchant_id = \\? AND status = \\?"
+	mock.ExpectQuery(query).WithArgs("m1", "requested").WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(900))
+
+	a := payoutRepo.NewpayoutRepository(db)
+	sum, err := a.SumByStatus(context.TODO(), "m1", models.PayoutRequested)
+	require.NoError(t, err)
+	assert.Equal(t, int64(900), sum)
+}
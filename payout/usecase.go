@@ -0,0 +1,17 @@
+package payout
+
+import (
+	"context"
+
+	"github.com/models"
+)
+
+// Usecase represent the payout's usecases
+type Usecase interface {
+	Request(ctx context.Context, ar *models.NewCommandPayout, user string) (*models.Payout, error)
+	GetByID(ctx context.Context, id int64) (*models.Payout, error)
+	Fetch(ctx context.Context, filter *models.PayoutFilter, cursor string, num int64) ([]*models.Payout, string, error)
+	Approve(ctx context.Context, id int64, user string, note string) (*models.Payout, error)
+	Reject(ctx context.Context, id int64, user string, note string) (*models.Payout, error)
+	MarkPaid(ctx context.Context, id int64, user string, note string) (*models.Payout, error)
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/ledger"
+	"github.com/merchant"
+	"github.com/models"
+	"github.com/payout"
+	"github.com/transaction"
+)
+
+type payoutUsecase struct {
+	payoutRepo     payout.Repository
+	merchantRepo   merchant.Repository
+	ledgerUc       ledger.Usecase
+	txManager      transaction.Manager
+	contextTimeout time.Duration
+}
+
+// NewpayoutUsecase will create new an payoutUsecase object representation of payout.Usecase interface
+func NewpayoutUsecase(p payout.Repository, mr merchant.Repository, l ledger.Usecase, txManager transaction.Manager, timeout time.Duration) payout.Usecase {
+	return &payoutUsecase{
+		payoutRepo:     p,
+		merchantRepo:   mr,
+		ledgerUc:       l,
+		txManager:      txManager,
+		contextTimeout: timeout,
+	}
+}
+
+// Request will submit a payout of the merchant. The amount must be covered by the balance
+// minus the payouts still waiting for a decision, otherwise models.ErrInsufficientBalance is returned.
+func (m payoutUsecase) Request(c context.Context, ar *models.NewCommandPayout, user string) (*models.Payout, error) {
+	if ar.Amount <= 0 {
+		return nil, models.ErrBadParamInput
+	}
+
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	p := &models.Payout{
+		MerchantId:        ar.MerchantId,
+		Amount:            ar.Amount,
+		Currency:          models.CurrencyIDR,
+		BankName:          ar.BankName,
+		BankAccountNumber: ar.BankAccountNumber,
+		BankAccountName:   ar.BankAccountName,
+		Status:            models.PayoutRequested,
+		CreatedBy:         user,
+	}
+	err := m.txManager.Do(ctx, func(ctx context.Context) error {
+		// the merchant row stays locked until the end of the transaction, so concurrent requests
+		// of the same merchant see each other's pending amount
+		balance, err := m.merchantRepo.GetBalanceForUpdate(ctx, ar.MerchantId)
+		if err != nil {
+			return err
+		}
+		pending, err := m.payoutRepo.SumByStatus(ctx, ar.MerchantId, models.PayoutRequested)
+		if err != nil {
+			return err
+		}
+		if ar.Amount > balance-pending {
+			return models.ErrInsufficientBalance
+		}
+
+		if err := m.payoutRepo.Insert(ctx, p); err != nil {
+			return err
+		}
+		return m.recordStatus(ctx, p, user, ar.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// GetByID will return the payout with its status history
+func (m payoutUsecase) GetByID(c context.Context, id int64) (*models.Payout, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	res, err := m.payoutRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	res.History, err = m.payoutRepo.FetchHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (m payoutUsecase) Fetch(c context.Context, filter *models.PayoutFilter, cursor string, num int64) ([]*models.Payout, string, error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	return m.payoutRepo.Fetch(ctx, filter, cursor, num)
+}
+
+// Approve will debit the payout amount from the merchant balance through the ledger and approve the payout,
+// both or neither happen. The debit is refused with models.ErrInsufficientBalance when the balance does not cover it.
+func (m payoutUsecase) Approve(c context.Context, id int64, user string, note string) (*models.Payout, error) {
+	return m.transition(c, id, models.PayoutRequested, models.PayoutApproved, user, note, func(ctx context.Context, p *models.Payout) error {
+		entry, err := m.ledgerUc.Record(ctx, &models.NewCommandMerchantTransaction{
+			MerchantId:     p.MerchantId,
+			Type:           models.TransactionPayout,
+			Amount:         p.Amount,
+			IdempotencyKey: "payout-" + strconv.FormatInt(p.Id, 10),
+			Reference:      "payout:" + strconv.FormatInt(p.Id, 10),
+			Description:    "payout to " + p.BankName + " " + p.BankAccountNumber,
+		}, user)
+		if err != nil {
+			return err
+		}
+		p.TransactionId = &entry.Id
+		return nil
+	})
+}
+
+// Reject will refuse a requested payout, the merchant balance is left untouched
+func (m payoutUsecase) Reject(c context.Context, id int64, user string, note string) (*models.Payout, error) {
+	return m.transition(c, id, models.PayoutRequested, models.PayoutRejected, user, note, nil)
+}
+
+// MarkPaid will record that the bank transfer of an approved payout is done
+func (m payoutUsecase) MarkPaid(c context.Context, id int64, user string, note string) (*models.Payout, error) {
+	return m.transition(c, id, models.PayoutApproved, models.PayoutPaid, user, note, nil)
+}
+
+// transition will move the payout from one status to the next in one transaction, running apply before the change.
+// A payout that is not in the from status anymore gives models.ErrConflict.
+func (m payoutUsecase) transition(c context.Context, id int64, from string, to string, user string, note string,
+	apply func(ctx context.Context, p *models.Payout) error) (*models.Payout, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	var res *models.Payout
+	err := m.txManager.Do(ctx, func(ctx context.Context) error {
+		p, err := m.payoutRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if p.Status != from {
+			return models.ErrConflict
+		}
+		if apply != nil {
+			if err := apply(ctx, p); err != nil {
+				return err
+			}
+		}
+
+		p.Status = to
+		p.ModifiedBy = &user
+		if err := m.payoutRepo.UpdateStatus(ctx, p, from); err != nil {
+			return err
+		}
+		if p.History, err = m.payoutRepo.FetchHistory(ctx, p.Id); err != nil {
+			return err
+		}
+		if err := m.recordStatus(ctx, p, user, note); err != nil {
+			return err
+		}
+		res = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// recordStatus will add the current status of the payout to its history
+func (m payoutUsecase) recordStatus(ctx context.Context, p *models.Payout, user string, note string) error {
+	h := models.PayoutStatus{
+		PayoutId: p.Id,
+		Status:   p.Status,
+		Actor:    user,
+		Note:     note,
+	}
+	if err := m.payoutRepo.InsertHistory(ctx, &h); err != nil {
+		return err
+	}
+	p.History = append(p.History, h)
+	return nil
+}
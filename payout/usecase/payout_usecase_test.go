@@ -0,0 +1,173 @@
+package usecase_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	_ledgerMock "github.com/ledger/mocks"
+	_merchantMock "github.com/merchant/mocks"
+	"github.com/models"
+	"github.com/payout/mocks"
+	ucase "github.com/payout/usecase"
+	_txMock "github.com/transaction/mocks"
+)
+
+// inTransaction will run the units of work one at a time, like the row lock of the merchant does
+func inTransaction() *_txMock.Manager {
+	var mu sync.Mutex
+	mockTx := new(_txMock.Manager)
+	mockTx.On("Do", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return fn(ctx)
+		})
+	return mockTx
+}
+
+func newCommand(amount int64) *models.NewCommandPayout {
+	return &models.NewCommandPayout{MerchantId: "m1", Amount: amount, BankName: "BCA", BankAccountNumber: "123", BankAccountName: "Shop"}
+}
+
+func TestRequest(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPayoutRepo := new(mocks.Repository)
+		mockMerchantRepo := new(_merchantMock.Repository)
+		mockMerchantRepo.On("GetBalanceForUpdate", mock.Anything, "m1").Return(int64(1000), nil).Once()
+		mockPayoutRepo.On("SumByStatus", mock.Anything, "m1", models.PayoutRequested).Return(int64(400), nil).Once()
+		mockPayoutRepo.On("Insert", mock.Anything, mock.MatchedBy(func(p *models.Payout) bool {
+			return p.Status == models.PayoutRequested && p.Amount == 600
+		})).Return(nil).Run(func(args mock.Arguments) { args.Get(1).(*models.Payout).Id = 7 }).Once()
+		mockPayoutRepo.On("InsertHistory", mock.Anything, mock.MatchedBy(func(h *models.PayoutStatus) bool {
+			return h.PayoutId == 7 && h.Status == models.PayoutRequested && h.Actor == "shop@mail.com"
+		})).Return(nil).Once()
+
+		u := ucase.NewpayoutUsecase(mockPayoutRepo, mockMerchantRepo, new(_ledgerMock.Usecase), inTransaction(), time.Second*2)
+		res, err := u.Request(context.TODO(), newCommand(600), "shop@mail.com")
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), res.Id)
+		assert.Len(t, res.History, 1)
+		mockPayoutRepo.AssertExpectations(t)
+	})
+
+	t.Run("pending-payouts-count", func(t *testing.T) {
+		mockPayoutRepo := new(mocks.Repository)
+		mockMerchantRepo := new(_merchantMock.Repository)
+		mockMerchantRepo.On("GetBalanceForUpdate", mock.Anything, "m1").Return(int64(1000), nil).Once()
+		mockPayoutRepo.On("SumByStatus", mock.Anything, "m1", models.PayoutRequested).Return(int64(500), nil).Once()
+
+		u := ucase.NewpayoutUsecase(mockPayoutRepo, mockMerchantRepo, new(_ledgerMock.Usecase), inTransaction(), time.Second*2)
+		_, err := u.Request(context.TODO(), newCommand(600), "shop@mail.com")
+		assert.Equal(t, models.ErrInsufficientBalance, err)
+		mockPayoutRepo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		var mu sync.Mutex
+		pending := int64(0)
+		mockPayoutRepo := new(mocks.Repository)
+		mockMerchantRepo := new(_merchantMock.Repository)
+		mockMerchantRepo.On("GetBalanceForUpdate", mock.Anything, "m1").Return(int64(1000), nil)
+		mockPayoutRepo.On("SumByStatus", mock.Anything, "m1", models.PayoutRequested).
+			Return(func(context.Context, string, string) int64 {
+				mu.Lock()
+				defer mu.Unlock()
+				return pending
+			}, nil)
+		mockPayoutRepo.On("Insert", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			mu.Lock()
+			defer mu.Unlock()
+			pending += args.Get(1).(*models.Payout).Amount
+		})
+		mockPayoutRepo.On("InsertHistory", mock.Anything, mock.Anything).Return(nil)
+
+		u := ucase.NewpayoutUsecase(mockPayoutRepo, mockMerchantRepo, new(_ledgerMock.Usecase), inTransaction(), time.Second*2)
+		var wg sync.WaitGroup
+		var accepted int32
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := u.Request(context.TODO(), newCommand(300), "shop@mail.com"); err == nil {
+					atomic.AddInt32(&accepted, 1)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(3), accepted)
+		assert.Equal(t, int64(900), pending)
+	})
+}
+
+func TestApprove(t *testing.T) {
+	requested := func() *models.Payout {
+		return &models.Payout{Id: 7, MerchantId: "m1", Amount: 600, BankName: "BCA", BankAccountNumber: "123", Status: models.PayoutRequested}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockPayoutRepo := new(mocks.Repository)
+		mockLedgerUc := new(_ledgerMock.Usecase)
+		mockPayoutRepo.On("GetByIDForUpdate", mock.Anything, int64(7)).Return(requested(), nil).Once()
+		mockLedgerUc.On("Record", mock.Anything, mock.MatchedBy(func(ar *models.NewCommandMerchantTransaction) bool {
+			return ar.Type == models.TransactionPayout && ar.Amount == 600 && ar.IdempotencyKey == "payout-7"
+		}), "admin@mail.com").Return(&models.MerchantTransaction{Id: 31}, nil).Once()
+		mockPayoutRepo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(p *models.Payout) bool {
+			return p.Status == models.PayoutApproved && *p.TransactionId == 31
+		}), models.PayoutRequested).Return(nil).Once()
+		mockPayoutRepo.On("FetchHistory", mock.Anything, int64(7)).
+			Return([]models.PayoutStatus{{PayoutId: 7, Status: models.PayoutRequested}}, nil).Once()
+		mockPayoutRepo.On("InsertHistory", mock.Anything, mock.MatchedBy(func(h *models.PayoutStatus) bool {
+			return h.Status == models.PayoutApproved && h.Actor == "admin@mail.com" && h.Note == "ok"
+		})).Return(nil).Once()
+
+		u := ucase.NewpayoutUsecase(mockPayoutRepo, new(_merchantMock.Repository), mockLedgerUc, inTransaction(), time.Second*2)
+		res, err := u.Approve(context.TODO(), 7, "admin@mail.com", "ok")
+		require.NoError(t, err)
+		assert.Equal(t, models.PayoutApproved, res.Status)
+		assert.Len(t, res.History, 2)
+		mockPayoutRepo.AssertExpectations(t)
+		mockLedgerUc.AssertExpectations(t)
+	})
+
+	t.Run("insufficient-balance", func(t *testing.T) {
+		mockPayoutRepo := new(mocks.Repository)
+		mockLedgerUc := new(_ledgerMock.Usecase)
+		mockPayoutRepo.On("GetByIDForUpdate", mock.Anything, int64(7)).Return(requested(), nil).Once()
+		mockLedgerUc.On("Record", mock.Anything, mock.Anything, "admin@mail.com").Return(nil, models.ErrInsufficientBalance).Once()
+
+		u := ucase.NewpayoutUsecase(mockPayoutRepo, new(_merchantMock.Repository), mockLedgerUc, inTransaction(), time.Second*2)
+		_, err := u.Approve(context.TODO(), 7, "admin@mail.com", "")
+		assert.Equal(t, models.ErrInsufficientBalance, err)
+		mockPayoutRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("already-decided", func(t *testing.T) {
+		mockPayoutRepo := new(mocks.Repository)
+		mockLedgerUc := new(_ledgerMock.Usecase)
+		rejected := requested()
+		rejected.Status = models.PayoutRejected
+		mockPayoutRepo.On("GetByIDForUpdate", mock.Anything, int64(7)).Return(rejected, nil).Once()
+
+		u := ucase.NewpayoutUsecase(mockPayoutRepo, new(_merchantMock.Repository), mockLedgerUc, inTransaction(), time.Second*2)
+		_, err := u.Approve(context.TODO(), 7, "admin@mail.com", "")
+		assert.Equal(t, models.ErrConflict, err)
+		mockLedgerUc.AssertNotCalled(t, "Record", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMarkPaid(t *testing.T) {
+	mockPayoutRepo := new(mocks.Repository)
+	mockPayoutRepo.On("GetByIDForUpdate", mock.Anything, int64(7)).
+		Return(&models.Payout{Id: 7, MerchantId: "m1", Amount: 600, Status: models.PayoutRequested}, nil).Once()
+
+	u := ucase.NewpayoutUsecase(mockPayoutRepo, new(_merchantMock.Repository), new(_ledgerMock.Usecase), inTransaction(), time.Second*2)
+	_, err := u.MarkPaid(context.TODO(), 7, "admin@mail.com", "")
+	assert.Equal(t, models.ErrConflict, err)
+}
@@ -0,0 +1,16 @@
+package picture
+
+import (
+	"github.com/models"
+)
+
+var (
+	// ErrTooLarge will throw if the uploaded file or the image it holds is bigger than allowed
+	ErrTooLarge = models.NewBadParam("The picture is too large")
+	// ErrUnsupportedType will throw if the uploaded file is not a JPEG, PNG or GIF image
+	ErrUnsupportedType = models.NewBadParam("The picture must be a JPEG, PNG or GIF image")
+	// ErrMissingFile will throw if the request is not a multipart form holding the picture file
+	ErrMissingFile = models.NewBadParam("The picture must be sent as the file field of a multipart form")
+	// ErrCorrupt will throw if the uploaded file looks like an image but can not be decoded
+	ErrCorrupt = models.NewBadParam("The picture can not be read")
+)
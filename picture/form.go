@@ -0,0 +1,24 @@
+package picture
+
+import (
+	"io"
+	"net/http"
+)
+
+// FormFile will return the file sent as the given field of a multipart/form-data request.
+// The parts are read as a stream, the upload is not buffered before the Uploader limits its size.
+func FormFile(r *http.Request, field string) (io.Reader, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, ErrMissingFile
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, ErrMissingFile
+		}
+		if part.FormName() == field && part.FileName() != "" {
+			return part, nil
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import io "io"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Uploader is an autogenerated mock type for the Uploader type
+type Uploader struct {
+	mock.Mock
+}
+
+// Remove provides a mock function with given fields: ctx, prefix, url
+func (_m *Uploader) Remove(ctx context.Context, prefix string, url string) error {
+	ret := _m.Called(ctx, prefix, url)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, prefix, url)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Upload provides a mock function with given fields: ctx, prefix, r
+func (_m *Uploader) Upload(ctx context.Context, prefix string, r io.Reader) (*models.Picture, error) {
+	ret := _m.Called(ctx, prefix, r)
+
+	var r0 *models.Picture
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) *models.Picture); ok {
+		r0 = rf(ctx, prefix, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Picture)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader) error); ok {
+		r1 = rf(ctx, prefix, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,22 @@
+// Package picture turns the uploaded profile pictures and logos into the standard square thumbnails and stores them.
+package picture
+
+import (
+	"context"
+	"io"
+
+	"github.com/models"
+)
+
+// Sizes lists the edge in pixels of the thumbnails made of every upload, the smallest first.
+// The url of the largest one is the one saved on the profile.
+var Sizes = []int{64, 256, 512}
+
+// Uploader represent the picture upload contract.
+// Upload will read the image from r and store one thumbnail per size under prefix-<version>-<size>.<ext>,
+// e.g. users/u1/avatar-9f86d081884c7d65-256.jpg, the version being taken from the content so a new picture
+// gets new urls. Remove will delete the thumbnails of the upload whose url of the largest one is given.
+type Uploader interface {
+	Upload(ctx context.Context, prefix string, r io.Reader) (*models.Picture, error)
+	Remove(ctx context.Context, prefix string, url string) error
+}
@@ -0,0 +1,61 @@
+package picture
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Thumbnail will crop the centered square of img and scale it to size x size pixels.
+// Every pixel of the thumbnail is the average of the source pixels it covers, so shrinking does not alias.
+func Thumbnail(img image.Image, size int) *image.RGBA {
+	b := img.Bounds()
+	edge := b.Dx()
+	if b.Dy() < edge {
+		edge = b.Dy()
+	}
+	crop := image.Rect(0, 0, edge, edge).Add(image.Pt(b.Min.X+(b.Dx()-edge)/2, b.Min.Y+(b.Dy()-edge)/2))
+
+	// work on premultiplied RGBA so the transparent pixels do not darken their neighbours
+	src := image.NewRGBA(image.Rect(0, 0, edge, edge))
+	draw.Draw(src, src.Bounds(), img, crop.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	if edge == 0 {
+		return dst
+	}
+	for y := 0; y < size; y++ {
+		y0, y1 := span(y, size, edge)
+		for x := 0; x < size; x++ {
+			x0, x1 := span(x, size, edge)
+			var r, g, bl, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					bl += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+			d := dst.Pix[y*dst.Stride+x*4 : y*dst.Stride+x*4+4]
+			d[0] = uint8((r + n/2) / n)
+			d[1] = uint8((g + n/2) / n)
+			d[2] = uint8((bl + n/2) / n)
+			d[3] = uint8((a + n/2) / n)
+		}
+	}
+	return dst
+}
+
+// span will return the source pixels [from, to) covered by the destination pixel i,
+// at least one pixel so that enlarging repeats the nearest source pixel
+func span(i int, size int, edge int) (from int, to int) {
+	from = i * edge / size
+	to = (i + 1) * edge / size
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
@@ -0,0 +1,159 @@
+package picture
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/models"
+	"github.com/storage"
+)
+
+const (
+	// maxPixels bounds the decoded size of an image, a small file can declare a huge image
+	maxPixels = 40 * 1000 * 1000
+
+	jpegQuality = 85
+
+	// versionLength is the number of hex digits of the content hash in the keys of the thumbnails
+	versionLength = 16
+)
+
+// format represent how the thumbnails of an uploaded image type are encoded
+type format struct {
+	contentType string
+	ext         string
+	encode      func(w io.Writer, img image.Image) error
+}
+
+var (
+	jpegFormat = format{"image/jpeg", ".jpg", func(w io.Writer, img image.Image) error {
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	}}
+	pngFormat = format{"image/png", ".png", png.Encode}
+
+	// formats maps the sniffed content type of an upload to the format of its thumbnails,
+	// the GIFs become PNGs because only their first frame is kept
+	formats = map[string]format{
+		"image/jpeg": jpegFormat,
+		"image/png":  pngFormat,
+		"image/gif":  pngFormat,
+	}
+)
+
+type uploader struct {
+	store   storage.Storage
+	maxSize int64
+}
+
+// New will create an Uploader storing the thumbnails in store, uploads bigger than maxSize bytes are rejected
+func New(store storage.Storage, maxSize int64) Uploader {
+	return &uploader{store: store, maxSize: maxSize}
+}
+
+func (u *uploader) Upload(ctx context.Context, prefix string, r io.Reader) (*models.Picture, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, u.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > u.maxSize {
+		return nil, ErrTooLarge
+	}
+
+	// the content type is sniffed from the data, the one declared by the client is not trusted
+	f, ok := formats[http.DetectContentType(data)]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+
+	sum := sha256.Sum256(data)
+	version := hex.EncodeToString(sum[:])[:versionLength]
+	res := &models.Picture{Thumbnails: make([]models.Thumbnail, 0, len(Sizes))}
+	for _, size := range Sizes {
+		var buf bytes.Buffer
+		if err := f.encode(&buf, Thumbnail(img, size)); err != nil {
+			return nil, err
+		}
+		url, err := u.store.Put(ctx, key(prefix, version, size, f.ext), f.contentType, buf.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		res.Thumbnails = append(res.Thumbnails, models.Thumbnail{Size: size, Url: url})
+		res.Url = url
+	}
+	return res, nil
+}
+
+// Remove will delete every thumbnail of the upload the url belongs to.
+// A url that was not made by Upload under prefix, such as an empty one, has nothing to delete.
+func (u *uploader) Remove(ctx context.Context, prefix string, url string) error {
+	version, ext, ok := parseURL(prefix, url)
+	if !ok {
+		return nil
+	}
+	for _, size := range Sizes {
+		if err := u.store.Delete(ctx, key(prefix, version, size, ext)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// key will return the storage key of the thumbnail, the uploads made before the versions have none
+func key(prefix string, version string, size int, ext string) string {
+	if version == "" {
+		return fmt.Sprintf("%s-%d%s", prefix, size, ext)
+	}
+	return fmt.Sprintf("%s-%s-%d%s", prefix, version, size, ext)
+}
+
+// parseURL will return the version and the extension of the upload under prefix, given the url of its largest thumbnail
+func parseURL(prefix string, url string) (string, string, bool) {
+	name := url[strings.LastIndex(url, "/")+1:]
+	ext := path.Ext(name)
+	if ext != jpegFormat.ext && ext != pngFormat.ext {
+		return "", "", false
+	}
+	stem := strings.TrimSuffix(name, ext)
+	largest := "-" + strconv.Itoa(Sizes[len(Sizes)-1])
+	if !strings.HasSuffix(stem, largest) {
+		return "", "", false
+	}
+	stem = strings.TrimSuffix(stem, largest)
+
+	base := path.Base(prefix)
+	if stem == base {
+		return "", ext, true
+	}
+	version := strings.TrimPrefix(stem, base+"-")
+	if version == stem || len(version) != versionLength {
+		return "", "", false
+	}
+	if _, err := hex.DecodeString(version); err != nil {
+		return "", "", false
+	}
+	return version, ext, true
+}
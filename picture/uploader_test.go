@@ -0,0 +1,180 @@
+package picture_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/models"
+	"github.com/picture"
+	"github.com/storage/local"
+)
+
+func newUploader(t *testing.T, maxSize int64) (picture.Uploader, string) {
+	dir, err := ioutil.TempDir("", "picture")
+	require.NoError(t, err)
+	return picture.New(local.New(dir, "/uploads"), maxSize), dir
+}
+
+func encoded(t *testing.T, encode func(*bytes.Buffer, image.Image) error, w int, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, encode(&buf, img))
+	return buf.Bytes()
+}
+
+// version will return the version Upload gives the thumbnails of the data
+func version(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func encodeJPEG(buf *bytes.Buffer, img image.Image) error { return jpeg.Encode(buf, img, nil) }
+func encodePNG(buf *bytes.Buffer, img image.Image) error  { return png.Encode(buf, img) }
+func encodeGIF(buf *bytes.Buffer, img image.Image) error  { return gif.Encode(buf, img, nil) }
+
+func TestUpload(t *testing.T) {
+	u, dir := newUploader(t, 1<<20)
+	defer os.RemoveAll(dir)
+
+	data := encoded(t, encodeJPEG, 300, 200)
+	v := version(data)
+	res, err := u.Upload(context.TODO(), "users/u1/avatar", bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "/uploads/users/u1/avatar-"+v+"-512.jpg", res.Url)
+	assert.Equal(t, []models.Thumbnail{
+		{Size: 64, Url: "/uploads/users/u1/avatar-" + v + "-64.jpg"},
+		{Size: 256, Url: "/uploads/users/u1/avatar-" + v + "-256.jpg"},
+		{Size: 512, Url: "/uploads/users/u1/avatar-" + v + "-512.jpg"},
+	}, res.Thumbnails)
+
+	for _, size := range picture.Sizes {
+		f, err := os.Open(filepath.Join(dir, "users", "u1", "avatar-"+v+"-"+strconv.Itoa(size)+".jpg"))
+		require.NoError(t, err)
+		config, format, err := image.DecodeConfig(f)
+		f.Close()
+		require.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, size, config.Width)
+		assert.Equal(t, size, config.Height)
+	}
+}
+
+func TestUploadPNGAndGIF(t *testing.T) {
+	u, dir := newUploader(t, 1<<20)
+	defer os.RemoveAll(dir)
+
+	data := encoded(t, encodePNG, 100, 100)
+	res, err := u.Upload(context.TODO(), "merchants/m1/logo", bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "/uploads/merchants/m1/logo-"+version(data)+"-512.png", res.Url)
+
+	data = encoded(t, encodeGIF, 100, 100)
+	res, err = u.Upload(context.TODO(), "merchants/m2/logo", bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "/uploads/merchants/m2/logo-"+version(data)+"-512.png", res.Url)
+	_, err = os.Stat(filepath.Join(dir, "merchants", "m2", "logo-"+version(data)+"-64.png"))
+	assert.NoError(t, err)
+}
+
+func TestRemove(t *testing.T) {
+	u, dir := newUploader(t, 1<<20)
+	defer os.RemoveAll(dir)
+	files := func() []string {
+		infos, err := ioutil.ReadDir(filepath.Join(dir, "users", "u1"))
+		require.NoError(t, err)
+		names := make([]string, len(infos))
+		for i, info := range infos {
+			names[i] = info.Name()
+		}
+		return names
+	}
+
+	first, err := u.Upload(context.TODO(), "users/u1/avatar", bytes.NewReader(encoded(t, encodeJPEG, 100, 100)))
+	require.NoError(t, err)
+	data := encoded(t, encodePNG, 100, 100)
+	second, err := u.Upload(context.TODO(), "users/u1/avatar", bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.Url, second.Url)
+	assert.Len(t, files(), 6)
+
+	require.NoError(t, u.Remove(context.TODO(), "users/u1/avatar", first.Url))
+	v := version(data)
+	assert.Equal(t, []string{"avatar-" + v + "-256.png", "avatar-" + v + "-512.png", "avatar-" + v + "-64.png"}, files())
+
+	// the urls that were not made under the prefix are left alone
+	for _, url := range []string{"", "https://cdn.local/me.jpg", "/uploads/users/u1/avatar-" + v + "-64.png", "/uploads/users/u1/logo-" + v + "-512.png"} {
+		require.NoError(t, u.Remove(context.TODO(), "users/u1/avatar", url))
+	}
+	assert.Len(t, files(), 3)
+
+	// the thumbnails stored before the versions
+	for _, size := range picture.Sizes {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "users", "u1", "avatar-"+strconv.Itoa(size)+".jpg"), []byte("jpeg"), 0644))
+	}
+	require.NoError(t, u.Remove(context.TODO(), "users/u1/avatar", "/uploads/users/u1/avatar-512.jpg"))
+	assert.Len(t, files(), 3)
+}
+
+func TestUploadRejected(t *testing.T) {
+	u, dir := newUploader(t, 1024)
+	defer os.RemoveAll(dir)
+
+	tests := map[string]struct {
+		data []byte
+		err  error
+	}{
+		"too large":   {encoded(t, encodeJPEG, 200, 200), picture.ErrTooLarge},
+		"not image":   {[]byte("%PDF-1.4 not a picture"), picture.ErrUnsupportedType},
+		"svg":         {[]byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), picture.ErrUnsupportedType},
+		"corrupt png": {append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...), picture.ErrCorrupt},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := u.Upload(context.TODO(), "users/u1/avatar", bytes.NewReader(tt.data))
+			assert.Equal(t, tt.err, err)
+			assert.True(t, errors.Is(err, models.ErrBadParamInput))
+		})
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestThumbnail(t *testing.T) {
+	// a 4x2 image, left half black and right half white, keeps its centered 2x2 square
+	img := image.NewGray(image.Rect(0, 0, 4, 2))
+	for y := 0; y < 2; y++ {
+		img.SetGray(2, y, color.Gray{Y: 255})
+		img.SetGray(3, y, color.Gray{Y: 255})
+	}
+
+	thumb := picture.Thumbnail(img, 1)
+	assert.Equal(t, image.Rect(0, 0, 1, 1), thumb.Bounds())
+	assert.Equal(t, color.RGBA{R: 128, G: 128, B: 128, A: 255}, thumb.RGBAAt(0, 0))
+
+	thumb = picture.Thumbnail(img, 4)
+	assert.Equal(t, color.RGBA{A: 255}, thumb.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, thumb.RGBAAt(3, 3))
+}
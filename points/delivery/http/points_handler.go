@@ -0,0 +1,152 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/middleware"
+	"github.com/models"
+	"github.com/points"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// pointsHandler  represent the httphandler for the points ledger
+type pointsHandler struct {
+	PointsUsecase points.Usecase
+}
+
+// NewpointsHandler will initialize the users/:id/points resources endpoint
+func NewpointsHandler(e *echo.Echo, us points.Usecase, mw *middleware.GoMiddleware) {
+	handler := &pointsHandler{
+		PointsUsecase: us,
+	}
+	adminOnly := mw.Authorize(middleware.Policy{Roles: []string{models.RoleAdmin}})
+	ownerOrAdmin := mw.Authorize(middleware.Policy{
+		Roles:      []string{models.RoleUser, models.RoleAdmin},
+		OwnerParam: "id",
+	})
+	e.GET("/users/:id/points/history", handler.FetchHistory, mw.Authenticate, ownerOrAdmin)
+	e.POST("/users/:id/points/redeem", handler.Redeem, mw.Authenticate, ownerOrAdmin)
+	e.POST("/users/:id/points/adjust", handler.Adjust, mw.Authenticate, adminOnly)
+	e.POST("/users/:id/points/earn", handler.Earn, mw.Authenticate, adminOnly)
+}
+
+// FetchHistory will list the points ledger entries of the user, the most recent first
+func (a *pointsHandler) FetchHistory(c echo.Context) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	list, nextCursor, err := a.PointsUsecase.Fetch(ctx, c.Param("id"), cursor, int64(num))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, list)
+}
+
+func isRequestValid(m interface{}) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Redeem will spend points of the user by given request body
+func (a *pointsHandler) Redeem(c echo.Context) error {
+	return a.record(c, a.PointsUsecase.Redeem)
+}
+
+// Adjust will add or take points of the user by given request body
+func (a *pointsHandler) Adjust(c echo.Context) error {
+	return a.record(c, a.PointsUsecase.Adjust)
+}
+
+func (a *pointsHandler) record(c echo.Context, apply func(context.Context, *models.NewCommandPoints, string) (*models.PointsEntry, error)) error {
+	var command models.NewCommandPoints
+	err := c.Bind(&command)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+	}
+	command.UserId = c.Param("id")
+
+	if ok, err := isRequestValid(&command); !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := apply(ctx, &command, middleware.GetPrincipal(c).Username)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// Earn will award the points an event of the user is worth, the points are computed from the earning rules
+func (a *pointsHandler) Earn(c echo.Context) error {
+	var event models.PointsEvent
+	err := c.Bind(&event)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+	}
+	event.UserId = c.Param("id")
+
+	if ok, err := isRequestValid(&event); !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := a.PointsUsecase.Earn(ctx, &event, middleware.GetPrincipal(c).Username)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	if res == nil {
+		return c.NoContent(http.StatusNoContent)
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	logrus.Error(err)
+	switch {
+	case errors.Is(err, models.ErrInternalServerError):
+		return http.StatusInternalServerError
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrUnAuthorize):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrConflict), errors.Is(err, models.ErrInsufficientBalance):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrBadParamInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,92 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/middleware/middlewaretest"
+	"github.com/models"
+	pointsHttp "github.com/points/delivery/http"
+	"github.com/points/mocks"
+)
+
+func newServer(mockUCase *mocks.Usecase) *echo.Echo {
+	e := echo.New()
+	pointsHttp.NewpointsHandler(e, mockUCase, middlewaretest.New())
+	return e
+}
+
+func newJSONRequest(target string, body string) *http.Request {
+	req := httptest.NewRequest(echo.POST, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	return req
+}
+
+func TestFetchHistory(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Fetch", mock.Anything, "u1", "", int64(5)).
+		Return([]*models.PointsEntry{{Id: 12, UserId: "u1"}}, "next", nil).Once()
+
+	rec := middlewaretest.Serve(e, httptest.NewRequest(echo.GET, "/users/u1/points/history?num=5", nil), middlewaretest.UserToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "next", rec.Header().Get("X-Cursor"))
+
+	rec = middlewaretest.Serve(e, httptest.NewRequest(echo.GET, "/users/u2/points/history", nil), middlewaretest.UserToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestRedeem(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Redeem", mock.Anything, mock.MatchedBy(func(ar *models.NewCommandPoints) bool {
+		return ar.UserId == "u1" && ar.Points == 50
+	}), "john@mail.com").Return(nil, models.ErrInsufficientBalance).Once()
+
+	rec := middlewaretest.Serve(e, newJSONRequest("/users/u1/points/redeem", `{"points":50,"reason":"voucher"}`), middlewaretest.UserToken)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = middlewaretest.Serve(e, newJSONRequest("/users/u1/points/redeem", `{"points":50}`), middlewaretest.UserToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestAdjustAdminOnly(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Adjust", mock.Anything, mock.Anything, "admin@mail.com").
+		Return(&models.PointsEntry{Id: 13, UserId: "u1", Points: -10}, nil).Once()
+
+	rec := middlewaretest.Serve(e, newJSONRequest("/users/u1/points/adjust", `{"points":-10,"reason":"correction"}`), middlewaretest.UserToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = middlewaretest.Serve(e, newJSONRequest("/users/u1/points/adjust", `{"points":-10,"reason":"correction"}`), middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestEarn(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Earn", mock.Anything, mock.MatchedBy(func(ev *models.PointsEvent) bool {
+		return ev.UserId == "u1" && ev.Event == "purchase" && ev.Amount == 2500000
+	}), "admin@mail.com").Return(&models.PointsEntry{Id: 14, UserId: "u1", Points: 2}, nil).Once()
+	mockUCase.On("Earn", mock.Anything, mock.MatchedBy(func(ev *models.PointsEvent) bool {
+		return ev.Amount == 10
+	}), "admin@mail.com").Return(nil, nil).Once()
+
+	rec := middlewaretest.Serve(e, newJSONRequest("/users/u1/points/earn", `{"event":"purchase","amount":2500000,"reference":"order-1"}`), middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = middlewaretest.Serve(e, newJSONRequest("/users/u1/points/earn", `{"event":"purchase","amount":10}`), middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
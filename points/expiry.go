@@ -0,0 +1,30 @@
+package points
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RunExpiry will expire the points whose expiry date passed every interval until ctx is done.
+// A failed run is logged and retried on the next tick.
+func RunExpiry(ctx context.Context, uc Usecase, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		expired, err := uc.Expire(ctx, time.Now())
+		if err != nil {
+			logrus.WithError(err).Error("expire points")
+		} else if expired > 0 {
+			logrus.WithField("entries", expired).Info("expired points")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
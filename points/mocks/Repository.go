@@ -0,0 +1,162 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import time "time"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// Fetch provides a mock function with given fields: ctx, userId, cursor, num
+func (_m *Repository) Fetch(ctx context.Context, userId string, cursor string, num int64) ([]*models.PointsEntry, string, error) {
+	ret := _m.Called(ctx, userId, cursor, num)
+
+	var r0 []*models.PointsEntry
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) []*models.PointsEntry); ok {
+		r0 = rf(ctx, userId, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PointsEntry)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) string); ok {
+		r1 = rf(ctx, userId, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, int64) error); ok {
+		r2 = rf(ctx, userId, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchExpiredLots provides a mock function with given fields: ctx, userId, now
+func (_m *Repository) FetchExpiredLots(ctx context.Context, userId string, now time.Time) ([]*models.PointsEntry, error) {
+	ret := _m.Called(ctx, userId, now)
+
+	var r0 []*models.PointsEntry
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) []*models.PointsEntry); ok {
+		r0 = rf(ctx, userId, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PointsEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, userId, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchOpenLots provides a mock function with given fields: ctx, userId
+func (_m *Repository) FetchOpenLots(ctx context.Context, userId string) ([]*models.PointsEntry, error) {
+	ret := _m.Called(ctx, userId)
+
+	var r0 []*models.PointsEntry
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*models.PointsEntry); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PointsEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FetchUsersWithExpiredLots provides a mock function with given fields: ctx, now, num
+func (_m *Repository) FetchUsersWithExpiredLots(ctx context.Context, now time.Time, num int64) ([]string, error) {
+	ret := _m.Called(ctx, now, num)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int64) []string); ok {
+		r0 = rf(ctx, now, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int64) error); ok {
+		r1 = rf(ctx, now, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByReference provides a mock function with given fields: ctx, userId, entryType, reference
+func (_m *Repository) GetByReference(ctx context.Context, userId string, entryType string, reference string) (*models.PointsEntry, error) {
+	ret := _m.Called(ctx, userId, entryType, reference)
+
+	var r0 *models.PointsEntry
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *models.PointsEntry); ok {
+		r0 = rf(ctx, userId, entryType, reference)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PointsEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, userId, entryType, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, e
+func (_m *Repository) Insert(ctx context.Context, e *models.PointsEntry) error {
+	ret := _m.Called(ctx, e)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PointsEntry) error); ok {
+		r0 = rf(ctx, e)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRemaining provides a mock function with given fields: ctx, id, remaining
+func (_m *Repository) UpdateRemaining(ctx context.Context, id int64, remaining int64) error {
+	ret := _m.Called(ctx, id, remaining)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, id, remaining)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,132 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import time "time"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Adjust provides a mock function with given fields: ctx, ar, user
+func (_m *Usecase) Adjust(ctx context.Context, ar *models.NewCommandPoints, user string) (*models.PointsEntry, error) {
+	ret := _m.Called(ctx, ar, user)
+
+	var r0 *models.PointsEntry
+	if rf, ok := ret.Get(0).(func(context.Context, *models.NewCommandPoints, string) *models.PointsEntry); ok {
+		r0 = rf(ctx, ar, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PointsEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.NewCommandPoints, string) error); ok {
+		r1 = rf(ctx, ar, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Earn provides a mock function with given fields: ctx, ev, user
+func (_m *Usecase) Earn(ctx context.Context, ev *models.PointsEvent, user string) (*models.PointsEntry, error) {
+	ret := _m.Called(ctx, ev, user)
+
+	var r0 *models.PointsEntry
+	if rf, ok := ret.Get(0).(func(context.Context, *models.PointsEvent, string) *models.PointsEntry); ok {
+		r0 = rf(ctx, ev, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PointsEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.PointsEvent, string) error); ok {
+		r1 = rf(ctx, ev, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Expire provides a mock function with given fields: ctx, now
+func (_m *Usecase) Expire(ctx context.Context, now time.Time) (int, error) {
+	ret := _m.Called(ctx, now)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fetch provides a mock function with given fields: ctx, userId, cursor, num
+func (_m *Usecase) Fetch(ctx context.Context, userId string, cursor string, num int64) ([]*models.PointsEntry, string, error) {
+	ret := _m.Called(ctx, userId, cursor, num)
+
+	var r0 []*models.PointsEntry
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64) []*models.PointsEntry); ok {
+		r0 = rf(ctx, userId, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.PointsEntry)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64) string); ok {
+		r1 = rf(ctx, userId, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, int64) error); ok {
+		r2 = rf(ctx, userId, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Redeem provides a mock function with given fields: ctx, ar, user
+func (_m *Usecase) Redeem(ctx context.Context, ar *models.NewCommandPoints, user string) (*models.PointsEntry, error) {
+	ret := _m.Called(ctx, ar, user)
+
+	var r0 *models.PointsEntry
+	if rf, ok := ret.Get(0).(func(context.Context, *models.NewCommandPoints, string) *models.PointsEntry); ok {
+		r0 = rf(ctx, ar, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PointsEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.NewCommandPoints, string) error); ok {
+		r1 = rf(ctx, ar, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
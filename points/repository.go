@@ -0,0 +1,20 @@
+package points
+
+import (
+	"context"
+	"time"
+
+	"github.com/models"
+)
+
+// Repository represent the points ledger's repository contract.
+// Entries are only ever inserted, UpdateRemaining is the only change made to an existing entry.
+type Repository interface {
+	Fetch(ctx context.Context, userId string, cursor string, num int64) (res []*models.PointsEntry, nextCursor string, err error)
+	GetByReference(ctx context.Context, userId string, entryType string, reference string) (*models.PointsEntry, error)
+	Insert(ctx context.Context, e *models.PointsEntry) error
+	FetchOpenLots(ctx context.Context, userId string) ([]*models.PointsEntry, error)
+	FetchExpiredLots(ctx context.Context, userId string, now time.Time) ([]*models.PointsEntry, error)
+	FetchUsersWithExpiredLots(ctx context.Context, now time.Time, num int64) ([]string, error)
+	UpdateRemaining(ctx context.Context, id int64, remaining int64) error
+}
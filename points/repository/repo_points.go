@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/models"
+	"github.com/points"
+	"github.com/transaction"
+)
+
+// entryColumns lists the columns in the order they are scanned by fetch
+const entryColumns = `id, user_id, type, points, remaining, balance_after, reason, reference, expires_at, created_by, created_date`
+
+type pointsRepository struct {
+	Conn *sql.DB
+}
+
+// NewpointsRepository will create an object that represent the points.Repository interface
+func NewpointsRepository(Conn *sql.DB) points.Repository {
+	return &pointsRepository{Conn}
+}
+
+// conn will return the transaction of the unit of work ctx belongs to, or the connection pool
+func (m *pointsRepository) conn(ctx context.Context) transaction.DBTX {
+	return transaction.Conn(ctx, m.Conn)
+}
+
+func (m *pointsRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*models.PointsEntry, error) {
+	rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}()
+
+	result := make([]*models.PointsEntry, 0)
+	for rows.Next() {
+		t := new(models.PointsEntry)
+		var expiresAt sql.NullTime
+		err = rows.Scan(
+			&t.Id,
+			&t.UserId,
+			&t.Type,
+			&t.Points,
+			&t.Remaining,
+			&t.BalanceAfter,
+			&t.Reason,
+			&t.Reference,
+			&expiresAt,
+			&t.CreatedBy,
+			&t.CreatedDate,
+		)
+
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		if expiresAt.Valid {
+			t.ExpiresAt = &expiresAt.Time
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+// Fetch will list the entries of the user, the most recent first
+func (m *pointsRepository) Fetch(ctx context.Context, userId string, cursor string, num int64) ([]*models.PointsEntry, string, error) {
+	query := `SELECT ` + entryColumns + ` FROM points_entries WHERE user_id = ? AND id < ? ORDER BY id DESC LIMIT ?`
+
+	before := int64(math.MaxInt64)
+	if cursor != "" {
+		decodedCursor, err := DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", models.ErrBadParamInput
+		}
+		before = decodedCursor
+	}
+
+	res, err := m.fetch(ctx, query, userId, before, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if len(res) == int(num) {
+		nextCursor = EncodeCursor(res[len(res)-1].Id)
+	}
+
+	return res, nextCursor, nil
+}
+
+func (m *pointsRepository) GetByReference(ctx context.Context, userId string, entryType string, reference string) (*models.PointsEntry, error) {
+	query := `SELECT ` + entryColumns + ` FROM points_entries WHERE user_id = ? AND type = ? AND reference = ? LIMIT 1`
+
+	list, err := m.fetch(ctx, query, userId, entryType, reference)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, models.ErrNotFound
+	}
+	return list[0], nil
+}
+
+func (m *pointsRepository) Insert(ctx context.Context, a *models.PointsEntry) error {
+	query := `INSERT points_entries SET user_id=? , type=? , points=? , remaining=? , balance_after=? , reason=? , reference=? , expires_at=? , created_by=? , created_date=?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	a.CreatedDate = time.Now()
+	res, err := stmt.ExecContext(ctx, a.UserId, a.Type, a.Points, a.Remaining, a.BalanceAfter, a.Reason, a.Reference,
+		a.ExpiresAt, a.CreatedBy, a.CreatedDate)
+	if err != nil {
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	a.Id = lastID
+	return nil
+}
+
+// FetchOpenLots will list the positive entries of the user that still have points left,
+// in the order they are consumed: the soonest expiry first and the entries that never expire last
+func (m *pointsRepository) FetchOpenLots(ctx context.Context, userId string) ([]*models.PointsEntry, error) {
+	query := `SELECT ` + entryColumns + ` FROM points_entries WHERE user_id = ? AND remaining > 0
+	ORDER BY expires_at IS NULL, expires_at, id`
+	return m.fetch(ctx, query, userId)
+}
+
+// FetchExpiredLots will list the positive entries of the user that still have points left after their expiry date
+func (m *pointsRepository) FetchExpiredLots(ctx context.Context, userId string, now time.Time) ([]*models.PointsEntry, error) {
+	query := `SELECT ` + entryColumns + ` FROM points_entries WHERE user_id = ? AND remaining > 0 AND expires_at <= ? ORDER BY id`
+	return m.fetch(ctx, query, userId, now)
+}
+
+// FetchUsersWithExpiredLots will list up to num users having points left after their expiry date
+func (m *pointsRepository) FetchUsersWithExpiredLots(ctx context.Context, now time.Time, num int64) ([]string, error) {
+	query := `SELECT DISTINCT user_id FROM points_entries WHERE remaining > 0 AND expires_at <= ? LIMIT ?`
+	rows, err := m.conn(ctx).QueryContext(ctx, query, now, num)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			logrus.Error(err)
+		}
+	}()
+
+	result := make([]string, 0)
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, userId)
+	}
+	return result, rows.Err()
+}
+
+func (m *pointsRepository) UpdateRemaining(ctx context.Context, id int64, remaining int64) error {
+	query := `UPDATE points_entries SET remaining=? WHERE id = ?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, remaining, id)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
+// DecodeCursor will decode the id of the last entry of a page
+func DecodeCursor(cursor string) (int64, error) {
+	byt, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(string(byt), 10, 64)
+}
+
+// EncodeCursor will encode the id of the last entry of a page
+func EncodeCursor(id int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
@@ -0,0 +1,160 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/models"
+	pointsRepo "github.com/points/repository"
+)
+
+var entryColumns = []string{"id", "user_id", "type", "points", "remaining", "balance_after", "reason", "reference",
+	"expires_at", "created_by", "created_date"}
+
+func TestFetch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	now := time.Now()
+	rows := sqlmock.NewRows(entryColumns).
+		AddRow(12, "u1", "redeem", -50, 0, 50, "voucher", "order-1", nil, "john", now).
+		AddRow(11, "u1", "earn", 100, 50, 100, "registration", "u1", now.AddDate(1, 0, 0), "admin", now)
+
+	query := "SELECT .+ FROM points_entries WHERE user_id = \\? AND id < \\? ORDER BY id DESC LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs("u1", int64(20), int64(2)).WillReturnRows(rows)
+
+	a := pointsRepo.NewpointsRepository(db)
+	list, nextCursor, err := a.Fetch(context.TODO(), "u1", pointsRepo.EncodeCursor(20), 2)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Nil(t, list[0].ExpiresAt)
+	assert.NotNil(t, list[1].ExpiresAt)
+	assert.Equal(t, int64(-50), list[0].Points)
+	assert.Equal(t, pointsRepo.EncodeCursor(11), nextCursor)
+
+	_, _, err = a.Fetch(context.TODO(), "u1", "not-a-cursor", 2)
+	assert.Equal(t, models.ErrBadParamInput, err)
+}
+
+func TestGetByReference(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "SELECT .+ FROM points_entries WHERE user_id = \\? AND type = \\? AND reference = \\?"
+	mock.ExpectQuery(query).WithArgs("u1", "earn", "order-1").WillReturnRows(sqlmock.NewRows(entryColumns))
+
+	a := pointsRepo.NewpointsRepository(db)
+	_, err = a.GetByReference(context.TODO(), "u1", models.PointsEarn, "order-1")
+	assert.Equal(t, models.ErrNotFound, err)
+}
+
+func TestInsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	expiresAt := time.Now().AddDate(1, 0, 0)
+	entry := &models.PointsEntry{UserId: "u1", Type: models.PointsEarn, Points: 100, Remaining: 100, BalanceAfter: 100,
+		Reason: "registration", Reference: "u1", ExpiresAt: &expiresAt, CreatedBy: "admin"}
+	query := "INSERT points_entries SET user_id=\\? , type=\\? , points=\\? , remaining=\\? , balance_after=\\? , reason=\\? , reference=\\? , expires_at=\\? , created_by=\\? , created_date=\\?"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs("u1", "earn", int64(100), int64(100), int64(100), "registration", "u1", expiresAt, "admin", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(11, 1))
+
+	a := pointsRepo.NewpointsRepository(db)
+	require.NoError(t, a.Insert(context.TODO(), entry))
+	assert.Equal(t, int64(11), entry.Id)
+}
+
+func TestFetchOpenLots(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	now := time.Now()
+	rows := sqlmock.NewRows(entryColumns).
+		AddRow(11, "u1", "earn", 100, 40, 100, "registration", "u1", now.AddDate(0, 1, 0), "admin", now).
+		AddRow(3, "u1", "adjust", 20, 20, 20, "opening balance", "opening-balance", nil, "migration", now)
+	query := "SELECT .+ FROM points_entries WHERE user_id = \\? AND remaining > 0\\s+ORDER BY expires_at IS NULL, expires_at, id"
+	mock.ExpectQuery(query).WithArgs("u1").WillReturnRows(rows)
+
+	a := pointsRepo.NewpointsRepository(db)
+	list, err := a.FetchOpenLots(context.TODO(), "u1")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, int64(40), list[0].Remaining)
+}
+
+func TestFetchUsersWithExpiredLots(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	now := time.Now()
+	query := "SELECT DISTINCT user_id FROM points_entries WHERE remaining > 0 AND expires_at <= \\? LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(now, int64(100)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("u1").AddRow("u2"))
+
+	a := pointsRepo.NewpointsRepository(db)
+	users, err := a.FetchUsersWithExpiredLots(context.TODO(), now, 100)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2"}, users)
+}
+
+func TestUpdateRemaining(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "UPDATE points_entries SET remaining=\\? WHERE id = \\?"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(int64(0), int64(11)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs(int64(0), int64(99)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := pointsRepo.NewpointsRepository(db)
+	require.NoError(t, a.UpdateRemaining(context.TODO(), 11, 0))
+	assert.Equal(t, models.ErrNotFound, a.UpdateRemaining(context.TODO(), 99, 0))
+}
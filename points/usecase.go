@@ -0,0 +1,17 @@
+package points
+
+import (
+	"context"
+	"time"
+
+	"github.com/models"
+)
+
+// Usecase represent the points ledger's usecases
+type Usecase interface {
+	Earn(ctx context.Context, ev *models.PointsEvent, user string) (*models.PointsEntry, error)
+	Redeem(ctx context.Context, ar *models.NewCommandPoints, user string) (*models.PointsEntry, error)
+	Adjust(ctx context.Context, ar *models.NewCommandPoints, user string) (*models.PointsEntry, error)
+	Fetch(ctx context.Context, userId string, cursor string, num int64) ([]*models.PointsEntry, string, error)
+	Expire(ctx context.Context, now time.Time) (int, error)
+}
@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/models"
+	"github.com/points"
+	"github.com/transaction"
+	"github.com/user"
+)
+
+const (
+	// systemUser is recorded as the author of the entries made by the expiry job
+	systemUser = "system"
+
+	// expiryBatch is the number of users the expiry job loads at once
+	expiryBatch = 100
+)
+
+type pointsUsecase struct {
+	pointsRepo     points.Repository
+	userRepo       user.Repository
+	txManager      transaction.Manager
+	rules          map[string]models.EarningRule
+	expiryPeriod   time.Duration
+	contextTimeout time.Duration
+}
+
+// NewpointsUsecase will create new an pointsUsecase object representation of points.Usecase interface.
+// The earned points expire after expiryPeriod, they never expire when it is zero.
+func NewpointsUsecase(p points.Repository, ur user.Repository, txManager transaction.Manager, rules []models.EarningRule, expiryPeriod time.Duration, timeout time.Duration) points.Usecase {
+	byEvent := make(map[string]models.EarningRule, len(rules))
+	for _, rule := range rules {
+		byEvent[rule.Event] = rule
+	}
+	return &pointsUsecase{
+		pointsRepo:     p,
+		userRepo:       ur,
+		txManager:      txManager,
+		rules:          byEvent,
+		expiryPeriod:   expiryPeriod,
+		contextTimeout: timeout,
+	}
+}
+
+// Earn will award the points the event is worth according to the earning rules.
+// Events without a rule fail with models.ErrBadParamInput, nothing is recorded when the event is worth no points.
+func (m pointsUsecase) Earn(c context.Context, ev *models.PointsEvent, user string) (*models.PointsEntry, error) {
+	rule, ok := m.rules[ev.Event]
+	if !ok || ev.Amount < 0 {
+		return nil, models.ErrBadParamInput
+	}
+	earned := rule.Points
+	if rule.PerAmount > 0 {
+		earned = ev.Amount / rule.PerAmount * rule.Points
+	}
+	if earned <= 0 {
+		return nil, nil
+	}
+
+	return m.record(c, &models.PointsEntry{
+		UserId:    ev.UserId,
+		Type:      models.PointsEarn,
+		Points:    earned,
+		Reason:    ev.Event,
+		Reference: ev.Reference,
+		CreatedBy: user,
+	})
+}
+
+// Redeem will take the given number of points from the user
+func (m pointsUsecase) Redeem(c context.Context, ar *models.NewCommandPoints, user string) (*models.PointsEntry, error) {
+	if ar.Points <= 0 {
+		return nil, models.ErrBadParamInput
+	}
+	return m.record(c, &models.PointsEntry{
+		UserId:    ar.UserId,
+		Type:      models.PointsRedeem,
+		Points:    -ar.Points,
+		Reason:    ar.Reason,
+		Reference: ar.Reference,
+		CreatedBy: user,
+	})
+}
+
+// Adjust will add the given number of points to the user, or take them when it is negative
+func (m pointsUsecase) Adjust(c context.Context, ar *models.NewCommandPoints, user string) (*models.PointsEntry, error) {
+	if ar.Points == 0 {
+		return nil, models.ErrBadParamInput
+	}
+	return m.record(c, &models.PointsEntry{
+		UserId:    ar.UserId,
+		Type:      models.PointsAdjust,
+		Points:    ar.Points,
+		Reason:    ar.Reason,
+		Reference: ar.Reference,
+		CreatedBy: user,
+	})
+}
+
+// record will add the entry to the ledger and apply it to the points of the user in one transaction.
+// Recording the same type and reference again returns the first entry, or models.ErrConflict when the points differ.
+// Entries taking the points below zero fail with models.ErrInsufficientBalance.
+func (m pointsUsecase) record(c context.Context, entry *models.PointsEntry) (*models.PointsEntry, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	var result *models.PointsEntry
+	err := m.txManager.Do(ctx, func(ctx context.Context) error {
+		// the user row stays locked until the end of the transaction, the entries of a user are serialized
+		balance, err := m.userRepo.GetPointsForUpdate(ctx, entry.UserId)
+		if err != nil {
+			return err
+		}
+
+		if entry.Reference != "" {
+			existed, err := m.pointsRepo.GetByReference(ctx, entry.UserId, entry.Type, entry.Reference)
+			if err == nil {
+				if existed.Points != entry.Points {
+					return models.ErrConflict
+				}
+				result = existed
+				return nil
+			}
+			if !errors.Is(err, models.ErrNotFound) {
+				return err
+			}
+		}
+
+		entry.BalanceAfter = balance + entry.Points
+		if entry.BalanceAfter < 0 {
+			return models.ErrInsufficientBalance
+		}
+		if entry.Points > 0 {
+			entry.Remaining = entry.Points
+			if m.expiryPeriod > 0 {
+				expiresAt := time.Now().Add(m.expiryPeriod)
+				entry.ExpiresAt = &expiresAt
+			}
+		} else if err := m.consume(ctx, entry.UserId, -entry.Points); err != nil {
+			return err
+		}
+
+		if err := m.userRepo.UpdatePoints(ctx, entry.UserId, entry.BalanceAfter); err != nil {
+			return err
+		}
+		if err := m.pointsRepo.Insert(ctx, entry); err != nil {
+			return err
+		}
+		result = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// consume will take the points from the open entries of the user, the soonest expiry first
+func (m pointsUsecase) consume(ctx context.Context, userId string, amount int64) error {
+	lots, err := m.pointsRepo.FetchOpenLots(ctx, userId)
+	if err != nil {
+		return err
+	}
+	for _, lot := range lots {
+		if amount == 0 {
+			break
+		}
+		taken := lot.Remaining
+		if taken > amount {
+			taken = amount
+		}
+		if err := m.pointsRepo.UpdateRemaining(ctx, lot.Id, lot.Remaining-taken); err != nil {
+			return err
+		}
+		amount -= taken
+	}
+	if amount > 0 {
+		logrus.WithFields(logrus.Fields{
+			"user_id": userId,
+			"missing": amount,
+		}).Warn("the points of the user are not covered by its ledger entries")
+	}
+	return nil
+}
+
+// Fetch will list the entries of the points ledger of the user, the most recent first
+func (m pointsUsecase) Fetch(c context.Context, userId string, cursor string, num int64) ([]*models.PointsEntry, string, error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	return m.pointsRepo.Fetch(ctx, userId, cursor, num)
+}
+
+// Expire will record an expire entry for every entry whose points were not used before now
+// and return the number of entries expired. Every user is handled in its own transaction.
+func (m pointsUsecase) Expire(c context.Context, now time.Time) (int, error) {
+	expired := 0
+	for {
+		ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+		users, err := m.pointsRepo.FetchUsersWithExpiredLots(ctx, now, expiryBatch)
+		cancel()
+		if err != nil {
+			return expired, err
+		}
+
+		for _, userId := range users {
+			n, err := m.expireUser(c, userId, now)
+			if err != nil {
+				return expired, err
+			}
+			expired += n
+		}
+		if len(users) < expiryBatch {
+			return expired, nil
+		}
+	}
+}
+
+func (m pointsUsecase) expireUser(c context.Context, userId string, now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	expired := 0
+	err := m.txManager.Do(ctx, func(ctx context.Context) error {
+		expired = 0
+		balance, err := m.userRepo.GetPointsForUpdate(ctx, userId)
+		if err != nil {
+			return err
+		}
+		lots, err := m.pointsRepo.FetchExpiredLots(ctx, userId, now)
+		if err != nil {
+			return err
+		}
+		if len(lots) == 0 {
+			return nil
+		}
+
+		for _, lot := range lots {
+			balance -= lot.Remaining
+			entry := &models.PointsEntry{
+				UserId:       userId,
+				Type:         models.PointsExpire,
+				Points:       -lot.Remaining,
+				BalanceAfter: balance,
+				Reason:       "points expired",
+				Reference:    strconv.FormatInt(lot.Id, 10),
+				CreatedBy:    systemUser,
+			}
+			if err := m.pointsRepo.UpdateRemaining(ctx, lot.Id, 0); err != nil {
+				return err
+			}
+			if err := m.pointsRepo.Insert(ctx, entry); err != nil {
+				return err
+			}
+			expired++
+		}
+		return m.userRepo.UpdatePoints(ctx, userId, balance)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return expired, nil
+}
@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/models"
+	"github.com/points/mocks"
+	ucase "github.com/points/usecase"
+	_txMock "github.com/transaction/mocks"
+	_userMock "github.com/user/mocks"
+)
+
+var rules = []models.EarningRule{
+	{Event: "registration", Points: 100},
+	{Event: "purchase", Points: 1, PerAmount: 1000000},
+}
+
+func inTransaction() *_txMock.Manager {
+	mockTx := new(_txMock.Manager)
+	mockTx.On("Do", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+	return mockTx
+}
+
+func TestEarn(t *testing.T) {
+	t.Run("per-amount", func(t *testing.T) {
+		mockPointsRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetPointsForUpdate", mock.Anything, "u1").Return(int64(10), nil).Once()
+		mockPointsRepo.On("GetByReference", mock.Anything, "u1", models.PointsEarn, "order-1").Return(nil, models.ErrNotFound).Once()
+		mockUserRepo.On("UpdatePoints", mock.Anything, "u1", int64(35)).Return(nil).Once()
+		mockPointsRepo.On("Insert", mock.Anything, mock.MatchedBy(func(e *models.PointsEntry) bool {
+			return e.Points == 25 && e.Remaining == 25 && e.BalanceAfter == 35 && e.ExpiresAt != nil && e.Reason == "purchase"
+		})).Return(nil).Once()
+
+		u := ucase.NewpointsUsecase(mockPointsRepo, mockUserRepo, inTransaction(), rules, time.Hour, time.Second*2)
+		res, err := u.Earn(context.TODO(), &models.PointsEvent{UserId: "u1", Event: "purchase", Amount: 25500000, Reference: "order-1"}, "admin")
+		require.NoError(t, err)
+		assert.Equal(t, int64(35), res.BalanceAfter)
+		mockPointsRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("replayed", func(t *testing.T) {
+		mockPointsRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		existed := &models.PointsEntry{Id: 11, UserId: "u1", Type: models.PointsEarn, Points: 100}
+		mockUserRepo.On("GetPointsForUpdate", mock.Anything, "u1").Return(int64(100), nil).Once()
+		mockPointsRepo.On("GetByReference", mock.Anything, "u1", models.PointsEarn, "u1").Return(existed, nil).Once()
+
+		u := ucase.NewpointsUsecase(mockPointsRepo, mockUserRepo, inTransaction(), rules, time.Hour, time.Second*2)
+		res, err := u.Earn(context.TODO(), &models.PointsEvent{UserId: "u1", Event: "registration", Reference: "u1"}, "admin")
+		require.NoError(t, err)
+		assert.Equal(t, existed, res)
+		mockUserRepo.AssertNotCalled(t, "UpdatePoints", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("worth-nothing", func(t *testing.T) {
+		mockPointsRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+
+		u := ucase.NewpointsUsecase(mockPointsRepo, mockUserRepo, inTransaction(), rules, time.Hour, time.Second*2)
+		res, err := u.Earn(context.TODO(), &models.PointsEvent{UserId: "u1", Event: "purchase", Amount: 999999}, "admin")
+		require.NoError(t, err)
+		assert.Nil(t, res)
+		mockUserRepo.AssertNotCalled(t, "GetPointsForUpdate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown-event", func(t *testing.T) {
+		u := ucase.NewpointsUsecase(new(mocks.Repository), new(_userMock.Repository), inTransaction(), rules, time.Hour, time.Second*2)
+		_, err := u.Earn(context.TODO(), &models.PointsEvent{UserId: "u1", Event: "birthday"}, "admin")
+		assert.Equal(t, models.ErrBadParamInput, err)
+	})
+}
+
+func TestRedeem(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockPointsRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetPointsForUpdate", mock.Anything, "u1").Return(int64(120), nil).Once()
+		mockPointsRepo.On("GetByReference", mock.Anything, "u1", models.PointsRedeem, "voucher-1").Return(nil, models.ErrNotFound).Once()
+		mockPointsRepo.On("FetchOpenLots", mock.Anything, "u1").Return([]*models.PointsEntry{
+			{Id: 3, Remaining: 30},
+			{Id: 7, Remaining: 90},
+		}, nil).Once()
+		mockPointsRepo.On("UpdateRemaining", mock.Anything, int64(3), int64(0)).Return(nil).Once()
+		mockPointsRepo.On("UpdateRemaining", mock.Anything, int64(7), int64(70)).Return(nil).Once()
+		mockUserRepo.On("UpdatePoints", mock.Anything, "u1", int64(70)).Return(nil).Once()
+		mockPointsRepo.On("Insert", mock.Anything, mock.MatchedBy(func(e *models.PointsEntry) bool {
+			return e.Type == models.PointsRedeem && e.Points == -50 && e.Remaining == 0 && e.ExpiresAt == nil
+		})).Return(nil).Once()
+
+		u := ucase.NewpointsUsecase(mockPointsRepo, mockUserRepo, inTransaction(), rules, time.Hour, time.Second*2)
+		res, err := u.Redeem(context.TODO(), &models.NewCommandPoints{UserId: "u1", Points: 50, Reason: "voucher", Reference: "voucher-1"}, "john")
+		require.NoError(t, err)
+		assert.Equal(t, int64(70), res.BalanceAfter)
+		mockPointsRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("insufficient", func(t *testing.T) {
+		mockPointsRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetPointsForUpdate", mock.Anything, "u1").Return(int64(20), nil).Once()
+
+		u := ucase.NewpointsUsecase(mockPointsRepo, mockUserRepo, inTransaction(), rules, time.Hour, time.Second*2)
+		_, err := u.Redeem(context.TODO(), &models.NewCommandPoints{UserId: "u1", Points: 50, Reason: "voucher"}, "john")
+		assert.Equal(t, models.ErrInsufficientBalance, err)
+		mockPointsRepo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("not-positive", func(t *testing.T) {
+		u := ucase.NewpointsUsecase(new(mocks.Repository), new(_userMock.Repository), inTransaction(), rules, time.Hour, time.Second*2)
+		_, err := u.Redeem(context.TODO(), &models.NewCommandPoints{UserId: "u1", Points: -50, Reason: "voucher"}, "john")
+		assert.Equal(t, models.ErrBadParamInput, err)
+	})
+}
+
+func TestExpire(t *testing.T) {
+	now := time.Now()
+	mockPointsRepo := new(mocks.Repository)
+	mockUserRepo := new(_userMock.Repository)
+	mockPointsRepo.On("FetchUsersWithExpiredLots", mock.Anything, now, int64(100)).Return([]string{"u1"}, nil).Once()
+	mockUserRepo.On("GetPointsForUpdate", mock.Anything, "u1").Return(int64(150), nil).Once()
+	mockPointsRepo.On("FetchExpiredLots", mock.Anything, "u1", now).Return([]*models.PointsEntry{
+		{Id: 3, Remaining: 30},
+		{Id: 7, Remaining: 100},
+	}, nil).Once()
+	mockPointsRepo.On("UpdateRemaining", mock.Anything, int64(3), int64(0)).Return(nil).Once()
+	mockPointsRepo.On("UpdateRemaining", mock.Anything, int64(7), int64(0)).Return(nil).Once()
+	mockPointsRepo.On("Insert", mock.Anything, mock.MatchedBy(func(e *models.PointsEntry) bool {
+		return e.Type == models.PointsExpire && e.Points == -30 && e.BalanceAfter == 120 && e.Reference == "3"
+	})).Return(nil).Once()
+	mockPointsRepo.On("Insert", mock.Anything, mock.MatchedBy(func(e *models.PointsEntry) bool {
+		return e.Type == models.PointsExpire && e.Points == -100 && e.BalanceAfter == 20 && e.Reference == "7"
+	})).Return(nil).Once()
+	mockUserRepo.On("UpdatePoints", mock.Anything, "u1", int64(20)).Return(nil).Once()
+
+	u := ucase.NewpointsUsecase(mockPointsRepo, mockUserRepo, inTransaction(), rules, time.Hour, time.Second*2)
+	expired, err := u.Expire(context.TODO(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, expired)
+	mockPointsRepo.AssertExpectations(t)
+	mockUserRepo.AssertExpectations(t)
+}
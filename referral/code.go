@@ -0,0 +1,65 @@
+package referral
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/models"
+	"github.com/user"
+)
+
+const (
+	// codeAlphabet leaves out the characters that are easily mistaken for one another (0/O, 1/I/L)
+	codeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+	// codeLength gives about 10^12 codes
+	codeLength = 8
+	// maxCodeAttempts bounds the search for a code that is not taken yet
+	maxCodeAttempts = 5
+)
+
+// NewCode will return a random referral code that is easy to read out and type
+func NewCode() (string, error) {
+	// bytes above the last multiple of the alphabet size are dropped so every character is equally likely
+	limit := byte(256 - 256%len(codeAlphabet))
+	code := make([]byte, 0, codeLength)
+	buf := make([]byte, codeLength)
+	for len(code) < codeLength {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if b < limit && len(code) < codeLength {
+				code = append(code, codeAlphabet[int(b)%len(codeAlphabet)])
+			}
+		}
+	}
+	return string(code), nil
+}
+
+// NormalizeCode will turn a code typed by a user into the stored form, ignoring the case, spaces and dashes
+func NormalizeCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.NewReplacer(" ", "", "-", "").Replace(code)
+}
+
+// GenerateCode will return a referral code that no user owns yet, deleted users keep theirs under the unique key.
+// The unique key on users.referral_code still guards against two registrations picking the same code at once.
+func GenerateCode(ctx context.Context, ur user.Repository) (string, error) {
+	for i := 0; i < maxCodeAttempts; i++ {
+		code, err := NewCode()
+		if err != nil {
+			return "", err
+		}
+		_, err = ur.GetByReferralCodeIncludeDeleted(ctx, code)
+		if errors.Is(err, models.ErrNotFound) {
+			return code, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no free referral code after %d attempts", maxCodeAttempts)
+}
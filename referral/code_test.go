@@ -0,0 +1,43 @@
+package referral_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/models"
+	"github.com/referral"
+	_userMock "github.com/user/mocks"
+)
+
+func TestNewCode(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		code, err := referral.NewCode()
+		require.NoError(t, err)
+		require.Len(t, code, 8)
+		assert.False(t, strings.ContainsAny(code, "01ILO"), code)
+		assert.Equal(t, code, referral.NormalizeCode(strings.ToLower(code[:4])+"-"+code[4:]))
+		seen[code] = true
+	}
+	assert.Len(t, seen, 1000)
+}
+
+func TestGenerateCodeSkipsDeletedOwners(t *testing.T) {
+	mockUserRepo := new(_userMock.Repository)
+	var taken string
+	mockUserRepo.On("GetByReferralCodeIncludeDeleted", mock.Anything, mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { taken = args.String(1) }).
+		Return(&models.User{Id: "u0", IsDeleted: 1}, nil).Once()
+	mockUserRepo.On("GetByReferralCodeIncludeDeleted", mock.Anything, mock.AnythingOfType("string")).
+		Return(nil, models.ErrNotFound).Once()
+
+	code, err := referral.GenerateCode(context.TODO(), mockUserRepo)
+	require.NoError(t, err)
+	assert.NotEqual(t, taken, code)
+	mockUserRepo.AssertExpectations(t)
+}
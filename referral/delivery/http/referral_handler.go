@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/middleware"
+	"github.com/models"
+	"github.com/referral"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// referralHandler  represent the httphandler for the referrals
+type referralHandler struct {
+	ReferralUsecase referral.Usecase
+}
+
+// NewreferralHandler will initialize the users/:id/referrals resources endpoint
+func NewreferralHandler(e *echo.Echo, us referral.Usecase, mw *middleware.GoMiddleware) {
+	handler := &referralHandler{
+		ReferralUsecase: us,
+	}
+	adminOnly := mw.Authorize(middleware.Policy{Roles: []string{models.RoleAdmin}})
+	ownerOrAdmin := mw.Authorize(middleware.Policy{
+		Roles:      []string{models.RoleUser, models.RoleAdmin},
+		OwnerParam: "id",
+	})
+	e.GET("/users/:id/referrals", handler.GetStats, mw.Authenticate, ownerOrAdmin)
+	e.POST("/users/:id/referrer/reward", handler.Reward, mw.Authenticate, adminOnly)
+}
+
+// GetStats will return the referral code of the user and how many users it referred
+func (a *referralHandler) GetStats(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := a.ReferralUsecase.GetStats(ctx, c.Param("id"))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// Reward will award the referral points of a user that was verified out of band
+func (a *referralHandler) Reward(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := a.ReferralUsecase.Reward(ctx, c.Param("id"))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	logrus.Error(err)
+	switch {
+	case errors.Is(err, models.ErrInternalServerError):
+		return http.StatusInternalServerError
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrUnAuthorize):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrBadParamInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
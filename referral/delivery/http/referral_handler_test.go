@@ -0,0 +1,68 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/middleware/middlewaretest"
+	"github.com/models"
+	referralHttp "github.com/referral/delivery/http"
+	"github.com/referral/mocks"
+)
+
+func newServer(mockUCase *mocks.Usecase) *echo.Echo {
+	e := echo.New()
+	referralHttp.NewreferralHandler(e, mockUCase, middlewaretest.New())
+	return e
+}
+
+func TestGetStats(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("GetStats", mock.Anything, "u1").
+		Return(&models.ReferralStats{UserId: "u1", ReferralCode: "ABCD2345", Total: 3, Rewarded: 2, PointsEarned: 100}, nil).Once()
+
+	rec := middlewaretest.Serve(e, httptest.NewRequest(echo.GET, "/users/u1/referrals", nil), middlewaretest.UserToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var stats models.ReferralStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, "ABCD2345", stats.ReferralCode)
+	assert.Equal(t, 3, stats.Total)
+
+	rec = middlewaretest.Serve(e, httptest.NewRequest(echo.GET, "/users/u2/referrals", nil), middlewaretest.UserToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestReferOnlyAtRegistration(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+
+	req := httptest.NewRequest(echo.POST, "/users/u1/referrer", strings.NewReader(`{"code":"ABCD2345"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := middlewaretest.Serve(e, req, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUCase.AssertNotCalled(t, "Refer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReward(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Reward", mock.Anything, "u1").
+		Return(&models.Referral{Id: 7, RefereeId: "u1", Status: models.ReferralRewarded}, nil).Once()
+
+	rec := middlewaretest.Serve(e, httptest.NewRequest(echo.POST, "/users/u1/referrer/reward", nil), middlewaretest.UserToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = middlewaretest.Serve(e, httptest.NewRequest(echo.POST, "/users/u1/referrer/reward", nil), middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
@@ -0,0 +1,14 @@
+package referral
+
+import (
+	"github.com/models"
+)
+
+var (
+	// ErrUnknownCode will throw if no active user owns the given referral code
+	ErrUnknownCode = models.NewBadParam("Unknown referral code")
+	// ErrSelfReferral will throw if a user gives its own referral code
+	ErrSelfReferral = models.NewBadParam("A user can not use its own referral code")
+	// ErrReferralCycle will throw if the referrer was itself referred, directly or not, by the referee
+	ErrReferralCycle = models.NewBadParam("The referral would create a cycle")
+)
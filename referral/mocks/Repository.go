@@ -0,0 +1,108 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// GetByReferee provides a mock function with given fields: ctx, refereeId
+func (_m *Repository) GetByReferee(ctx context.Context, refereeId string) (*models.Referral, error) {
+	ret := _m.Called(ctx, refereeId)
+
+	var r0 *models.Referral
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Referral); ok {
+		r0 = rf(ctx, refereeId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Referral)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refereeId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByRefereeForUpdate provides a mock function with given fields: ctx, refereeId
+func (_m *Repository) GetByRefereeForUpdate(ctx context.Context, refereeId string) (*models.Referral, error) {
+	ret := _m.Called(ctx, refereeId)
+
+	var r0 *models.Referral
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Referral); ok {
+		r0 = rf(ctx, refereeId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Referral)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refereeId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, r
+func (_m *Repository) Insert(ctx context.Context, r *models.Referral) error {
+	ret := _m.Called(ctx, r)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Referral) error); ok {
+		r0 = rf(ctx, r)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkRewarded provides a mock function with given fields: ctx, r
+func (_m *Repository) MarkRewarded(ctx context.Context, r *models.Referral) error {
+	ret := _m.Called(ctx, r)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Referral) error); ok {
+		r0 = rf(ctx, r)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Stats provides a mock function with given fields: ctx, referrerId
+func (_m *Repository) Stats(ctx context.Context, referrerId string) (*models.ReferralStats, error) {
+	ret := _m.Called(ctx, referrerId)
+
+	var r0 *models.ReferralStats
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.ReferralStats); ok {
+		r0 = rf(ctx, referrerId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReferralStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, referrerId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
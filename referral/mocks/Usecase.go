@@ -0,0 +1,80 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// GetStats provides a mock function with given fields: ctx, userId
+func (_m *Usecase) GetStats(ctx context.Context, userId string) (*models.ReferralStats, error) {
+	ret := _m.Called(ctx, userId)
+
+	var r0 *models.ReferralStats
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.ReferralStats); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ReferralStats)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Refer provides a mock function with given fields: ctx, refereeId, code
+func (_m *Usecase) Refer(ctx context.Context, refereeId string, code string) (*models.Referral, error) {
+	ret := _m.Called(ctx, refereeId, code)
+
+	var r0 *models.Referral
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *models.Referral); ok {
+		r0 = rf(ctx, refereeId, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Referral)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, refereeId, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reward provides a mock function with given fields: ctx, refereeId
+func (_m *Usecase) Reward(ctx context.Context, refereeId string) (*models.Referral, error) {
+	ret := _m.Called(ctx, refereeId)
+
+	var r0 *models.Referral
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Referral); ok {
+		r0 = rf(ctx, refereeId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Referral)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refereeId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
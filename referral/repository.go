@@ -0,0 +1,17 @@
+package referral
+
+import (
+	"context"
+
+	"github.com/models"
+)
+
+// Repository represent the referral's repository contract.
+// MarkRewarded only moves a pending referral, so both sides can not be rewarded twice.
+type Repository interface {
+	GetByReferee(ctx context.Context, refereeId string) (*models.Referral, error)
+	GetByRefereeForUpdate(ctx context.Context, refereeId string) (*models.Referral, error)
+	Insert(ctx context.Context, r *models.Referral) error
+	MarkRewarded(ctx context.Context, r *models.Referral) error
+	Stats(ctx context.Context, referrerId string) (*models.ReferralStats, error)
+}
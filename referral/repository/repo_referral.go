@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/sirupsen/logrus"
+
+	"github.com/models"
+	"github.com/mysqlerr"
+	"github.com/referral"
+	"github.com/transaction"
+)
+
+// referralColumns lists the columns in the order they are scanned by fetch
+const referralColumns = `id, referrer_id, referee_id, code, status, referrer_points, referee_points, created_date, rewarded_date`
+
+type referralRepository struct {
+	Conn *sql.DB
+}
+
+// NewreferralRepository will create an object that represent the referral.Repository interface
+func NewreferralRepository(Conn *sql.DB) referral.Repository {
+	return &referralRepository{Conn}
+}
+
+// conn will return the transaction of the unit of work ctx belongs to, or the connection pool
+func (m *referralRepository) conn(ctx context.Context) transaction.DBTX {
+	return transaction.Conn(ctx, m.Conn)
+}
+
+func (m *referralRepository) getOne(ctx context.Context, query string, args ...interface{}) (*models.Referral, error) {
+	r := new(models.Referral)
+	err := m.conn(ctx).QueryRowContext(ctx, query, args...).Scan(
+		&r.Id,
+		&r.ReferrerId,
+		&r.RefereeId,
+		&r.Code,
+		&r.Status,
+		&r.ReferrerPoints,
+		&r.RefereePoints,
+		&r.CreatedDate,
+		&r.RewardedDate,
+	)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return r, nil
+}
+
+func (m *referralRepository) GetByReferee(ctx context.Context, refereeId string) (*models.Referral, error) {
+	return m.getOne(ctx, `SELECT `+referralColumns+` FROM referrals WHERE referee_id = ?`, refereeId)
+}
+
+// GetByRefereeForUpdate will return the referral of the referee and lock it until the unit of work of ctx ends
+func (m *referralRepository) GetByRefereeForUpdate(ctx context.Context, refereeId string) (*models.Referral, error) {
+	return m.getOne(ctx, `SELECT `+referralColumns+` FROM referrals WHERE referee_id = ? FOR UPDATE`, refereeId)
+}
+
+// Insert will record the referral, a referee that was already referred fails with models.ErrConflict
+func (m *referralRepository) Insert(ctx context.Context, r *models.Referral) error {
+	query := `INSERT referrals SET referrer_id=? , referee_id=? , code=? , status=? , created_date=?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	r.CreatedDate = time.Now()
+	res, err := stmt.ExecContext(ctx, r.ReferrerId, r.RefereeId, r.Code, r.Status, r.CreatedDate)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlerr.DuplicateEntry {
+			return models.ErrConflict
+		}
+		return err
+	}
+
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	r.Id = lastID
+	return nil
+}
+
+// MarkRewarded will record the points given to both sides, a referral that is not pending anymore fails with models.ErrConflict
+func (m *referralRepository) MarkRewarded(ctx context.Context, r *models.Referral) error {
+	query := `UPDATE referrals SET status=? , referrer_points=? , referee_points=? , rewarded_date=? WHERE id = ? AND status = ?`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	res, err := stmt.ExecContext(ctx, models.ReferralRewarded, r.ReferrerPoints, r.RefereePoints, now, r.Id, models.ReferralPending)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrConflict
+	}
+	r.Status = models.ReferralRewarded
+	r.RewardedDate = &now
+	return nil
+}
+
+// Stats will count the referrals made by the referrer and sum the points they earned it
+func (m *referralRepository) Stats(ctx context.Context, referrerId string) (*models.ReferralStats, error) {
+	query := `SELECT COUNT(*), COALESCE(SUM(status = ?), 0), COALESCE(SUM(status = ?), 0), COALESCE(SUM(referrer_points), 0)
+	FROM referrals WHERE referrer_id = ?`
+
+	stats := &models.ReferralStats{UserId: referrerId}
+	err := m.conn(ctx).QueryRowContext(ctx, query, models.ReferralPending, models.ReferralRewarded, referrerId).
+		Scan(&stats.Total, &stats.Pending, &stats.Rewarded, &stats.PointsEarned)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+	return stats, nil
+}
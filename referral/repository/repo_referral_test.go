@@ -0,0 +1,112 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/models"
+	referralRepo "github.com/referral/repository"
+)
+
+var referralColumns = []string{"id", "referrer_id", "referee_id", "code", "status", "referrer_points", "referee_points",
+	"created_date", "rewarded_date"}
+
+func TestGetByReferee(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "SELECT .+ FROM referrals WHERE referee_id = \\?"
+	mock.ExpectQuery(query).WithArgs("u1").
+		WillReturnRows(sqlmock.NewRows(referralColumns).AddRow(7, "u0", "u1", "ABCD2345", "pending", 0, 0, time.Now(), nil))
+	mock.ExpectQuery(query).WithArgs("u2").WillReturnRows(sqlmock.NewRows(referralColumns))
+
+	a := referralRepo.NewreferralRepository(db)
+	r, err := a.GetByReferee(context.TODO(), "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "u0", r.ReferrerId)
+	assert.Nil(t, r.RewardedDate)
+
+	_, err = a.GetByReferee(context.TODO(), "u2")
+	assert.Equal(t, models.ErrNotFound, err)
+}
+
+func TestInsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	r := &models.Referral{ReferrerId: "u0", RefereeId: "u1", Code: "ABCD2345", Status: models.ReferralPending}
+	query := "INSERT referrals SET referrer_id=\\? , referee_id=\\? , code=\\? , status=\\? , created_date=\\?"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("u0", "u1", "ABCD2345", "pending", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectPrepare(query).ExpectExec().WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+
+	a := referralRepo.NewreferralRepository(db)
+	require.NoError(t, a.Insert(context.TODO(), r))
+	assert.Equal(t, int64(7), r.Id)
+	assert.Equal(t, models.ErrConflict, a.Insert(context.TODO(), r))
+}
+
+func TestMarkRewarded(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	r := &models.Referral{Id: 7, ReferrerPoints: 50, RefereePoints: 25, Status: models.ReferralPending}
+	query := "UPDATE referrals SET status=\\? , referrer_points=\\? , referee_points=\\? , rewarded_date=\\? WHERE id = \\? AND status = \\?"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("rewarded", int64(50), int64(25), sqlmock.AnyArg(), int64(7), "pending").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := referralRepo.NewreferralRepository(db)
+	require.NoError(t, a.MarkRewarded(context.TODO(), r))
+	assert.Equal(t, models.ReferralRewarded, r.Status)
+	assert.NotNil(t, r.RewardedDate)
+	assert.Equal(t, models.ErrConflict, a.MarkRewarded(context.TODO(), r))
+}
+
+func TestStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "SELECT COUNT\\(\\*\\), .+ FROM referrals WHERE referrer_id = \\?"
+	mock.ExpectQuery(query).WithArgs("pending", "rewarded", "u0").
+		WillReturnRows(sqlmock.NewRows([]string{"total", "pending", "rewarded", "points"}).AddRow(3, 1, 2, 100))
+
+	a := referralRepo.NewreferralRepository(db)
+	stats, err := a.Stats(context.TODO(), "u0")
+	require.NoError(t, err)
+	assert.Equal(t, &models.ReferralStats{UserId: "u0", Total: 3, Pending: 1, Rewarded: 2, PointsEarned: 100}, stats)
+}
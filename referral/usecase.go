@@ -0,0 +1,14 @@
+package referral
+
+import (
+	"context"
+
+	"github.com/models"
+)
+
+// Usecase represent the referral's usecases
+type Usecase interface {
+	Refer(ctx context.Context, refereeId string, code string) (*models.Referral, error)
+	Reward(ctx context.Context, refereeId string) (*models.Referral, error)
+	GetStats(ctx context.Context, userId string) (*models.ReferralStats, error)
+}
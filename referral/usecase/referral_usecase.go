@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/models"
+	"github.com/points"
+	"github.com/referral"
+	"github.com/transaction"
+	"github.com/user"
+)
+
+const (
+	// systemUser is recorded as the author of the points entries awarded for a referral
+	systemUser = "system"
+
+	// maxChainDepth bounds the walk up the referral chain done to detect cycles
+	maxChainDepth = 64
+)
+
+type referralUsecase struct {
+	referralRepo   referral.Repository
+	userRepo       user.Repository
+	pointsUc       points.Usecase
+	txManager      transaction.Manager
+	contextTimeout time.Duration
+}
+
+// NewreferralUsecase will create new an referralUsecase object representation of referral.Usecase interface
+func NewreferralUsecase(r referral.Repository, ur user.Repository, pointsUc points.Usecase, txManager transaction.Manager, timeout time.Duration) referral.Usecase {
+	return &referralUsecase{
+		referralRepo:   r,
+		userRepo:       ur,
+		pointsUc:       pointsUc,
+		txManager:      txManager,
+		contextTimeout: timeout,
+	}
+}
+
+// Refer will record that the referee was referred by the owner of the code.
+// A user is referred at most once, it can not use its own code nor the code of a user it referred, directly or not.
+func (m referralUsecase) Refer(c context.Context, refereeId string, code string) (*models.Referral, error) {
+	code = referral.NormalizeCode(code)
+	if code == "" {
+		return nil, referral.ErrUnknownCode
+	}
+
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	var result *models.Referral
+	err := m.txManager.Do(ctx, func(ctx context.Context) error {
+		referrer, err := m.userRepo.GetByReferralCode(ctx, code)
+		if errors.Is(err, models.ErrNotFound) {
+			return referral.ErrUnknownCode
+		}
+		if err != nil {
+			return err
+		}
+		if referrer.Id == refereeId {
+			return referral.ErrSelfReferral
+		}
+
+		_, err = m.referralRepo.GetByReferee(ctx, refereeId)
+		if err == nil {
+			return models.ErrConflict
+		}
+		if !errors.Is(err, models.ErrNotFound) {
+			return err
+		}
+		if err := m.checkCycle(ctx, referrer.Id, refereeId); err != nil {
+			return err
+		}
+
+		result = &models.Referral{
+			ReferrerId: referrer.Id,
+			RefereeId:  refereeId,
+			Code:       code,
+			Status:     models.ReferralPending,
+		}
+		return m.referralRepo.Insert(ctx, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkCycle will walk up the chain of the users who referred the referrer and fail when it reaches the referee
+func (m referralUsecase) checkCycle(ctx context.Context, referrerId string, refereeId string) error {
+	current := referrerId
+	for depth := 0; depth < maxChainDepth; depth++ {
+		r, err := m.referralRepo.GetByReferee(ctx, current)
+		if errors.Is(err, models.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if r.ReferrerId == refereeId {
+			return referral.ErrReferralCycle
+		}
+		current = r.ReferrerId
+	}
+	return referral.ErrReferralCycle
+}
+
+// Reward will award the points of the referral events to the referrer and the referee once the referee is verified.
+// Rewarding a referral again returns it unchanged, a referee that was not referred fails with models.ErrNotFound.
+func (m referralUsecase) Reward(c context.Context, refereeId string) (*models.Referral, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	var result *models.Referral
+	err := m.txManager.Do(ctx, func(ctx context.Context) error {
+		r, err := m.referralRepo.GetByRefereeForUpdate(ctx, refereeId)
+		if err != nil {
+			return err
+		}
+		result = r
+		if r.Status == models.ReferralRewarded {
+			return nil
+		}
+
+		reference := "referral-" + strconv.FormatInt(r.Id, 10)
+		r.ReferrerPoints, err = m.earn(ctx, r.ReferrerId, models.EventReferrer, reference)
+		if err != nil {
+			return err
+		}
+		r.RefereePoints, err = m.earn(ctx, r.RefereeId, models.EventReferee, reference)
+		if err != nil {
+			return err
+		}
+		return m.referralRepo.MarkRewarded(ctx, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// earn will award the points of the event to the user in the transaction of ctx and return how many were awarded
+func (m referralUsecase) earn(ctx context.Context, userId string, event string, reference string) (int64, error) {
+	entry, err := m.pointsUc.Earn(ctx, &models.PointsEvent{UserId: userId, Event: event, Reference: reference}, systemUser)
+	if err != nil || entry == nil {
+		return 0, err
+	}
+	return entry.Points, nil
+}
+
+// GetStats will return the referral code of the user and the referrals it made.
+// Users created before the codes were generated get their code on the first call.
+func (m referralUsecase) GetStats(c context.Context, userId string) (*models.ReferralStats, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	existedUser, err := m.userRepo.GetByID(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	code := ""
+	if existedUser.ReferralCode != nil {
+		code = *existedUser.ReferralCode
+	} else {
+		code, err = referral.GenerateCode(ctx, m.userRepo)
+		if err != nil {
+			return nil, err
+		}
+		err = m.userRepo.UpdateReferralCode(ctx, userId, code)
+		if errors.Is(err, models.ErrConflict) {
+			// a concurrent call gave the user its code first, or took the same code for another user
+			return m.GetStats(c, userId)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stats, err := m.referralRepo.Stats(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	stats.ReferralCode = code
+
+	referredBy, err := m.referralRepo.GetByReferee(ctx, userId)
+	switch {
+	case err == nil:
+		stats.ReferredBy = &referredBy.ReferrerId
+	case !errors.Is(err, models.ErrNotFound):
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,135 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/models"
+	_pointsMock "github.com/points/mocks"
+	"github.com/referral"
+	"github.com/referral/mocks"
+	ucase "github.com/referral/usecase"
+	_txMock "github.com/transaction/mocks"
+	_userMock "github.com/user/mocks"
+)
+
+func inTransaction() *_txMock.Manager {
+	mockTx := new(_txMock.Manager)
+	mockTx.On("Do", mock.Anything, mock.Anything).
+		Return(func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) })
+	return mockTx
+}
+
+func TestRefer(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockReferralRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByReferralCode", mock.Anything, "ABCD2345").Return(&models.User{Id: "u0"}, nil).Once()
+		mockReferralRepo.On("GetByReferee", mock.Anything, "u1").Return(nil, models.ErrNotFound).Once()
+		mockReferralRepo.On("GetByReferee", mock.Anything, "u0").Return(nil, models.ErrNotFound).Once()
+		mockReferralRepo.On("Insert", mock.Anything, mock.MatchedBy(func(r *models.Referral) bool {
+			return r.ReferrerId == "u0" && r.RefereeId == "u1" && r.Status == models.ReferralPending
+		})).Return(nil).Once()
+
+		u := ucase.NewreferralUsecase(mockReferralRepo, mockUserRepo, new(_pointsMock.Usecase), inTransaction(), time.Second*2)
+		res, err := u.Refer(context.TODO(), "u1", "abcd-2345")
+		require.NoError(t, err)
+		assert.Equal(t, "ABCD2345", res.Code)
+		mockReferralRepo.AssertExpectations(t)
+	})
+
+	t.Run("self", func(t *testing.T) {
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByReferralCode", mock.Anything, "ABCD2345").Return(&models.User{Id: "u1"}, nil).Once()
+
+		u := ucase.NewreferralUsecase(new(mocks.Repository), mockUserRepo, new(_pointsMock.Usecase), inTransaction(), time.Second*2)
+		_, err := u.Refer(context.TODO(), "u1", "ABCD2345")
+		assert.Equal(t, referral.ErrSelfReferral, err)
+		assert.True(t, errors.Is(err, models.ErrBadParamInput))
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		// u1 referred u2 who referred u3, u1 now gives the code of u3
+		mockReferralRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByReferralCode", mock.Anything, "CODEOFU3").Return(&models.User{Id: "u3"}, nil).Once()
+		mockReferralRepo.On("GetByReferee", mock.Anything, "u1").Return(nil, models.ErrNotFound).Once()
+		mockReferralRepo.On("GetByReferee", mock.Anything, "u3").Return(&models.Referral{ReferrerId: "u2", RefereeId: "u3"}, nil).Once()
+		mockReferralRepo.On("GetByReferee", mock.Anything, "u2").Return(&models.Referral{ReferrerId: "u1", RefereeId: "u2"}, nil).Once()
+
+		u := ucase.NewreferralUsecase(mockReferralRepo, mockUserRepo, new(_pointsMock.Usecase), inTransaction(), time.Second*2)
+		_, err := u.Refer(context.TODO(), "u1", "CODEOFU3")
+		assert.Equal(t, referral.ErrReferralCycle, err)
+		mockReferralRepo.AssertNotCalled(t, "Insert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("already-referred", func(t *testing.T) {
+		mockReferralRepo := new(mocks.Repository)
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByReferralCode", mock.Anything, "ABCD2345").Return(&models.User{Id: "u0"}, nil).Once()
+		mockReferralRepo.On("GetByReferee", mock.Anything, "u1").Return(&models.Referral{ReferrerId: "u9"}, nil).Once()
+
+		u := ucase.NewreferralUsecase(mockReferralRepo, mockUserRepo, new(_pointsMock.Usecase), inTransaction(), time.Second*2)
+		_, err := u.Refer(context.TODO(), "u1", "ABCD2345")
+		assert.Equal(t, models.ErrConflict, err)
+	})
+}
+
+func TestReward(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockReferralRepo := new(mocks.Repository)
+		mockPointsUc := new(_pointsMock.Usecase)
+		r := &models.Referral{Id: 7, ReferrerId: "u0", RefereeId: "u1", Status: models.ReferralPending}
+		mockReferralRepo.On("GetByRefereeForUpdate", mock.Anything, "u1").Return(r, nil).Once()
+		mockPointsUc.On("Earn", mock.Anything, &models.PointsEvent{UserId: "u0", Event: models.EventReferrer, Reference: "referral-7"}, "system").
+			Return(&models.PointsEntry{Points: 50}, nil).Once()
+		mockPointsUc.On("Earn", mock.Anything, &models.PointsEvent{UserId: "u1", Event: models.EventReferee, Reference: "referral-7"}, "system").
+			Return(&models.PointsEntry{Points: 25}, nil).Once()
+		mockReferralRepo.On("MarkRewarded", mock.Anything, mock.MatchedBy(func(r *models.Referral) bool {
+			return r.ReferrerPoints == 50 && r.RefereePoints == 25
+		})).Return(nil).Once()
+
+		u := ucase.NewreferralUsecase(mockReferralRepo, new(_userMock.Repository), mockPointsUc, inTransaction(), time.Second*2)
+		_, err := u.Reward(context.TODO(), "u1")
+		require.NoError(t, err)
+		mockReferralRepo.AssertExpectations(t)
+		mockPointsUc.AssertExpectations(t)
+	})
+
+	t.Run("already-rewarded", func(t *testing.T) {
+		mockReferralRepo := new(mocks.Repository)
+		mockPointsUc := new(_pointsMock.Usecase)
+		r := &models.Referral{Id: 7, ReferrerId: "u0", RefereeId: "u1", Status: models.ReferralRewarded}
+		mockReferralRepo.On("GetByRefereeForUpdate", mock.Anything, "u1").Return(r, nil).Once()
+
+		u := ucase.NewreferralUsecase(mockReferralRepo, new(_userMock.Repository), mockPointsUc, inTransaction(), time.Second*2)
+		res, err := u.Reward(context.TODO(), "u1")
+		require.NoError(t, err)
+		assert.Equal(t, r, res)
+		mockPointsUc.AssertNotCalled(t, "Earn", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetStats(t *testing.T) {
+	mockReferralRepo := new(mocks.Repository)
+	mockUserRepo := new(_userMock.Repository)
+	mockUserRepo.On("GetByID", mock.Anything, "u1").Return(&models.User{Id: "u1"}, nil).Once()
+	mockUserRepo.On("GetByReferralCodeIncludeDeleted", mock.Anything, mock.AnythingOfType("string")).Return(nil, models.ErrNotFound).Once()
+	mockUserRepo.On("UpdateReferralCode", mock.Anything, "u1", mock.AnythingOfType("string")).Return(nil).Once()
+	mockReferralRepo.On("Stats", mock.Anything, "u1").Return(&models.ReferralStats{UserId: "u1", Total: 2, Pending: 1, Rewarded: 1}, nil).Once()
+	mockReferralRepo.On("GetByReferee", mock.Anything, "u1").Return(&models.Referral{ReferrerId: "u0"}, nil).Once()
+
+	u := ucase.NewreferralUsecase(mockReferralRepo, mockUserRepo, new(_pointsMock.Usecase), inTransaction(), time.Second*2)
+	stats, err := u.GetStats(context.TODO(), "u1")
+	require.NoError(t, err)
+	assert.Len(t, stats.ReferralCode, 8)
+	assert.Equal(t, "u0", *stats.ReferredBy)
+	assert.Equal(t, 2, stats.Total)
+	mockUserRepo.AssertExpectations(t)
+}
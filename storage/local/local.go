@@ -0,0 +1,69 @@
+// Package local stores the files in a directory of the local filesystem, served by the service itself.
+package local
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/storage"
+)
+
+type localStorage struct {
+	dir     string
+	baseURL string
+}
+
+// New will create a storage.Storage writing under dir, the url of a file is baseURL followed by its key
+func New(dir string, baseURL string) storage.Storage {
+	return &localStorage{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (s *localStorage) Put(ctx context.Context, key string, contentType string, data []byte) (string, error) {
+	key, err := storage.CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+
+	// write next to the destination and rename, so a file is never served half written
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return s.baseURL + "/" + key, nil
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	key, err := storage.CleanKey(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+package local
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/storage"
+)
+
+func TestPut(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	s := New(dir, "/uploads/")
+
+	url, err := s.Put(context.TODO(), "users/u1/avatar-256.jpg", "image/jpeg", []byte("first"))
+	require.NoError(t, err)
+	assert.Equal(t, "/uploads/users/u1/avatar-256.jpg", url)
+
+	_, err = s.Put(context.TODO(), "users/u1/avatar-256.jpg", "image/jpeg", []byte("second"))
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "users", "u1", "avatar-256.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	files, err := ioutil.ReadDir(filepath.Join(dir, "users", "u1"))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestPutInvalidKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	s := New(dir, "/uploads")
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "users/../../secret", `users\u1`} {
+		_, err := s.Put(context.TODO(), key, "image/jpeg", []byte("data"))
+		assert.Equal(t, storage.ErrInvalidKey, err, key)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	s := New(dir, "/uploads")
+
+	_, err = s.Put(context.TODO(), "users/u1/avatar-256.jpg", "image/jpeg", []byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, s.Delete(context.TODO(), "users/u1/avatar-256.jpg"))
+	_, err = os.Stat(filepath.Join(dir, "users", "u1", "avatar-256.jpg"))
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, s.Delete(context.TODO(), "users/u1/avatar-256.jpg"))
+}
@@ -0,0 +1,227 @@
+// Package s3 stores the files in a bucket of an S3 compatible object storage, such as AWS S3 or MinIO.
+// The requests are signed with AWS Signature Version 4.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/storage"
+)
+
+const (
+	// maxErrorBody limits how much of an error response is read to build the error message
+	maxErrorBody = 1024
+
+	amzDateFormat = "20060102T150405Z"
+	algorithm     = "AWS4-HMAC-SHA256"
+)
+
+// Options represent the bucket the files are stored in.
+// Endpoint is the base url of the object storage, e.g. https://s3.ap-southeast-1.amazonaws.com or http://localhost:9000.
+// PathStyle addresses the bucket as Endpoint/Bucket/key instead of the Bucket.host virtual host, most S3 compatible servers need it.
+// The url of a file is BaseURL followed by its key when BaseURL is set, such as a CDN in front of the bucket,
+// and the object url otherwise. ACL is sent as x-amz-acl when it is set, e.g. public-read.
+type Options struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+	BaseURL   string
+	ACL       string
+}
+
+type s3Storage struct {
+	opts     Options
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// New will create a storage.Storage writing to the bucket, http.DefaultClient is used when client is nil
+func New(opts Options, client *http.Client) (storage.Storage, error) {
+	endpoint, err := url.Parse(strings.TrimRight(opts.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint %q must be an absolute url", opts.Endpoint)
+	}
+	if opts.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	return &s3Storage{opts: opts, endpoint: endpoint, client: client, now: time.Now}, nil
+}
+
+func (s *s3Storage) Put(ctx context.Context, key string, contentType string, data []byte) (string, error) {
+	key, err := storage.CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest("PUT", s.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if s.opts.ACL != "" {
+		req.Header.Set("X-Amz-Acl", s.opts.ACL)
+	}
+	if err := s.do(ctx, req, data); err != nil {
+		return "", err
+	}
+	if s.opts.BaseURL != "" {
+		return s.opts.BaseURL + "/" + key, nil
+	}
+	return s.objectURL(key), nil
+}
+
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	key, err := storage.CleanKey(key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("DELETE", s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	// S3 answers 204 to the deletion of a missing object as well
+	return s.do(ctx, req, nil)
+}
+
+// objectURL will return the url of the object stored under the key
+func (s *s3Storage) objectURL(key string) string {
+	u := *s.endpoint
+	if s.opts.PathStyle {
+		u.Path = u.Path + "/" + s.opts.Bucket + "/" + key
+	} else {
+		u.Host = s.opts.Bucket + "." + u.Host
+		u.Path = u.Path + "/" + key
+	}
+	u.RawPath = encodePath(u.Path)
+	return u.String()
+}
+
+func (s *s3Storage) do(ctx context.Context, req *http.Request, payload []byte) error {
+	sign(req, payload, s.opts.AccessKey, s.opts.SecretKey, s.opts.Region, s.now().UTC())
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("s3 %s %s responded with status %d: %s", req.Method, req.URL.Path, resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// sign will add the x-amz-date, x-amz-content-sha256 and Authorization headers of AWS Signature Version 4,
+// every header already set on the request is signed along with the host
+func sign(req *http.Request, payload []byte, accessKey string, secretKey string, region string, now time.Time) {
+	payloadHash := sha256.Sum256(payload)
+	amzDate := now.Format(amzDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		encodePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := now.Format("20060102") + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{algorithm, amzDate, scope, hex.EncodeToString(canonicalHash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), now.Format("20060102"))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// encodePath will percent-encode every byte of the path but the unreserved characters and the slashes
+func encodePath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return uriEncode(path, false)
+}
+
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
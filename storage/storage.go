@@ -0,0 +1,33 @@
+// Package storage keeps the files uploaded by the users and the merchants, such as the profile pictures.
+package storage
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+)
+
+// ErrInvalidKey will throw if a key is empty, absolute or leaves the storage with ..
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage represent a place the files are stored in and served from.
+// A key is a slash separated relative path such as users/u1/avatar-256.jpg.
+type Storage interface {
+	// Put will store the data under the key, replacing a previous file, and return the public url of the file
+	Put(ctx context.Context, key string, contentType string, data []byte) (string, error)
+	// Delete will remove the file stored under the key, deleting a missing file is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// CleanKey will return the canonical form of the key, or ErrInvalidKey when it can not be stored safely
+func CleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
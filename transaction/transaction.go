@@ -0,0 +1,80 @@
+// Package transaction shares one database transaction between the repositories taking part in a unit of work.
+//
+// Manager.Do begins a transaction and stores it in the context given to the unit of work.
+// The repositories resolve their connection with Conn, so every query issued with that context
+// runs on the same *sql.Tx and is committed or rolled back together.
+package transaction
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+type txKey struct{}
+
+// DBTX represent the methods shared by *sql.DB and *sql.Tx that the repositories use
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Manager represent the unit of work of the repositories
+type Manager interface {
+	// Do will run fn in a transaction, committed when fn returns nil and rolled back otherwise.
+	// When ctx already carries a transaction fn joins it and the outermost Do decides the outcome.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type sqlManager struct {
+	db *sql.DB
+}
+
+// NewManager will create a Manager beginning its transactions on db
+func NewManager(db *sql.DB) Manager {
+	return &sqlManager{db: db}
+}
+
+func (m *sqlManager) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			rollback(tx)
+			panic(p)
+		}
+		if err != nil {
+			rollback(tx)
+			return
+		}
+		if errCommit := tx.Commit(); errCommit != nil {
+			err = fmt.Errorf("commit transaction: %w", errCommit)
+		}
+	}()
+
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+func rollback(tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		logrus.Error(err)
+	}
+}
+
+// Conn will return the transaction carried by ctx, or db when the call is not part of a unit of work
+func Conn(ctx context.Context, db *sql.DB) DBTX {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
@@ -0,0 +1,80 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/transaction"
+)
+
+func TestDo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+	m := transaction.NewManager(db)
+
+	t.Run("commit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT users").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT merchant_transactions").WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := m.Do(context.TODO(), func(ctx context.Context) error {
+			if _, err := transaction.Conn(ctx, db).ExecContext(ctx, "INSERT users SET id=?", "u1"); err != nil {
+				return err
+			}
+			// a nested unit of work joins the transaction instead of beginning another one
+			return m.Do(ctx, func(ctx context.Context) error {
+				_, err := transaction.Conn(ctx, db).ExecContext(ctx, "INSERT merchant_transactions SET id=?", "t1")
+				return err
+			})
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		failure := errors.New("insert failed")
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT users").WillReturnError(failure)
+		mock.ExpectRollback()
+
+		err := m.Do(context.TODO(), func(ctx context.Context) error {
+			_, err := transaction.Conn(ctx, db).ExecContext(ctx, "INSERT users SET id=?", "u1")
+			return err
+		})
+		assert.Equal(t, failure, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		assert.Panics(t, func() {
+			m.Do(context.TODO(), func(ctx context.Context) error { panic("boom") })
+		})
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestConn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	assert.Equal(t, db, transaction.Conn(context.TODO(), db))
+}
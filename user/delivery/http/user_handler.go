@@ -2,16 +2,26 @@ package http
 
 import (
 	"context"
+	"errors"
 	"github.com/labstack/echo"
 	"github.com/sirupsen/logrus"
-	validator "gopkg.in/go-playground/validator.v9"
 	"net/http"
 	"strconv"
 
+	"github.com/identityserver"
+	"github.com/middleware"
 	"github.com/models"
+	"github.com/picture"
 	"github.com/user"
+	"github.com/validation"
 )
 
+// pointsNotEditable is answered to the profile requests that try to set the points
+const pointsNotEditable = "points can only change through the points ledger"
+
+// passwordNotEditable is answered to the profile updates that try to set the password
+const passwordNotEditable = "password can only change through /account/password/change"
+
 // ResponseError represent the reseponse error struct
 type ResponseError struct {
 	Message string `json:"message"`
@@ -23,108 +33,171 @@ type userHandler struct {
 }
 
 // NewuserHandler will initialize the users/ resources endpoint
-func NewuserHandler(e *echo.Echo, us user.Usecase) {
+func NewuserHandler(e *echo.Echo, us user.Usecase, mw *middleware.GoMiddleware) {
 	handler := &userHandler{
 		userUsecase: us,
 	}
 	e.POST("/users", handler.CreateUser)
-	e.PUT("/users/:id", handler.UpdateUser)
+	e.PUT("/users/:id", handler.UpdateUser, mw.Authenticate, mw.Authorize(middleware.Policy{
+		Roles:      []string{models.RoleUser, models.RoleAdmin},
+		OwnerParam: "id",
+	}))
+	e.GET("/users", handler.FetchUser, mw.Authenticate, mw.Authorize(middleware.Policy{
+		Roles: []string{models.RoleAdmin},
+	}))
+	e.GET("/users/:id", handler.GetByID, mw.Authenticate, mw.Authorize(middleware.Policy{
+		Roles:      []string{models.RoleUser, models.RoleAdmin},
+		OwnerParam: "id",
+	}))
+	e.DELETE("/users/:id", handler.Delete, mw.Authenticate, mw.Authorize(middleware.Policy{
+		Roles:      []string{models.RoleUser, models.RoleAdmin},
+		OwnerParam: "id",
+	}))
+	e.PUT("/users/:id/avatar", handler.UploadAvatar, mw.Authenticate, mw.Authorize(middleware.Policy{
+		Roles:      []string{models.RoleUser, models.RoleAdmin},
+		OwnerParam: "id",
+	}))
+	e.POST("/users/:id/restore", handler.Restore, mw.Authenticate, mw.Authorize(middleware.Policy{
+		Roles: []string{models.RoleAdmin},
+	}))
 }
 
-func isRequestValid(m *models.NewCommandUser) (bool, error) {
-	validate := validator.New()
-	err := validate.Struct(m)
+// FetchUser will fetch the users based on given params, include_deleted also lists the deleted users
+func (a *userHandler) FetchUser(c echo.Context) error {
+	numS := c.QueryParam("num")
+	num, _ := strconv.Atoi(numS)
+	cursor := c.QueryParam("cursor")
+	includeDeleted := false
+	if includeDeletedS := c.QueryParam("include_deleted"); includeDeletedS != "" {
+		var err error
+		if includeDeleted, err = strconv.ParseBool(includeDeletedS); err != nil {
+			return c.JSON(http.StatusBadRequest, ResponseError{Message: "include_deleted must be a boolean"})
+		}
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	listUser, nextCursor, err := a.userUsecase.Fetch(ctx, cursor, int64(num), includeDeleted)
+
 	if err != nil {
-		return false, err
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
 	}
-	return true, nil
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, listUser)
 }
 
-// Store will store the user by given request body
-func (a *userHandler) CreateUser(c echo.Context) error {
+// GetByID will get user by given id
+func (a *userHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := a.userUsecase.GetByID(ctx, c.Param("id"))
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// Delete will soft delete user by given id
+func (a *userHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err := a.userUsecase.Delete(ctx, c.Param("id"), middleware.GetPrincipal(c).Username)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Restore will undo the soft delete of the user by given id
+func (a *userHandler) Restore(c echo.Context) error {
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err := a.userUsecase.Restore(ctx, c.Param("id"), middleware.GetPrincipal(c).Username)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// UploadAvatar will store the profile picture sent as the file field of a multipart form and return its thumbnails
+func (a *userHandler) UploadAvatar(c echo.Context) error {
+	file, err := picture.FormFile(c.Request(), "file")
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
-	//name := c.FormValue("name")
-	//var userCommand models.NewCommandUser
-	//err := c.Bind(&userCommand)
-	//if err != nil {
-	//	return c.JSON(http.StatusUnprocessableEntity, err.Error())
-	//}
-	phoneNumber, _ := strconv.Atoi(c.FormValue("phone_number"))
-	verificationCode, _ := strconv.Atoi(c.FormValue("verification_code"))
-	gender, _ := strconv.Atoi(c.FormValue("gender"))
-	idType, _ := strconv.Atoi(c.FormValue("id_type"))
-	referralCode, _ := strconv.Atoi(c.FormValue("referral_code"))
-	points, _ := strconv.Atoi(c.FormValue("points"))
-	userCommand:= models.NewCommandUser{
-		Id:                   c.FormValue("id"),
-		UserEmail:            c.FormValue("user_email"),
-		Password:             c.FormValue("password"),
-		FullName:             c.FormValue("full_name"),
-		PhoneNumber:          phoneNumber,
-		VerificationSendDate: c.FormValue("verification_send_date"),
-		VerificationCode:     verificationCode,
-		ProfilePictUrl:       "#",
-		Address:              c.FormValue("address"),
-		Dob:                  c.FormValue("dob"),
-		Gender:               gender,
-		IdType:               idType,
-		IdNumber:             c.FormValue("id_number"),
-		ReferralCode:         referralCode,
-		Points:               points,
-	}
-	if ok, err := isRequestValid(&userCommand); !ok {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	res, err := a.userUsecase.UploadProfilePicture(ctx, c.Param("id"), file, middleware.GetPrincipal(c).Username)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// CreateUser will register the user sent as a JSON or form request body
+func (a *userHandler) CreateUser(c echo.Context) error {
+	body, err := validation.Decode(c.Request())
+	if err != nil {
+		return c.JSON(validation.StatusCode(err), err)
+	}
+	if body.Has("points") {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: pointsNotEditable})
+	}
+	var userCommand models.NewCommandUser
+	if err := body.Bind(&userCommand); err != nil {
+		return c.JSON(validation.StatusCode(err), err)
 	}
 	ctx := c.Request().Context()
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	error := a.userUsecase.Create(ctx, &userCommand, "admin")
+	error := a.userUsecase.Create(ctx, &userCommand, userCommand.UserEmail)
 
 	if error != nil {
 		return c.JSON(getStatusCode(error), ResponseError{Message: error.Error()})
 	}
+	userCommand.Password = ""
 	return c.JSON(http.StatusCreated, userCommand)
 }
 
+// UpdateUser will update the profile of the user by given id with the JSON or form request body
 func (a *userHandler) UpdateUser(c echo.Context) error {
-	//var userCommand models.NewCommandUser
-	//err := c.Bind(&userCommand)
-	//if err != nil {
-	//	return c.JSON(http.StatusUnprocessableEntity, err.Error())
-	//}
-	phoneNumber, _ := strconv.Atoi(c.FormValue("phone_number"))
-	verificationCode, _ := strconv.Atoi(c.FormValue("verification_code"))
-	gender, _ := strconv.Atoi(c.FormValue("gender"))
-	idType, _ := strconv.Atoi(c.FormValue("id_type"))
-	referralCode, _ := strconv.Atoi(c.FormValue("referral_code"))
-	points, _ := strconv.Atoi(c.FormValue("points"))
-	userCommand:= models.NewCommandUser{
-		Id:                   c.FormValue("id"),
-		UserEmail:            c.FormValue("user_email"),
-		Password:             c.FormValue("password"),
-		FullName:             c.FormValue("full_name"),
-		PhoneNumber:          phoneNumber,
-		VerificationSendDate: c.FormValue("verification_send_date"),
-		VerificationCode:     verificationCode,
-		ProfilePictUrl:       "#",
-		Address:              c.FormValue("address"),
-		Dob:                  c.FormValue("dob"),
-		Gender:               gender,
-		IdType:               idType,
-		IdNumber:             c.FormValue("id_number"),
-		ReferralCode:         referralCode,
-		Points:               points,
-	}
-	if ok, err := isRequestValid(&userCommand); !ok {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	body, err := validation.Decode(c.Request())
+	if err != nil {
+		return c.JSON(validation.StatusCode(err), err)
+	}
+	if body.Has("points") {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: pointsNotEditable})
+	}
+	if body.Has("password") {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: passwordNotEditable})
+	}
+	var userCommand models.NewCommandUser
+	if err := body.Bind(&userCommand); err != nil {
+		return c.JSON(validation.StatusCode(err), err)
 	}
+	userCommand.Id = c.Param("id")
 	ctx := c.Request().Context()
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	error := a.userUsecase.Update(ctx, &userCommand, "admin")
+	error := a.userUsecase.Update(ctx, &userCommand, middleware.GetPrincipal(c).Username)
 
 	if error != nil {
 		return c.JSON(getStatusCode(error), ResponseError{Message: error.Error()})
@@ -136,17 +209,25 @@ func getStatusCode(err error) int {
 		return http.StatusOK
 	}
 	logrus.Error(err)
-	switch err {
-	case models.ErrInternalServerError:
+	switch {
+	case errors.Is(err, models.ErrInternalServerError):
 		return http.StatusInternalServerError
-	case models.ErrNotFound:
+	case errors.Is(err, models.ErrNotFound):
 		return http.StatusNotFound
-	case models.ErrUnAuthorize:
+	case errors.Is(err, models.ErrUnAuthorize):
 		return http.StatusUnauthorized
-	case models.ErrConflict:
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrConflict):
 		return http.StatusConflict
-	case models.ErrBadParamInput:
+	case errors.Is(err, picture.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, picture.ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, models.ErrBadParamInput):
 		return http.StatusBadRequest
+	case errors.Is(err, identityserver.ErrUnreachable):
+		return http.StatusBadGateway
 	default:
 		return http.StatusInternalServerError
 	}
@@ -1 +1,261 @@
 package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/middleware/middlewaretest"
+	"github.com/models"
+	"github.com/picture"
+	userHttp "github.com/user/delivery/http"
+	"github.com/user/mocks"
+	"github.com/validation"
+)
+
+func newServer(mockUCase *mocks.Usecase) *echo.Echo {
+	e := echo.New()
+	userHttp.NewuserHandler(e, mockUCase, middlewaretest.New())
+	return e
+}
+
+func serve(e *echo.Echo, method string, target string, token string) *httptest.ResponseRecorder {
+	return middlewaretest.Serve(e, middlewaretest.NewRequest(method, target, ""), token)
+}
+
+func TestFetch(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Fetch", mock.Anything, "cursor-1", int64(2), false).
+		Return([]*models.UserInfoDto{{Id: "u1"}, {Id: "u2"}}, "cursor-2", nil).Once()
+	mockUCase.On("Fetch", mock.Anything, "", int64(0), true).
+		Return([]*models.UserInfoDto{{Id: "u3", IsDeleted: 1}}, "", nil).Once()
+
+	rec := serve(e, echo.GET, "/users?num=2&cursor=cursor-1", middlewaretest.AdminToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "cursor-2", rec.Header().Get("X-Cursor"))
+	var list []models.UserInfoDto
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Len(t, list, 2)
+
+	rec = serve(e, echo.GET, "/users?include_deleted=true", middlewaretest.AdminToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Equal(t, 1, list[0].IsDeleted)
+
+	rec = serve(e, echo.GET, "/users", middlewaretest.UserToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestGetByID(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("GetByID", mock.Anything, "u1").Return(&models.UserInfoDto{Id: "u1", UserEmail: "john@mail.com"}, nil).Once()
+	mockUCase.On("GetByID", mock.Anything, "u3").Return(nil, models.ErrNotFound).Once()
+
+	rec := serve(e, echo.GET, "/users/u1", middlewaretest.UserToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var res models.UserInfoDto
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, "john@mail.com", res.UserEmail)
+
+	rec = serve(e, echo.GET, "/users/u2", middlewaretest.UserToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(e, echo.GET, "/users/u3", middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestDelete(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Delete", mock.Anything, "u2", "admin@mail.com").Return(nil).Once()
+
+	rec := serve(e, echo.DELETE, "/users/u2", middlewaretest.UserToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(e, echo.DELETE, "/users/u2", middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestRestore(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Restore", mock.Anything, "u2", "admin@mail.com").Return(nil).Once()
+
+	rec := serve(e, echo.POST, "/users/u2/restore", middlewaretest.UserToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serve(e, echo.POST, "/users/u2/restore", middlewaretest.AdminToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestUpdatePasswordNotEditable(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+
+	form := url.Values{"full_name": {"John"}, "user_email": {"john@mail.com"}, "password": {"new-secret"}}
+	req := httptest.NewRequest(echo.PUT, "/users/u1", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer admin-token")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/account/password/change")
+	mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdatePointsNotEditable(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+
+	form := url.Values{"full_name": {"John"}, "user_email": {"john@mail.com"}, "points": {"1000000"}}
+	req := httptest.NewRequest(echo.PUT, "/users/u1", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer admin-token")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func uploadRequest(t *testing.T, target string, field string, data []byte) *http.Request {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile(field, "avatar.png")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	req := httptest.NewRequest(echo.PUT, target, &body)
+	req.Header.Set(echo.HeaderContentType, form.FormDataContentType())
+	req.Header.Set(echo.HeaderAuthorization, "Bearer user-token")
+	return req
+}
+
+func TestUploadAvatar(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	pict := &models.Picture{Url: "/uploads/users/u1/avatar-512.png", Thumbnails: []models.Thumbnail{{Size: 512, Url: "/uploads/users/u1/avatar-512.png"}}}
+	mockUCase.On("UploadProfilePicture", mock.Anything, "u1", mock.Anything, "john@mail.com").Return(pict, nil).Once()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, uploadRequest(t, "/users/u1/avatar", "file", []byte("png")))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var res models.Picture
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, *pict, res)
+
+	mockUCase.On("UploadProfilePicture", mock.Anything, "u1", mock.Anything, "john@mail.com").Return(nil, picture.ErrTooLarge).Once()
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, uploadRequest(t, "/users/u1/avatar", "file", []byte("png")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	mockUCase.On("UploadProfilePicture", mock.Anything, "u1", mock.Anything, "john@mail.com").Return(nil, picture.ErrUnsupportedType).Once()
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, uploadRequest(t, "/users/u1/avatar", "file", []byte("pdf")))
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, uploadRequest(t, "/users/u1/avatar", "picture", []byte("png")))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, uploadRequest(t, "/users/u2/avatar", "file", []byte("png")))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertNumberOfCalls(t, "UploadProfilePicture", 3)
+}
+
+func TestCreateUser(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Create", mock.Anything, mock.MatchedBy(func(cmd *models.NewCommandUser) bool {
+		return cmd.PhoneNumber == "+6281234567890" && cmd.Gender == 1 && cmd.Password == "secret"
+	}), "john@mail.com").Return(nil).Twice()
+
+	body := `{"user_email": "john@mail.com", "password": "secret", "full_name": "John", "phone_number": "+6281234567890",
+		"address": "Jakarta", "dob": "1990-01-02 00:00:00", "gender": 1}`
+	req := httptest.NewRequest(echo.POST, "/users", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "secret")
+
+	form := url.Values{"user_email": {"john@mail.com"}, "password": {"secret"}, "phone_number": {"+6281234567890"},
+		"address": {"Jakarta"}, "dob": {"1990-01-02 00:00:00"}, "gender": {"1"}}
+	req = httptest.NewRequest(echo.POST, "/users", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	mockUCase.AssertNumberOfCalls(t, "Create", 2)
+}
+
+func TestCreateUserInvalid(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+
+	form := url.Values{"user_email": {"john@mail.com"}, "phone_number": {"abc"}, "address": {"Jakarta"},
+		"dob": {"1990-01-02 00:00:00"}, "gender": {"male"}}
+	req := httptest.NewRequest(echo.POST, "/users", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	var res validation.Error
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, []validation.FieldError{{Field: "gender", Rule: "type", Message: "must be an integer"}}, res.Errors)
+
+	form.Set("gender", "1")
+	req = httptest.NewRequest(echo.POST, "/users", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, "phone_number", res.Errors[0].Field)
+	assert.Equal(t, "e164", res.Errors[0].Rule)
+
+	req = httptest.NewRequest(echo.POST, "/users", strings.NewReader("user_email=john@mail.com"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMETextPlain)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	mockUCase.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdatePointsNotEditableJSON(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+
+	req := httptest.NewRequest(echo.PUT, "/users/u1", strings.NewReader(`{"user_email": "john@mail.com", "points": 1000000}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer admin-token")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "points ledger")
+	mockUCase.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
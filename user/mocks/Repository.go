@@ -1 +1,371 @@
+// Code generated by mockery v1.0.0
 package mocks
+
+import time "time"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+import context "golang.org/x/net/context"
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: ctx, id, deleted_by
+func (_m *Repository) Delete(ctx context.Context, id string, deleted_by string) error {
+	ret := _m.Called(ctx, id, deleted_by)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, deleted_by)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fetch provides a mock function with given fields: ctx, cursor, num
+func (_m *Repository) Fetch(ctx context.Context, cursor string, num int64) ([]*models.User, string, error) {
+	ret := _m.Called(ctx, cursor, num)
+
+	var r0 []*models.User
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []*models.User); ok {
+		r0 = rf(ctx, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.User)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
+		r1 = rf(ctx, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
+		r2 = rf(ctx, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FetchIncludeDeleted provides a mock function with given fields: ctx, cursor, num
+func (_m *Repository) FetchIncludeDeleted(ctx context.Context, cursor string, num int64) ([]*models.User, string, error) {
+	ret := _m.Called(ctx, cursor, num)
+
+	var r0 []*models.User
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []*models.User); ok {
+		r0 = rf(ctx, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.User)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) string); ok {
+		r1 = rf(ctx, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
+		r2 = rf(ctx, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByIDIncludeDeleted provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByIDIncludeDeleted(ctx context.Context, id string) (*models.User, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByReferralCode provides a mock function with given fields: ctx, code
+func (_m *Repository) GetByReferralCode(ctx context.Context, code string) (*models.User, error) {
+	ret := _m.Called(ctx, code)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByReferralCodeIncludeDeleted provides a mock function with given fields: ctx, code
+func (_m *Repository) GetByReferralCodeIncludeDeleted(ctx context.Context, code string) (*models.User, error) {
+	ret := _m.Called(ctx, code)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByUserEmail provides a mock function with given fields: ctx, userEmail
+func (_m *Repository) GetByUserEmail(ctx context.Context, userEmail string) (*models.User, error) {
+	ret := _m.Called(ctx, userEmail)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, userEmail)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userEmail)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByUserEmailIncludeDeleted provides a mock function with given fields: ctx, userEmail
+func (_m *Repository) GetByUserEmailIncludeDeleted(ctx context.Context, userEmail string) (*models.User, error) {
+	ret := _m.Called(ctx, userEmail)
+
+	var r0 *models.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.User); ok {
+		r0 = rf(ctx, userEmail)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userEmail)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPointsForUpdate provides a mock function with given fields: ctx, id
+func (_m *Repository) GetPointsForUpdate(ctx context.Context, id string) (int64, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementVerificationAttempts provides a mock function with given fields: ctx, id, maxAttempts
+func (_m *Repository) IncrementVerificationAttempts(ctx context.Context, id string, maxAttempts int) error {
+	ret := _m.Called(ctx, id, maxAttempts)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
+		r0 = rf(ctx, id, maxAttempts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Insert provides a mock function with given fields: ctx, a
+func (_m *Repository) Insert(ctx context.Context, a *models.User) error {
+	ret := _m.Called(ctx, a)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, a)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkVerified provides a mock function with given fields: ctx, id, channel
+func (_m *Repository) MarkVerified(ctx context.Context, id string, channel string) error {
+	ret := _m.Called(ctx, id, channel)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, channel)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, id, modified_by
+func (_m *Repository) Restore(ctx context.Context, id string, modified_by string) error {
+	ret := _m.Called(ctx, id, modified_by)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, modified_by)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetVerificationCode provides a mock function with given fields: ctx, id, channel, code, sentAt
+func (_m *Repository) SetVerificationCode(ctx context.Context, id string, channel string, code int, sentAt time.Time) error {
+	ret := _m.Called(ctx, id, channel, code, sentAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, time.Time) error); ok {
+		r0 = rf(ctx, id, channel, code, sentAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, ar
+func (_m *Repository) Update(ctx context.Context, ar *models.User) error {
+	ret := _m.Called(ctx, ar)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePoints provides a mock function with given fields: ctx, id, points
+func (_m *Repository) UpdatePoints(ctx context.Context, id string, points int64) error {
+	ret := _m.Called(ctx, id, points)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, id, points)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProfilePictUrl provides a mock function with given fields: ctx, id, url, modified_by
+func (_m *Repository) UpdateProfilePictUrl(ctx context.Context, id string, url string, modified_by string) error {
+	ret := _m.Called(ctx, id, url, modified_by)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, id, url, modified_by)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateReferralCode provides a mock function with given fields: ctx, id, code
+func (_m *Repository) UpdateReferralCode(ctx context.Context, id string, code string) error {
+	ret := _m.Called(ctx, id, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -1 +1,250 @@
+// Code generated by mockery v1.0.0
 package mocks
+
+import io "io"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+import context "golang.org/x/net/context"
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, ar, user
+func (_m *Usecase) Create(ctx context.Context, ar *models.NewCommandUser, user string) error {
+	ret := _m.Called(ctx, ar, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.NewCommandUser, string) error); ok {
+		r0 = rf(ctx, ar, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, id, user
+func (_m *Usecase) Delete(ctx context.Context, id string, user string) error {
+	ret := _m.Called(ctx, id, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fetch provides a mock function with given fields: ctx, cursor, num, includeDeleted
+func (_m *Usecase) Fetch(ctx context.Context, cursor string, num int64, includeDeleted bool) ([]*models.UserInfoDto, string, error) {
+	ret := _m.Called(ctx, cursor, num, includeDeleted)
+
+	var r0 []*models.UserInfoDto
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, bool) []*models.UserInfoDto); ok {
+		r0 = rf(ctx, cursor, num, includeDeleted)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.UserInfoDto)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, bool) string); ok {
+		r1 = rf(ctx, cursor, num, includeDeleted)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64, bool) error); ok {
+		r2 = rf(ctx, cursor, num, includeDeleted)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Usecase) GetByID(ctx context.Context, id string) (*models.UserInfoDto, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *models.UserInfoDto
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.UserInfoDto); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserInfoDto)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserInfo provides a mock function with given fields: ctx, token
+func (_m *Usecase) GetUserInfo(ctx context.Context, token string) (*models.UserInfoDto, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *models.UserInfoDto
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.UserInfoDto); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UserInfoDto)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: ctx, ar
+func (_m *Usecase) Login(ctx context.Context, ar *models.Login) (*models.GetToken, error) {
+	ret := _m.Called(ctx, ar)
+
+	var r0 *models.GetToken
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Login) *models.GetToken); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *models.Login) error); ok {
+		r1 = rf(ctx, ar)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: ctx, principal, refreshToken
+func (_m *Usecase) Logout(ctx context.Context, principal *models.Principal, refreshToken string) error {
+	ret := _m.Called(ctx, principal, refreshToken)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.Principal, string) error); ok {
+		r0 = rf(ctx, principal, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshToken provides a mock function with given fields: ctx, refreshToken
+func (_m *Usecase) RefreshToken(ctx context.Context, refreshToken string) (*models.GetToken, error) {
+	ret := _m.Called(ctx, refreshToken)
+
+	var r0 *models.GetToken
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.GetToken); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.GetToken)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id, user
+func (_m *Usecase) Restore(ctx context.Context, id string, user string) error {
+	ret := _m.Called(ctx, id, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, id, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, ar, user
+func (_m *Usecase) Update(ctx context.Context, ar *models.NewCommandUser, user string) error {
+	ret := _m.Called(ctx, ar, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.NewCommandUser, string) error); ok {
+		r0 = rf(ctx, ar, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ValidateTokenUser provides a mock function with given fields: ctx, token
+func (_m *Usecase) ValidateTokenUser(ctx context.Context, token string) (*models.Principal, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *models.Principal
+	if rf, ok := ret.Get(0).(func(context.Context, string) *models.Principal); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Principal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UploadProfilePicture provides a mock function with given fields: ctx, id, file, user
+func (_m *Usecase) UploadProfilePicture(ctx context.Context, id string, file io.Reader, user string) (*models.Picture, error) {
+	ret := _m.Called(ctx, id, file, user)
+
+	var r0 *models.Picture
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader, string) *models.Picture); ok {
+		r0 = rf(ctx, id, file, user)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Picture)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader, string) error); ok {
+		r1 = rf(ctx, id, file, user)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
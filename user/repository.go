@@ -1,15 +1,36 @@
 package user
 
 import (
+	"time"
+
 	"github.com/models"
 	"golang.org/x/net/context"
 )
 
+// Repository represent the user's repository contract.
+// Fetch, GetByID, GetByUserEmail and GetByReferralCode only return users that are neither deleted nor inactive,
+// the IncludeDeleted variants return every row for the admin tooling and for the checks of the unique keys.
+// The referral code is set once, by Insert or by UpdateReferralCode for the users created before the codes were generated.
+// The points are only written by the points ledger through UpdatePoints, Insert starts them at zero and Update leaves them as is.
+// The profile picture is only written by UpdateProfilePictUrl once it is uploaded, Insert starts it empty.
 type Repository interface {
 	Fetch(ctx context.Context, cursor string, num int64) (res []*models.User, nextCursor string, err error)
+	FetchIncludeDeleted(ctx context.Context, cursor string, num int64) (res []*models.User, nextCursor string, err error)
 	GetByID(ctx context.Context, id string) (*models.User, error)
+	GetByIDIncludeDeleted(ctx context.Context, id string) (*models.User, error)
 	GetByUserEmail(ctx context.Context, userEmail string) (*models.User, error)
+	GetByUserEmailIncludeDeleted(ctx context.Context, userEmail string) (*models.User, error)
+	GetByReferralCode(ctx context.Context, code string) (*models.User, error)
+	GetByReferralCodeIncludeDeleted(ctx context.Context, code string) (*models.User, error)
 	Update(ctx context.Context, ar *models.User) error
 	Insert(ctx context.Context, a *models.User) error
-	Delete(ctx context.Context, id string,deleted_by string) error
-}
\ No newline at end of file
+	Delete(ctx context.Context, id string, deleted_by string) error
+	Restore(ctx context.Context, id string, modified_by string) error
+	GetPointsForUpdate(ctx context.Context, id string) (int64, error)
+	UpdatePoints(ctx context.Context, id string, points int64) error
+	UpdateReferralCode(ctx context.Context, id string, code string) error
+	UpdateProfilePictUrl(ctx context.Context, id string, url string, modified_by string) error
+	SetVerificationCode(ctx context.Context, id string, channel string, code int, sentAt time.Time) error
+	IncrementVerificationAttempts(ctx context.Context, id string, maxAttempts int) error
+	MarkVerified(ctx context.Context, id string, channel string) error
+}
@@ -4,18 +4,28 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"errors"
 	"fmt"
 
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/sirupsen/logrus"
 
 	"github.com/models"
+	"github.com/mysqlerr"
+	"github.com/transaction"
 	"github.com/user"
 )
 
 const (
 	timeFormat = "2006-01-02T15:04:05.999Z07:00" // reduce precision from RFC3339Nano as date format
+
+	// userColumns lists the columns in the order they are scanned by fetch
+	userColumns = `id, created_by, created_date, modified_by, modified_date, deleted_by, deleted_date, is_deleted, is_active, user_email, full_name, phone_number, verification_send_date, verification_code, verification_channel, verification_attempts, email_verified, phone_verified, profile_pict_url, address, dob, gender, id_type, id_number, referral_code, points`
+
+	// activeScope restricts a query to the rows that are neither soft deleted nor deactivated
+	activeScope = ` AND is_deleted = 0 AND is_active = 1`
 )
 
 type userRepository struct {
@@ -27,8 +37,13 @@ func NewuserRepository(Conn *sql.DB) user.Repository {
 	return &userRepository{Conn}
 }
 
+// conn will return the transaction of the unit of work ctx belongs to, or the connection pool
+func (m *userRepository) conn(ctx context.Context) transaction.DBTX {
+	return transaction.Conn(ctx, m.Conn)
+}
+
 func (m *userRepository) fetch(ctx context.Context, query string, args ...interface{}) ([]*models.User, error) {
-	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	rows, err := m.conn(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		logrus.Error(err)
 		return nil, err
@@ -59,6 +74,10 @@ func (m *userRepository) fetch(ctx context.Context, query string, args ...interf
 			&t.PhoneNumber,
 			&t.VerificationSendDate,
 			&t.VerificationCode,
+			&t.VerificationChannel,
+			&t.VerificationAttempts,
+			&t.EmailVerified,
+			&t.PhoneVerified,
 			&t.ProfilePictUrl,
 			&t.Address,
 			&t.Dob,
@@ -80,7 +99,15 @@ func (m *userRepository) fetch(ctx context.Context, query string, args ...interf
 }
 
 func (m *userRepository) Fetch(ctx context.Context, cursor string, num int64) ([]*models.User, string, error) {
-	query := `SELECT * FROM users WHERE created_at > ? ORDER BY created_at LIMIT ? `
+	return m.fetchPage(ctx, activeScope, cursor, num)
+}
+
+func (m *userRepository) FetchIncludeDeleted(ctx context.Context, cursor string, num int64) ([]*models.User, string, error) {
+	return m.fetchPage(ctx, "", cursor, num)
+}
+
+func (m *userRepository) fetchPage(ctx context.Context, scope string, cursor string, num int64) ([]*models.User, string, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE created_date > ?` + scope + ` ORDER BY created_date LIMIT ? `
 
 	decodedCursor, err := DecodeCursor(cursor)
 	if err != nil && cursor != "" {
@@ -99,46 +126,57 @@ func (m *userRepository) Fetch(ctx context.Context, cursor string, num int64) ([
 
 	return res, nextCursor, err
 }
-func (m *userRepository) GetByID(ctx context.Context, id string) (res *models.User, err error) {
-	query := `SELECT * FROM users WHERE id = ?`
 
-	list, err := m.fetch(ctx, query, id)
-	if err != nil {
-		return nil, err
-	}
+func (m *userRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	return m.getOne(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`+activeScope, id)
+}
 
-	if len(list) > 0 {
-		res = list[0]
-	} else {
-		return nil, models.ErrNotFound
-	}
+func (m *userRepository) GetByIDIncludeDeleted(ctx context.Context, id string) (*models.User, error) {
+	return m.getOne(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`, id)
+}
+
+func (m *userRepository) GetByUserEmail(ctx context.Context, userEmail string) (*models.User, error) {
+	return m.getOne(ctx, `SELECT `+userColumns+` FROM users WHERE user_email = ?`+activeScope, userEmail)
+}
+
+func (m *userRepository) GetByUserEmailIncludeDeleted(ctx context.Context, userEmail string) (*models.User, error) {
+	return m.getOne(ctx, `SELECT `+userColumns+` FROM users WHERE user_email = ?`, userEmail)
+}
 
-	return
+// GetByReferralCode will return the active user owning the referral code
+func (m *userRepository) GetByReferralCode(ctx context.Context, code string) (*models.User, error) {
+	return m.getOne(ctx, `SELECT `+userColumns+` FROM users WHERE referral_code = ?`+activeScope, code)
 }
-func (m *userRepository) GetByUserEmail(ctx context.Context, userEmail string) (res *models.User, err error) {
-	query := `SELECT * FROM users WHERE user_email = ?`
 
-	list, err := m.fetch(ctx, query, userEmail)
+// GetByReferralCodeIncludeDeleted will return the user owning the referral code, deleted or not
+func (m *userRepository) GetByReferralCodeIncludeDeleted(ctx context.Context, code string) (*models.User, error) {
+	return m.getOne(ctx, `SELECT `+userColumns+` FROM users WHERE referral_code = ?`, code)
+}
+
+func (m *userRepository) getOne(ctx context.Context, query string, args ...interface{}) (*models.User, error) {
+	list, err := m.fetch(ctx, query, args...)
 	if err != nil {
-		return
+		return nil, err
 	}
-
-	if len(list) > 0 {
-		res = list[0]
-	} else {
+	if len(list) == 0 {
 		return nil, models.ErrNotFound
 	}
-	return
+	return list[0], nil
 }
+
 func (m *userRepository) Insert(ctx context.Context, a *models.User) error {
-	query := `INSERT users SET id=? , created_by=? , created_date=? , modified_by=?, modified_date=? , deleted_by=? , deleted_date=? , is_deleted=? , is_active=? , user_email=? , full_name=? , phone_number=? ,verification_send_date=?,verification_code=?,profile_pict_url=?,address=?,dob=?,gender=?,id_type=?,id_number=?,referral_code=?,points=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	query := `INSERT users SET id=? , created_by=? , created_date=? , modified_by=?, modified_date=? , deleted_by=? , deleted_date=? , is_deleted=? , is_active=? , user_email=? , full_name=? , phone_number=? ,profile_pict_url='',address=?,dob=?,gender=?,id_type=?,id_number=?,referral_code=?,points=0`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
 	_, err = stmt.ExecContext(ctx, a.Id, a.CreatedBy, time.Now(), nil, nil, nil, nil, 0, 1, a.UserEmail, a.FullName,
-		a.PhoneNumber, a.VerificationSendDate,a.VerificationCode,a.ProfilePictUrl,a.Address,a.Dob,a.Gender,a.IdType,a.IdNumber,a.ReferralCode,a.Points)
+		a.PhoneNumber, a.Address, a.Dob, a.Gender, a.IdType, a.IdNumber, a.ReferralCode)
 	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlerr.DuplicateEntry {
+			return models.ErrConflict
+		}
 		return err
 	}
 
@@ -152,35 +190,59 @@ func (m *userRepository) Insert(ctx context.Context, a *models.User) error {
 }
 
 func (m *userRepository) Delete(ctx context.Context, id string, deleted_by string) error {
-	query := `UPDATE  users SET deleted_by=? , deleted_date=? , is_deleted=? , is_active=?`
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	query := `UPDATE  users SET deleted_by=? , deleted_date=? , is_deleted=? , is_active=? WHERE id = ? AND is_deleted = 0`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
 
-	_, err = stmt.ExecContext(ctx, deleted_by, time.Now(), 1, 0)
+	res, err := stmt.ExecContext(ctx, deleted_by, time.Now(), 1, 0, id)
 	if err != nil {
 		return err
 	}
 
-	//lastID, err := res.RowsAffected()
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+func (m *userRepository) Restore(ctx context.Context, id string, modified_by string) error {
+	query := `UPDATE users SET deleted_by=NULL , deleted_date=NULL , is_deleted=0 , is_active=1 , modified_by=? , modified_date=? WHERE id = ? AND is_deleted = 1`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
 	if err != nil {
 		return err
 	}
 
-	//a.Id = lastID
+	res, err := stmt.ExecContext(ctx, modified_by, time.Now(), id)
+	if err != nil {
+		return err
+	}
+
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrNotFound
+	}
 	return nil
 }
+
 func (m *userRepository) Update(ctx context.Context, a *models.User) error {
-	query := `UPDATE users set modified_by=?, modified_date=? , user_email=? , full_name=? , phone_number=? ,verification_send_date=?,verification_code=?,profile_pict_url=?,address=?,dob=?,gender=?,id_type=?,id_number=?,referral_code=?,points=? WHERE id = ?`
+	query := `UPDATE users set modified_by=?, modified_date=? , user_email=? , full_name=? , phone_number=? ,verification_code=?,verification_channel=?,verification_attempts=?,email_verified=?,phone_verified=?,address=?,dob=?,gender=?,id_type=?,id_number=? WHERE id = ?`
 
-	stmt, err := m.Conn.PrepareContext(ctx, query)
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
 	if err != nil {
-		return nil
+		return err
 	}
 
-	res, err := stmt.ExecContext(ctx, a.ModifiedBy, time.Now(),  a.UserEmail, a.FullName,
-		a.PhoneNumber, a.VerificationSendDate,a.VerificationCode,a.ProfilePictUrl,a.Address,a.Dob,a.Gender,a.IdType,a.IdNumber,a.ReferralCode,a.Points, a.Id)
+	res, err := stmt.ExecContext(ctx, a.ModifiedBy, time.Now(), a.UserEmail, a.FullName,
+		a.PhoneNumber, a.VerificationCode, a.VerificationChannel, a.VerificationAttempts, a.EmailVerified, a.PhoneVerified,
+		a.Address, a.Dob, a.Gender, a.IdType, a.IdNumber, a.Id)
 	if err != nil {
 		return err
 	}
@@ -197,6 +259,107 @@ func (m *userRepository) Update(ctx context.Context, a *models.User) error {
 	return nil
 }
 
+// GetPointsForUpdate will return the points of the user and lock its row until the unit of work of ctx ends,
+// so the points entries of a user are applied one after the other. Deleted users are included, their points keep expiring.
+func (m *userRepository) GetPointsForUpdate(ctx context.Context, id string) (int64, error) {
+	var points int64
+	err := m.conn(ctx).QueryRowContext(ctx, `SELECT points FROM users WHERE id = ? FOR UPDATE`, id).Scan(&points)
+	if err == sql.ErrNoRows {
+		return 0, models.ErrNotFound
+	}
+	if err != nil {
+		logrus.Error(err)
+		return 0, err
+	}
+	return points, nil
+}
+
+func (m *userRepository) UpdatePoints(ctx context.Context, id string, points int64) error {
+	query := `UPDATE users SET points=? WHERE id = ?`
+	return m.exec(ctx, query, points, id)
+}
+
+// UpdateReferralCode will give a referral code to a user that has none yet,
+// it fails with models.ErrConflict when the user already has a code or the code is taken
+func (m *userRepository) UpdateReferralCode(ctx context.Context, id string, code string) error {
+	query := `UPDATE users SET referral_code=? WHERE id = ? AND referral_code IS NULL`
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, code, id)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlerr.DuplicateEntry {
+			return models.ErrConflict
+		}
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrConflict
+	}
+	return nil
+}
+
+// UpdateProfilePictUrl will save the url of the uploaded profile picture of the user
+func (m *userRepository) UpdateProfilePictUrl(ctx context.Context, id string, url string, modified_by string) error {
+	query := `UPDATE users SET profile_pict_url=? , modified_by=? , modified_date=? WHERE id = ? AND is_deleted = 0`
+	return m.exec(ctx, query, url, modified_by, time.Now(), id)
+}
+
+// SetVerificationCode will replace the pending verification code of the user and reset its attempts
+func (m *userRepository) SetVerificationCode(ctx context.Context, id string, channel string, code int, sentAt time.Time) error {
+	query := `UPDATE users SET verification_code=? , verification_channel=? , verification_attempts=0 , verification_send_date=? WHERE id = ?`
+	return m.exec(ctx, query, code, channel, sentAt, id)
+}
+
+// IncrementVerificationAttempts will count an attempt at the pending verification code,
+// it fails with models.ErrConflict when the user already used maxAttempts attempts
+func (m *userRepository) IncrementVerificationAttempts(ctx context.Context, id string, maxAttempts int) error {
+	query := `UPDATE users SET verification_attempts = verification_attempts + 1 WHERE id = ? AND verification_attempts < ?`
+	err := m.exec(ctx, query, id, maxAttempts)
+	if errors.Is(err, models.ErrNotFound) {
+		return models.ErrConflict
+	}
+	return err
+}
+
+// MarkVerified will record that the email or the phone number of the user is verified and clear the pending code
+func (m *userRepository) MarkVerified(ctx context.Context, id string, channel string) error {
+	column := "email_verified"
+	if channel == models.VerificationSMS {
+		column = "phone_verified"
+	}
+	query := `UPDATE users SET ` + column + `=1 , verification_code=0 , verification_channel='' , verification_attempts=0 WHERE id = ?`
+	return m.exec(ctx, query, id)
+}
+
+// exec will run a statement that must change exactly one user, models.ErrNotFound is returned otherwise
+func (m *userRepository) exec(ctx context.Context, query string, args ...interface{}) error {
+	stmt, err := m.conn(ctx).PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	res, err := stmt.ExecContext(ctx, args...)
+	if err != nil {
+		return err
+	}
+	affect, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affect != 1 {
+		return models.ErrNotFound
+	}
+	return nil
+}
+
 // DecodeCursor will decode cursor from user for mysql
 func DecodeCursor(encodedTime string) (time.Time, error) {
 	byt, err := base64.StdEncoding.DecodeString(encodedTime)
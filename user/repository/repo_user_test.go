@@ -1 +1,147 @@
 package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/models"
+	userRepo "github.com/user/repository"
+)
+
+func TestDelete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "UPDATE  users SET deleted_by=\\? , deleted_date=\\? , is_deleted=\\? , is_active=\\? WHERE id = \\? AND is_deleted = 0"
+
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs("admin", sqlmock.AnyArg(), 1, 0, "u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	a := userRepo.NewuserRepository(db)
+	err = a.Delete(context.TODO(), "u1", "admin")
+	assert.NoError(t, err)
+
+	prep = mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs("admin", sqlmock.AnyArg(), 1, 0, "u2").WillReturnResult(sqlmock.NewResult(0, 0))
+	err = a.Delete(context.TODO(), "u2", "admin")
+	assert.Equal(t, models.ErrNotFound, err)
+}
+
+func TestFetchScope(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	a := userRepo.NewuserRepository(db)
+
+	mock.ExpectQuery("SELECT .+ FROM users WHERE created_date > \\? AND is_deleted = 0 AND is_active = 1 ORDER BY created_date LIMIT \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, _, err = a.Fetch(context.TODO(), "", 10)
+	assert.NoError(t, err)
+
+	mock.ExpectQuery("SELECT .+ FROM users WHERE created_date > \\? ORDER BY created_date LIMIT \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, _, err = a.FetchIncludeDeleted(context.TODO(), "", 10)
+	assert.NoError(t, err)
+}
+
+func TestUpdateReferralCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "UPDATE users SET referral_code=\\? WHERE id = \\? AND referral_code IS NULL"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("ABCD2345", "u1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("ABCD2345", "u2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("ABCD2345", "u3").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+
+	a := userRepo.NewuserRepository(db)
+	assert.NoError(t, a.UpdateReferralCode(context.TODO(), "u1", "ABCD2345"))
+	assert.Equal(t, models.ErrConflict, a.UpdateReferralCode(context.TODO(), "u2", "ABCD2345"))
+	assert.Equal(t, models.ErrConflict, a.UpdateReferralCode(context.TODO(), "u3", "ABCD2345"))
+}
+
+func TestUpdateProfilePictUrl(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "UPDATE users SET profile_pict_url=\\? , modified_by=\\? , modified_date=\\? WHERE id = \\? AND is_deleted = 0"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("/uploads/users/u1/avatar-512.jpg", "john@mail.com", sqlmock.AnyArg(), "u1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("/uploads/users/u2/avatar-512.jpg", "admin", sqlmock.AnyArg(), "u2").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := userRepo.NewuserRepository(db)
+	assert.NoError(t, a.UpdateProfilePictUrl(context.TODO(), "u1", "/uploads/users/u1/avatar-512.jpg", "john@mail.com"))
+	assert.Equal(t, models.ErrNotFound, a.UpdateProfilePictUrl(context.TODO(), "u2", "/uploads/users/u2/avatar-512.jpg", "admin"))
+}
+
+func TestIncrementVerificationAttempts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	query := "UPDATE users SET verification_attempts = verification_attempts \\+ 1 WHERE id = \\? AND verification_attempts < \\?"
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("u1", 5).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(query).ExpectExec().WithArgs("u1", 5).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	a := userRepo.NewuserRepository(db)
+	assert.NoError(t, a.IncrementVerificationAttempts(context.TODO(), "u1", 5))
+	assert.Equal(t, models.ErrConflict, a.IncrementVerificationAttempts(context.TODO(), "u1", 5))
+}
+
+func TestMarkVerified(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer func() {
+		mock.ExpectClose()
+		err = db.Close()
+		require.NoError(t, err)
+	}()
+
+	mock.ExpectPrepare("UPDATE users SET phone_verified=1 , verification_code=0").ExpectExec().WithArgs("u1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	a := userRepo.NewuserRepository(db)
+	assert.NoError(t, a.MarkVerified(context.TODO(), "u1", models.VerificationSMS))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
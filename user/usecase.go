@@ -1,6 +1,8 @@
 package user
 
 import (
+	"io"
+
 	"github.com/models"
 	"golang.org/x/net/context"
 )
@@ -8,7 +10,14 @@ import (
 type Usecase interface {
 	Update(ctx context.Context, ar *models.NewCommandUser, user string) error
 	Create(ctx context.Context, ar *models.NewCommandUser, user string) error
-	ValidateTokenUser(ctx context.Context, token string) (*string,error)
-	Login(ctx context.Context, ar *models.Login) (*models.GetToken,error)
-	GetUserInfo(ctx context.Context, token string) (*models.UserInfoDto,error)
+	ValidateTokenUser(ctx context.Context, token string) (*models.Principal, error)
+	Login(ctx context.Context, ar *models.Login) (*models.GetToken, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*models.GetToken, error)
+	Logout(ctx context.Context, principal *models.Principal, refreshToken string) error
+	GetUserInfo(ctx context.Context, token string) (*models.UserInfoDto, error)
+	GetByID(ctx context.Context, id string) (*models.UserInfoDto, error)
+	Fetch(ctx context.Context, cursor string, num int64, includeDeleted bool) ([]*models.UserInfoDto, string, error)
+	Delete(ctx context.Context, id string, user string) error
+	Restore(ctx context.Context, id string, user string) error
+	UploadProfilePicture(ctx context.Context, id string, file io.Reader, user string) (*models.Picture, error)
 }
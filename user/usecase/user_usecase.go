@@ -1,138 +1,383 @@
 package usecase
 
 import (
+	"errors"
+	"io"
+
+	"github.com/sirupsen/logrus"
+
 	"github.com/identityserver"
 	"github.com/models"
+	"github.com/picture"
+	"github.com/points"
+	"github.com/referral"
+	"github.com/transaction"
 	"github.com/user"
 	"golang.org/x/net/context"
 	"time"
 )
 
 type userUsecase struct {
-	userRepo   user.Repository
+	userRepo         user.Repository
 	identityServerUc identityserver.Usecase
-	contextTimeout time.Duration
+	tokenRepo        identityserver.Repository
+	referralUc       referral.Usecase
+	pointsUc         points.Usecase
+	pictureUploader  picture.Uploader
+	txManager        transaction.Manager
+	contextTimeout   time.Duration
 }
 
 // NewuserUsecase will create new an userUsecase object representation of user.Usecase interface
-func NewuserUsecase(a user.Repository, is identityserver.Usecase,timeout time.Duration) user.Usecase {
+func NewuserUsecase(a user.Repository, is identityserver.Usecase, tokenRepo identityserver.Repository, referralUc referral.Usecase, pointsUc points.Usecase, pictureUploader picture.Uploader, txManager transaction.Manager, timeout time.Duration) user.Usecase {
 	return &userUsecase{
-		userRepo:   a,
-		identityServerUc:is,
-		contextTimeout: timeout,
+		userRepo:         a,
+		identityServerUc: is,
+		tokenRepo:        tokenRepo,
+		referralUc:       referralUc,
+		pointsUc:         pointsUc,
+		pictureUploader:  pictureUploader,
+		txManager:        txManager,
+		contextTimeout:   timeout,
 	}
 }
 func (m userUsecase) Login(ctx context.Context, ar *models.Login) (*models.GetToken, error) {
 	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
 	defer cancel()
 
-	requestToken ,err:= m.identityServerUc.GetToken(ar.Email,ar.Password)
-	if err != nil{
-		return nil,err
+	requestToken, err := m.identityServerUc.GetToken(ctx, ar.Email, ar.Password)
+	if err != nil {
+		return nil, err
 	}
 	existeduser, _ := m.userRepo.GetByUserEmail(ctx, ar.Email)
 	if existeduser == nil {
-		return nil,models.ErrNotFound
+		return nil, models.ErrNotFound
+	}
+	if err := m.storeRefreshToken(ctx, requestToken.RefreshToken, existeduser.Id); err != nil {
+		return nil, err
+	}
+	return requestToken, err
+}
+
+func (m userUsecase) RefreshToken(ctx context.Context, refreshToken string) (*models.GetToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	stored, err := m.tokenRepo.GetRefreshToken(ctx, identityserver.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, models.ErrNotFound) {
+			return nil, models.ErrUnAuthorize
+		}
+		return nil, err
+	}
+	if stored.IsRevoked == 1 || stored.AccountType != models.PrincipalUser {
+		return nil, models.ErrUnAuthorize
+	}
+	if _, err := m.userRepo.GetByID(ctx, stored.AccountId); err != nil {
+		return nil, models.ErrUnAuthorize
+	}
+
+	requestToken, err := m.identityServerUc.RefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	// the identity server may rotate the refresh token, the previous one must not be accepted again
+	if requestToken.RefreshToken != "" && requestToken.RefreshToken != refreshToken {
+		if err := m.tokenRepo.RevokeRefreshToken(ctx, stored.TokenHash); err != nil {
+			return nil, err
+		}
+		if err := m.storeRefreshToken(ctx, requestToken.RefreshToken, stored.AccountId); err != nil {
+			return nil, err
+		}
 	}
-	return  requestToken,err
+	return requestToken, nil
 }
 
-func (m userUsecase) ValidateTokenUser(ctx context.Context, token string) (*string, error) {
+func (m userUsecase) Logout(ctx context.Context, principal *models.Principal, refreshToken string) error {
 	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
 	defer cancel()
 
-	getInfoToIs ,err := m.identityServerUc.GetUserInfo(token)
-	if err != nil{
-		return nil,err
+	if refreshToken != "" {
+		stored, err := m.tokenRepo.GetRefreshToken(ctx, identityserver.HashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				return models.ErrBadParamInput
+			}
+			return err
+		}
+		if stored.AccountId != principal.Id || stored.AccountType != models.PrincipalUser {
+			return models.ErrForbidden
+		}
+		if err := m.identityServerUc.RevokeToken(ctx, refreshToken, identityserver.TokenTypeRefresh); err != nil {
+			return err
+		}
+		if err := m.tokenRepo.RevokeRefreshToken(ctx, stored.TokenHash); err != nil {
+			return err
+		}
+	}
+
+	err := m.identityServerUc.RevokeToken(ctx, principal.Token, identityserver.TokenTypeAccess)
+	var validationErr *identityserver.ValidationError
+	if errors.As(err, &validationErr) {
+		// self-contained access tokens can not be revoked, they expire on their own
+		return nil
+	}
+	return err
+}
+
+func (m userUsecase) storeRefreshToken(ctx context.Context, refreshToken string, accountId string) error {
+	if refreshToken == "" {
+		return nil
+	}
+	return m.tokenRepo.StoreRefreshToken(ctx, &models.RefreshToken{
+		TokenHash:   identityserver.HashToken(refreshToken),
+		AccountId:   accountId,
+		AccountType: models.PrincipalUser,
+		CreatedDate: time.Now(),
+	})
+}
+
+func (m userUsecase) ValidateTokenUser(ctx context.Context, token string) (*models.Principal, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
+	defer cancel()
+
+	getInfoToIs, err := m.identityServerUc.GetUserInfo(ctx, token)
+	if err != nil {
+		return nil, err
 	}
 	existeduser, _ := m.userRepo.GetByUserEmail(ctx, getInfoToIs.Email)
 	if existeduser == nil {
-		return nil,models.ErrNotFound
+		return nil, models.ErrNotFound
 	}
-	currentUser := getInfoToIs.Username
-	return &currentUser,nil
+	return &models.Principal{
+		Id:       existeduser.Id,
+		Username: getInfoToIs.Username,
+		Email:    getInfoToIs.Email,
+		Type:     models.PrincipalUser,
+		Token:    token,
+	}, nil
 }
 
 func (m userUsecase) GetUserInfo(ctx context.Context, token string) (*models.UserInfoDto, error) {
 	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
 	defer cancel()
 
-	getInfoToIs ,err := m.identityServerUc.GetUserInfo(token)
-	if err != nil{
-		return nil,err
+	getInfoToIs, err := m.identityServerUc.GetUserInfo(ctx, token)
+	if err != nil {
+		return nil, err
 	}
 	existeduser, _ := m.userRepo.GetByUserEmail(ctx, getInfoToIs.Email)
 	if existeduser == nil {
-		return nil,models.ErrNotFound
+		return nil, models.ErrNotFound
 	}
-	userInfo := models.UserInfoDto{
-		Id:             existeduser.Id,
-		UserEmail:      existeduser.UserEmail,
-		FullName:       existeduser.FullName,
-		PhoneNumber:    existeduser.PhoneNumber,
-		ProfilePictUrl: existeduser.ProfilePictUrl,
+
+	return toUserInfoDto(existeduser), nil
+}
+
+func (m userUsecase) GetByID(c context.Context, id string) (*models.UserInfoDto, error) {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	res, err := m.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toUserInfoDto(res), nil
+}
+
+// Fetch will list the users, deleted and inactive users are only listed when includeDeleted is set
+func (m userUsecase) Fetch(c context.Context, cursor string, num int64, includeDeleted bool) ([]*models.UserInfoDto, string, error) {
+	if num == 0 {
+		num = 10
+	}
+
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	fetch := m.userRepo.Fetch
+	if includeDeleted {
+		fetch = m.userRepo.FetchIncludeDeleted
+	}
+	listUser, nextCursor, err := fetch(ctx, cursor, num)
+	if err != nil {
+		return nil, "", err
 	}
 
-	return &userInfo,nil
+	result := make([]*models.UserInfoDto, len(listUser))
+	for i, u := range listUser {
+		result[i] = toUserInfoDto(u)
+	}
+	return result, nextCursor, nil
 }
 
+// Delete will soft delete the user and disable its account at the identity server
+// The account is enabled again when the user can not be deleted.
+func (m userUsecase) Delete(c context.Context, id string, user string) error {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	existeduser, err := m.userRepo.GetByIDIncludeDeleted(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existeduser.IsDeleted == 1 {
+		return models.ErrNotFound
+	}
+	if err := m.identityServerUc.DisableUser(ctx, id); err != nil {
+		return err
+	}
+	err = m.userRepo.Delete(ctx, id, user)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		// not found means it was deleted meanwhile, its account stays disabled
+		identityserver.EnableAccount(m.identityServerUc, id, m.contextTimeout)
+	}
+	return err
+}
 
-func (m userUsecase) Update(c context.Context, ar *models.NewCommandUser ,user string) error {
+// Restore will undo the soft delete of the user and enable its account at the identity server again
+func (m userUsecase) Restore(c context.Context, id string, user string) error {
 	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
 	defer cancel()
 
-	updateUser := models.RegisterAndUpdateUser{
-		Id:            ar.Id,
-		Username:      ar.UserEmail,
-		Password:      ar.Password,
-		Name:          ar.FullName,
-		GivenName:     "",
-		FamilyName:    "",
-		Email:         ar.UserEmail,
-		EmailVerified: false,
-		Website:       "",
-		Address:       "",
+	existeduser, err := m.userRepo.GetByIDIncludeDeleted(ctx, id)
+	if err != nil {
+		return err
 	}
-	_ ,err:= m.identityServerUc.UpdateUser(&updateUser)
-	if err != nil{
+	if existeduser.IsDeleted == 0 {
+		return nil
+	}
+	if err := m.identityServerUc.EnableUser(ctx, id); err != nil {
 		return err
 	}
-	layoutFormat := "2006-01-02 15:04:05"
-	verificationSendDate, errDate := time.Parse(layoutFormat,ar.VerificationSendDate)
-	if errDate != nil{
-		return errDate
+	return m.userRepo.Restore(ctx, id, user)
+}
+
+// Update will update the profile of the user at the identity server and locally.
+// Changing the email address or the phone number drops its verification and the pending code.
+// The password and the profile picture are left as is, they change through the password usecase and UploadProfilePicture.
+func (m userUsecase) Update(c context.Context, ar *models.NewCommandUser, user string) error {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	existedUser, err := m.userRepo.GetByID(ctx, ar.Id)
+	if err != nil {
+		return err
 	}
-	dob , errDateDob := time.Parse(layoutFormat,ar.Dob)
-	if errDateDob != nil{
+	layoutFormat := "2006-01-02 15:04:05"
+	dob, errDateDob := time.Parse(layoutFormat, ar.Dob)
+	if errDateDob != nil {
 		return errDateDob
 	}
 
 	userModel := models.User{}
 	userModel.Id = ar.Id
-	userModel.ModifiedBy = &ar.UserEmail
+	userModel.ModifiedBy = &user
 	userModel.UserEmail = ar.UserEmail
 	userModel.FullName = ar.FullName
 	userModel.PhoneNumber = ar.PhoneNumber
-	userModel.VerificationSendDate = verificationSendDate
-	userModel.VerificationCode =ar.VerificationCode
-	userModel.ProfilePictUrl = ar.ProfilePictUrl
+	userModel.VerificationCode = existedUser.VerificationCode
+	userModel.VerificationChannel = existedUser.VerificationChannel
+	userModel.VerificationAttempts = existedUser.VerificationAttempts
+	userModel.EmailVerified = existedUser.EmailVerified
+	userModel.PhoneVerified = existedUser.PhoneVerified
+	if existedUser.UserEmail != ar.UserEmail {
+		userModel.EmailVerified = 0
+		clearPendingCode(&userModel, models.VerificationEmail)
+	}
+	if existedUser.PhoneNumber != ar.PhoneNumber {
+		userModel.PhoneVerified = 0
+		clearPendingCode(&userModel, models.VerificationSMS)
+	}
 	userModel.Address = ar.Address
 	userModel.Dob = dob
 	userModel.Gender = ar.Gender
 	userModel.IdType = ar.IdType
 	userModel.IdNumber = ar.IdNumber
-	userModel.ReferralCode = ar.ReferralCode
-	userModel.Points = ar.Points
+
+	updateUser := models.RegisterAndUpdateUser{
+		Id:            ar.Id,
+		Username:      ar.UserEmail,
+		Name:          ar.FullName,
+		GivenName:     "",
+		FamilyName:    "",
+		Email:         ar.UserEmail,
+		EmailVerified: userModel.EmailVerified == 1,
+		Website:       "",
+		Address:       "",
+	}
+	_, err = m.identityServerUc.UpdateUser(ctx, &updateUser)
+	if err != nil {
+		return err
+	}
 	return m.userRepo.Update(ctx, &userModel)
 }
 
-func (m userUsecase) Create(c context.Context, ar *models.NewCommandUser,user string) error {
+// UploadProfilePicture will store the thumbnails of the uploaded picture and save the url of the largest one on the user.
+// The thumbnails of the previous picture are deleted.
+func (m userUsecase) UploadProfilePicture(c context.Context, id string, file io.Reader, user string) (*models.Picture, error) {
 	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
 	defer cancel()
-	existeduser, _ := m.userRepo.GetByUserEmail(ctx, ar.UserEmail)
+
+	existed, err := m.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	prefix := "users/" + id + "/avatar"
+	res, err := m.pictureUploader.Upload(ctx, prefix, file)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.userRepo.UpdateProfilePictUrl(ctx, id, res.Url, user); err != nil {
+		if res.Url != existed.ProfilePictUrl {
+			m.removePicture(ctx, prefix, res.Url)
+		}
+		return nil, err
+	}
+	if existed.ProfilePictUrl != "" && existed.ProfilePictUrl != res.Url {
+		m.removePicture(ctx, prefix, existed.ProfilePictUrl)
+	}
+	return res, nil
+}
+
+// removePicture will delete the thumbnails of a picture nobody points to, a failure only leaves files behind
+func (m userUsecase) removePicture(ctx context.Context, prefix string, url string) {
+	if err := m.pictureUploader.Remove(ctx, prefix, url); err != nil {
+		logrus.WithError(err).WithField("url", url).Warn("remove the thumbnails of a replaced picture")
+	}
+}
+
+// clearPendingCode will drop the pending verification code when it was sent through the given channel
+func clearPendingCode(u *models.User, channel string) {
+	if u.VerificationChannel != channel {
+		return
+	}
+	u.VerificationCode = 0
+	u.VerificationChannel = ""
+	u.VerificationAttempts = 0
+}
+
+// Create will register the account at the identity server and store the user with a generated referral code.
+// The points of the registration event and, when a referrer code is given, the referral are recorded in the same
+// transaction as the user.
+func (m userUsecase) Create(c context.Context, ar *models.NewCommandUser, user string) error {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+	existeduser, _ := m.userRepo.GetByUserEmailIncludeDeleted(ctx, ar.UserEmail)
 	if existeduser != nil {
 		return models.ErrConflict
 	}
+	if ar.ReferrerCode != "" {
+		if _, err := m.userRepo.GetByReferralCode(ctx, referral.NormalizeCode(ar.ReferrerCode)); err != nil {
+			if errors.Is(err, models.ErrNotFound) {
+				return referral.ErrUnknownCode
+			}
+			return err
+		}
+	}
+	referralCode, err := referral.GenerateCode(ctx, m.userRepo)
+	if err != nil {
+		return err
+	}
 	registerUser := models.RegisterAndUpdateUser{
 		Id:            "",
 		Username:      ar.UserEmail,
@@ -145,50 +390,81 @@ func (m userUsecase) Create(c context.Context, ar *models.NewCommandUser,user st
 		Website:       "",
 		Address:       "",
 	}
-	isUser ,errorIs:= m.identityServerUc.CreateUser(&registerUser)
-	ar.Id = isUser.Id
 	layoutFormat := "2006-01-02 15:04:05"
-	verificationSendDate, errDate := time.Parse(layoutFormat,ar.VerificationSendDate)
-	if errDate != nil{
-		return errDate
-	}
-	dob , errDateDob := time.Parse(layoutFormat,ar.Dob)
-	if errDateDob != nil{
+	dob, errDateDob := time.Parse(layoutFormat, ar.Dob)
+	if errDateDob != nil {
 		return errDateDob
 	}
 
-	if errorIs != nil{
+	isUser, errorIs := m.identityServerUc.CreateUser(ctx, &registerUser)
+	if errorIs != nil {
 		return errorIs
 	}
+	ar.Id = isUser.Id
 	userModel := models.User{}
 	userModel.Id = isUser.Id
-	userModel.CreatedBy = ar.UserEmail
+	userModel.CreatedBy = user
 	userModel.UserEmail = ar.UserEmail
 	userModel.FullName = ar.FullName
 	userModel.PhoneNumber = ar.PhoneNumber
-	userModel.VerificationSendDate = verificationSendDate
-	userModel.VerificationCode =ar.VerificationCode
-	userModel.ProfilePictUrl = ar.ProfilePictUrl
 	userModel.Address = ar.Address
 	userModel.Dob = dob
 	userModel.Gender = ar.Gender
 	userModel.IdType = ar.IdType
 	userModel.IdNumber = ar.IdNumber
-	userModel.ReferralCode = ar.ReferralCode
-	userModel.Points = ar.Points
-	err := m.userRepo.Insert(ctx, &userModel)
+	userModel.ReferralCode = &referralCode
+	err = m.txManager.Do(ctx, func(ctx context.Context) error {
+		if err := m.userRepo.Insert(ctx, &userModel); err != nil {
+			return err
+		}
+		_, err := m.pointsUc.Earn(ctx, &models.PointsEvent{
+			UserId:    userModel.Id,
+			Event:     models.EventRegistration,
+			Reference: "registration-" + userModel.Id,
+		}, user)
+		// without an earning rule the registration is worth no points
+		if err != nil && !errors.Is(err, models.ErrBadParamInput) {
+			return err
+		}
+		if ar.ReferrerCode == "" {
+			return nil
+		}
+		_, err = m.referralUc.Refer(ctx, userModel.Id, ar.ReferrerCode)
+		return err
+	})
 	if err != nil {
+		identityserver.RemoveAccount(m.identityServerUc, isUser.Id, m.contextTimeout)
 		return err
 	}
 
-
 	return nil
 }
 
+func toUserInfoDto(u *models.User) *models.UserInfoDto {
+	return &models.UserInfoDto{
+		Id:             u.Id,
+		UserEmail:      u.UserEmail,
+		FullName:       u.FullName,
+		PhoneNumber:    u.PhoneNumber,
+		ProfilePictUrl: u.ProfilePictUrl,
+		ReferralCode:   referralCode(u),
+		Points:         u.Points,
+		EmailVerified:  u.EmailVerified,
+		PhoneVerified:  u.PhoneVerified,
+		IsActive:       u.IsActive,
+		IsDeleted:      u.IsDeleted,
+	}
+}
+
+func referralCode(u *models.User) string {
+	if u.ReferralCode == nil {
+		return ""
+	}
+	return *u.ReferralCode
+}
 
 /*
 * In this function below, I'm using errgroup with the pipeline pattern
 * Look how this works in this package explanation
 * in godoc: https://godoc.org/golang.org/x/sync/errgroup#ex-Group--Pipeline
  */
-
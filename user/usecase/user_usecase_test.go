@@ -1 +1,461 @@
 package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/identityserver"
+	_isMock "github.com/identityserver/mocks"
+	"github.com/models"
+	_pictureMock "github.com/picture/mocks"
+	_pointsMock "github.com/points/mocks"
+	"github.com/referral"
+	_referralMock "github.com/referral/mocks"
+	_txMock "github.com/transaction/mocks"
+	"github.com/user/mocks"
+	ucase "github.com/user/usecase"
+)
+
+func TestRefreshToken(t *testing.T) {
+	oldHash := identityserver.HashToken("old-refresh")
+
+	t.Run("rotated", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockTokenRepo := new(_isMock.Repository)
+
+		mockTokenRepo.On("GetRefreshToken", mock.Anything, oldHash).
+			Return(&models.RefreshToken{TokenHash: oldHash, AccountId: "u1", AccountType: models.PrincipalUser}, nil).Once()
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(&models.User{Id: "u1"}, nil).Once()
+		mockIs.On("RefreshToken", mock.Anything, "old-refresh").
+			Return(&models.GetToken{AccessToken: "access", RefreshToken: "new-refresh"}, nil).Once()
+		mockTokenRepo.On("RevokeRefreshToken", mock.Anything, oldHash).Return(nil).Once()
+		mockTokenRepo.On("StoreRefreshToken", mock.Anything, mock.MatchedBy(func(token *models.RefreshToken) bool {
+			return token.TokenHash == identityserver.HashToken("new-refresh") && token.AccountId == "u1"
+		})).Return(nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, mockTokenRepo, new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		token, err := u.RefreshToken(context.TODO(), "old-refresh")
+		require.NoError(t, err)
+		assert.Equal(t, "new-refresh", token.RefreshToken)
+
+		mockUserRepo.AssertExpectations(t)
+		mockIs.AssertExpectations(t)
+		mockTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		mockIs := new(_isMock.Usecase)
+		mockTokenRepo := new(_isMock.Repository)
+		mockTokenRepo.On("GetRefreshToken", mock.Anything, oldHash).
+			Return(&models.RefreshToken{TokenHash: oldHash, AccountId: "u1", AccountType: models.PrincipalUser, IsRevoked: 1}, nil).Once()
+
+		u := ucase.NewuserUsecase(new(mocks.Repository), mockIs, mockTokenRepo, new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		_, err := u.RefreshToken(context.TODO(), "old-refresh")
+		assert.Equal(t, models.ErrUnAuthorize, err)
+		mockIs.AssertNotCalled(t, "RefreshToken", mock.Anything, mock.Anything)
+	})
+
+	t.Run("merchant-token", func(t *testing.T) {
+		mockTokenRepo := new(_isMock.Repository)
+		mockTokenRepo.On("GetRefreshToken", mock.Anything, oldHash).
+			Return(&models.RefreshToken{TokenHash: oldHash, AccountId: "m1", AccountType: models.PrincipalMerchant}, nil).Once()
+
+		u := ucase.NewuserUsecase(new(mocks.Repository), new(_isMock.Usecase), mockTokenRepo, new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		_, err := u.RefreshToken(context.TODO(), "old-refresh")
+		assert.Equal(t, models.ErrUnAuthorize, err)
+	})
+}
+
+func TestLogout(t *testing.T) {
+	hash := identityserver.HashToken("refresh")
+	principal := &models.Principal{Id: "u1", Type: models.PrincipalUser, Token: "access"}
+
+	t.Run("success", func(t *testing.T) {
+		mockIs := new(_isMock.Usecase)
+		mockTokenRepo := new(_isMock.Repository)
+		mockTokenRepo.On("GetRefreshToken", mock.Anything, hash).
+			Return(&models.RefreshToken{TokenHash: hash, AccountId: "u1", AccountType: models.PrincipalUser}, nil).Once()
+		mockIs.On("RevokeToken", mock.Anything, "refresh", identityserver.TokenTypeRefresh).Return(nil).Once()
+		mockTokenRepo.On("RevokeRefreshToken", mock.Anything, hash).Return(nil).Once()
+		mockIs.On("RevokeToken", mock.Anything, "access", identityserver.TokenTypeAccess).
+			Return(&identityserver.ValidationError{Message: "unsupported_token_type"}).Once()
+
+		u := ucase.NewuserUsecase(new(mocks.Repository), mockIs, mockTokenRepo, new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		err := u.Logout(context.TODO(), principal, "refresh")
+		assert.NoError(t, err)
+
+		mockIs.AssertExpectations(t)
+		mockTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("token-of-another-user", func(t *testing.T) {
+		mockIs := new(_isMock.Usecase)
+		mockTokenRepo := new(_isMock.Repository)
+		mockTokenRepo.On("GetRefreshToken", mock.Anything, hash).
+			Return(&models.RefreshToken{TokenHash: hash, AccountId: "u2", AccountType: models.PrincipalUser}, nil).Once()
+
+		u := ucase.NewuserUsecase(new(mocks.Repository), mockIs, mockTokenRepo, new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		err := u.Logout(context.TODO(), principal, "refresh")
+		assert.Equal(t, models.ErrForbidden, err)
+		mockIs.AssertNotCalled(t, "RevokeToken", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockUserRepo.On("GetByIDIncludeDeleted", mock.Anything, "u1").Return(&models.User{Id: "u1"}, nil).Once()
+		mockIs.On("DisableUser", mock.Anything, "u1").Return(nil).Once()
+		mockUserRepo.On("Delete", mock.Anything, "u1", "admin").Return(nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		err := u.Delete(context.TODO(), "u1", "admin")
+		assert.NoError(t, err)
+
+		mockUserRepo.AssertExpectations(t)
+		mockIs.AssertExpectations(t)
+	})
+
+	t.Run("already-deleted", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockUserRepo.On("GetByIDIncludeDeleted", mock.Anything, "u1").Return(&models.User{Id: "u1", IsDeleted: 1}, nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		err := u.Delete(context.TODO(), "u1", "admin")
+		assert.Equal(t, models.ErrNotFound, err)
+		mockIs.AssertNotCalled(t, "DisableUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("identity-server-failure", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockUserRepo.On("GetByIDIncludeDeleted", mock.Anything, "u1").Return(&models.User{Id: "u1"}, nil).Once()
+		mockIs.On("DisableUser", mock.Anything, "u1").Return(identityserver.ErrUnreachable).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		err := u.Delete(context.TODO(), "u1", "admin")
+		assert.Equal(t, identityserver.ErrUnreachable, err)
+		mockUserRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed-delete", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockUserRepo.On("GetByIDIncludeDeleted", mock.Anything, "u1").Return(&models.User{Id: "u1"}, nil).Once()
+		mockIs.On("DisableUser", mock.Anything, "u1").Return(nil).Once()
+		mockUserRepo.On("Delete", mock.Anything, "u1", "admin").Return(errors.New("Unexpected Error")).Once()
+		mockIs.On("EnableUser", mock.Anything, "u1").Return(nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		err := u.Delete(context.TODO(), "u1", "admin")
+		assert.Error(t, err)
+		mockIs.AssertExpectations(t)
+	})
+
+	t.Run("deleted-meanwhile", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockUserRepo.On("GetByIDIncludeDeleted", mock.Anything, "u1").Return(&models.User{Id: "u1"}, nil).Once()
+		mockIs.On("DisableUser", mock.Anything, "u1").Return(nil).Once()
+		mockUserRepo.On("Delete", mock.Anything, "u1", "admin").Return(models.ErrNotFound).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		err := u.Delete(context.TODO(), "u1", "admin")
+		assert.Equal(t, models.ErrNotFound, err)
+		mockIs.AssertNotCalled(t, "EnableUser", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRestore(t *testing.T) {
+	t.Run("deleted", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockUserRepo.On("GetByIDIncludeDeleted", mock.Anything, "u1").Return(&models.User{Id: "u1", IsDeleted: 1}, nil).Once()
+		mockIs.On("EnableUser", mock.Anything, "u1").Return(nil).Once()
+		mockUserRepo.On("Restore", mock.Anything, "u1", "admin").Return(nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		err := u.Restore(context.TODO(), "u1", "admin")
+		assert.NoError(t, err)
+
+		mockUserRepo.AssertExpectations(t)
+		mockIs.AssertExpectations(t)
+	})
+
+	t.Run("not-deleted", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockUserRepo.On("GetByIDIncludeDeleted", mock.Anything, "u1").Return(&models.User{Id: "u1"}, nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		err := u.Restore(context.TODO(), "u1", "admin")
+		assert.NoError(t, err)
+		mockIs.AssertNotCalled(t, "EnableUser", mock.Anything, mock.Anything)
+		mockUserRepo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	existed := func() *models.User {
+		return &models.User{
+			Id:                  "u1",
+			UserEmail:           "john@mail.com",
+			PhoneNumber:         "+6281234567890",
+			EmailVerified:       1,
+			PhoneVerified:       1,
+			VerificationCode:    123456,
+			VerificationChannel: models.VerificationEmail,
+		}
+	}
+
+	t.Run("email-changed", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(existed(), nil).Once()
+		mockIs.On("UpdateUser", mock.Anything, mock.MatchedBy(func(ar *models.RegisterAndUpdateUser) bool {
+			return ar.Email == "johnny@mail.com" && !ar.EmailVerified
+		})).Return(&models.RegisterAndUpdateUser{}, nil).Once()
+		mockUserRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return u.EmailVerified == 0 && u.PhoneVerified == 1 && u.VerificationCode == 0 && u.VerificationChannel == ""
+		})).Return(nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		err := u.Update(context.TODO(), &models.NewCommandUser{
+			Id: "u1", UserEmail: "johnny@mail.com", PhoneNumber: "+6281234567890", Dob: "1990-01-02 00:00:00",
+		}, "john@mail.com")
+		require.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockIs.AssertExpectations(t)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(existed(), nil).Once()
+		mockIs.On("UpdateUser", mock.Anything, mock.MatchedBy(func(ar *models.RegisterAndUpdateUser) bool {
+			return ar.EmailVerified
+		})).Return(&models.RegisterAndUpdateUser{}, nil).Once()
+		mockUserRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return u.EmailVerified == 1 && u.PhoneVerified == 1 && u.VerificationCode == 123456
+		})).Return(nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		err := u.Update(context.TODO(), &models.NewCommandUser{
+			Id: "u1", UserEmail: "john@mail.com", PhoneNumber: "+6281234567890", Dob: "1990-01-02 00:00:00",
+		}, "john@mail.com")
+		require.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
+func TestCreate(t *testing.T) {
+	newCommand := func() *models.NewCommandUser {
+		return &models.NewCommandUser{
+			UserEmail: "john@mail.com",
+			Password:  "secret",
+			FullName:  "John",
+			Dob:       "1990-01-02 00:00:00",
+		}
+	}
+	inTransaction := func(ctx context.Context, fn func(context.Context) error) error { return fn(ctx) }
+	registration := &models.PointsEvent{UserId: "u1", Event: models.EventRegistration, Reference: "registration-u1"}
+	// earned will answer the registration points in the transaction, with the given error
+	earned := func(err error) *_pointsMock.Usecase {
+		mockPointsUc := new(_pointsMock.Usecase)
+		mockPointsUc.On("Earn", mock.Anything, registration, "admin").Return(&models.PointsEntry{Points: 100}, err).Once()
+		return mockPointsUc
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockTx := new(_txMock.Manager)
+		mockUserRepo.On("GetByUserEmailIncludeDeleted", mock.Anything, "john@mail.com").Return(nil, models.ErrNotFound).Once()
+		mockUserRepo.On("GetByReferralCodeIncludeDeleted", mock.Anything, mock.AnythingOfType("string")).Return(nil, models.ErrNotFound).Once()
+		mockIs.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.RegisterAndUpdateUser")).
+			Return(&models.RegisterAndUpdateUser{Id: "u1"}, nil).Once()
+		mockTx.On("Do", mock.Anything, mock.Anything).Return(inTransaction).Once()
+		mockUserRepo.On("Insert", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return u.Id == "u1" && u.ReferralCode != nil && len(*u.ReferralCode) == 8
+		})).Return(nil).Once()
+		mockPointsUc := earned(nil)
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), mockPointsUc, new(_pictureMock.Uploader), mockTx, time.Second*2)
+		ar := newCommand()
+		require.NoError(t, u.Create(context.TODO(), ar, "admin"))
+		assert.Equal(t, "u1", ar.Id)
+		mockUserRepo.AssertExpectations(t)
+		mockTx.AssertExpectations(t)
+		mockPointsUc.AssertExpectations(t)
+		mockIs.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("without-registration-rule", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockTx := new(_txMock.Manager)
+		mockUserRepo.On("GetByUserEmailIncludeDeleted", mock.Anything, "john@mail.com").Return(nil, models.ErrNotFound).Once()
+		mockUserRepo.On("GetByReferralCodeIncludeDeleted", mock.Anything, mock.AnythingOfType("string")).Return(nil, models.ErrNotFound).Once()
+		mockIs.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.RegisterAndUpdateUser")).
+			Return(&models.RegisterAndUpdateUser{Id: "u1"}, nil).Once()
+		mockTx.On("Do", mock.Anything, mock.Anything).Return(inTransaction).Once()
+		mockUserRepo.On("Insert", mock.Anything, mock.Anything).Return(nil).Once()
+		mockPointsUc := new(_pointsMock.Usecase)
+		mockPointsUc.On("Earn", mock.Anything, registration, "admin").Return(nil, models.ErrBadParamInput).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), mockPointsUc, new(_pictureMock.Uploader), mockTx, time.Second*2)
+		require.NoError(t, u.Create(context.TODO(), newCommand(), "admin"))
+		mockPointsUc.AssertExpectations(t)
+		mockIs.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("earn-failed", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockTx := new(_txMock.Manager)
+		mockUserRepo.On("GetByUserEmailIncludeDeleted", mock.Anything, "john@mail.com").Return(nil, models.ErrNotFound).Once()
+		mockUserRepo.On("GetByReferralCodeIncludeDeleted", mock.Anything, mock.AnythingOfType("string")).Return(nil, models.ErrNotFound).Once()
+		mockIs.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.RegisterAndUpdateUser")).
+			Return(&models.RegisterAndUpdateUser{Id: "u1"}, nil).Once()
+		mockTx.On("Do", mock.Anything, mock.Anything).Return(inTransaction).Once()
+		mockUserRepo.On("Insert", mock.Anything, mock.Anything).Return(nil).Once()
+		mockIs.On("DeleteUser", mock.Anything, "u1").Return(nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), earned(models.ErrInternalServerError), new(_pictureMock.Uploader), mockTx, time.Second*2)
+		err := u.Create(context.TODO(), newCommand(), "admin")
+		assert.Equal(t, models.ErrInternalServerError, err)
+		mockIs.AssertExpectations(t)
+	})
+
+	t.Run("insert-failed", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockTx := new(_txMock.Manager)
+		mockUserRepo.On("GetByUserEmailIncludeDeleted", mock.Anything, "john@mail.com").Return(nil, models.ErrNotFound).Once()
+		mockUserRepo.On("GetByReferralCodeIncludeDeleted", mock.Anything, mock.AnythingOfType("string")).Return(nil, models.ErrNotFound).Once()
+		mockIs.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.RegisterAndUpdateUser")).
+			Return(&models.RegisterAndUpdateUser{Id: "u1"}, nil).Once()
+		mockTx.On("Do", mock.Anything, mock.Anything).Return(inTransaction).Once()
+		mockUserRepo.On("Insert", mock.Anything, mock.Anything).Return(models.ErrInternalServerError).Once()
+		mockIs.On("DeleteUser", mock.Anything, "u1").Return(nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), mockTx, time.Second*2)
+		err := u.Create(context.TODO(), newCommand(), "admin")
+		assert.Equal(t, models.ErrInternalServerError, err)
+		mockIs.AssertExpectations(t)
+		mockIs.AssertNotCalled(t, "DisableUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("delete-refused", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockTx := new(_txMock.Manager)
+		mockUserRepo.On("GetByUserEmailIncludeDeleted", mock.Anything, "john@mail.com").Return(nil, models.ErrNotFound).Once()
+		mockUserRepo.On("GetByReferralCodeIncludeDeleted", mock.Anything, mock.AnythingOfType("string")).Return(nil, models.ErrNotFound).Once()
+		mockIs.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.RegisterAndUpdateUser")).
+			Return(&models.RegisterAndUpdateUser{Id: "u1"}, nil).Once()
+		mockTx.On("Do", mock.Anything, mock.Anything).Return(models.ErrInternalServerError).Once()
+		mockIs.On("DeleteUser", mock.Anything, "u1").Return(models.ErrUnAuthorize).Once()
+		mockIs.On("DisableUser", mock.Anything, "u1").Return(nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), mockTx, time.Second*2)
+		err := u.Create(context.TODO(), newCommand(), "admin")
+		assert.Equal(t, models.ErrInternalServerError, err)
+		mockIs.AssertExpectations(t)
+	})
+
+	t.Run("with-referrer", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockTx := new(_txMock.Manager)
+		mockReferralUc := new(_referralMock.Usecase)
+		mockUserRepo.On("GetByUserEmailIncludeDeleted", mock.Anything, "john@mail.com").Return(nil, models.ErrNotFound).Once()
+		mockUserRepo.On("GetByReferralCode", mock.Anything, "ABCD2345").Return(&models.User{Id: "u0"}, nil).Once()
+		mockUserRepo.On("GetByReferralCodeIncludeDeleted", mock.Anything, mock.AnythingOfType("string")).Return(nil, models.ErrNotFound).Once()
+		mockIs.On("CreateUser", mock.Anything, mock.AnythingOfType("*models.RegisterAndUpdateUser")).
+			Return(&models.RegisterAndUpdateUser{Id: "u1"}, nil).Once()
+		mockTx.On("Do", mock.Anything, mock.Anything).Return(inTransaction).Once()
+		mockUserRepo.On("Insert", mock.Anything, mock.Anything).Return(nil).Once()
+		mockReferralUc.On("Refer", mock.Anything, "u1", "abcd-2345").Return(&models.Referral{Id: 1}, nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), mockReferralUc, earned(nil), new(_pictureMock.Uploader), mockTx, time.Second*2)
+		ar := newCommand()
+		ar.ReferrerCode = "abcd-2345"
+		require.NoError(t, u.Create(context.TODO(), ar, "admin"))
+		mockUserRepo.AssertExpectations(t)
+		mockReferralUc.AssertExpectations(t)
+	})
+
+	t.Run("unknown-referrer", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockUserRepo.On("GetByUserEmailIncludeDeleted", mock.Anything, "john@mail.com").Return(nil, models.ErrNotFound).Once()
+		mockUserRepo.On("GetByReferralCode", mock.Anything, "NOPE2345").Return(nil, models.ErrNotFound).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, mockIs, new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), new(_pictureMock.Uploader), new(_txMock.Manager), time.Second*2)
+		ar := newCommand()
+		ar.ReferrerCode = "nope2345"
+		err := u.Create(context.TODO(), ar, "admin")
+		assert.Equal(t, referral.ErrUnknownCode, err)
+		mockIs.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUploadProfilePicture(t *testing.T) {
+	pict := &models.Picture{Url: "/uploads/users/u1/avatar-9f86d081884c7d65-512.jpg"}
+	previous := "/uploads/users/u1/avatar-60303ae22b998861-512.jpg"
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockUploader := new(_pictureMock.Uploader)
+		file := strings.NewReader("jpeg")
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(&models.User{Id: "u1", ProfilePictUrl: previous}, nil).Once()
+		mockUploader.On("Upload", mock.Anything, "users/u1/avatar", file).Return(pict, nil).Once()
+		mockUserRepo.On("UpdateProfilePictUrl", mock.Anything, "u1", pict.Url, "john@mail.com").Return(nil).Once()
+		mockUploader.On("Remove", mock.Anything, "users/u1/avatar", previous).Return(nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, new(_isMock.Usecase), new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), mockUploader, new(_txMock.Manager), time.Second*2)
+		res, err := u.UploadProfilePicture(context.TODO(), "u1", file, "john@mail.com")
+		require.NoError(t, err)
+		assert.Equal(t, pict, res)
+		mockUserRepo.AssertExpectations(t)
+		mockUploader.AssertExpectations(t)
+	})
+
+	t.Run("update-failed", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockUploader := new(_pictureMock.Uploader)
+		file := strings.NewReader("jpeg")
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(&models.User{Id: "u1", ProfilePictUrl: previous}, nil).Once()
+		mockUploader.On("Upload", mock.Anything, "users/u1/avatar", file).Return(pict, nil).Once()
+		mockUserRepo.On("UpdateProfilePictUrl", mock.Anything, "u1", pict.Url, "john@mail.com").Return(models.ErrNotFound).Once()
+		mockUploader.On("Remove", mock.Anything, "users/u1/avatar", pict.Url).Return(nil).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, new(_isMock.Usecase), new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), mockUploader, new(_txMock.Manager), time.Second*2)
+		_, err := u.UploadProfilePicture(context.TODO(), "u1", file, "john@mail.com")
+		assert.Equal(t, models.ErrNotFound, err)
+		mockUploader.AssertExpectations(t)
+		mockUploader.AssertNotCalled(t, "Remove", mock.Anything, "users/u1/avatar", previous)
+	})
+
+	t.Run("unknown-user", func(t *testing.T) {
+		mockUserRepo := new(mocks.Repository)
+		mockUploader := new(_pictureMock.Uploader)
+		mockUserRepo.On("GetByID", mock.Anything, "u9").Return(nil, models.ErrNotFound).Once()
+
+		u := ucase.NewuserUsecase(mockUserRepo, new(_isMock.Usecase), new(_isMock.Repository), new(_referralMock.Usecase), new(_pointsMock.Usecase), mockUploader, new(_txMock.Manager), time.Second*2)
+		_, err := u.UploadProfilePicture(context.TODO(), "u9", strings.NewReader("jpeg"), "admin")
+		assert.Equal(t, models.ErrNotFound, err)
+		mockUploader.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,202 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// maxMemory is how much of a multipart form is kept in memory, the rest goes to temporary files
+const maxMemory = 32 << 20
+
+// ErrUnsupportedMediaType will throw if the request body is neither JSON nor a form
+var ErrUnsupportedMediaType = errors.New("The request body must be application/json, application/x-www-form-urlencoded or multipart/form-data")
+
+// Body represent the fields of a JSON object or a form sent as the request body
+type Body struct {
+	json map[string]json.RawMessage
+	form url.Values
+}
+
+// Decode will read the fields of the request body, by its content type.
+// A request without a body has no fields.
+func Decode(r *http.Request) (*Body, error) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" && r.ContentLength <= 0 {
+		return &Body{form: url.Values{}}, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	switch mediaType {
+	case "application/json":
+		fields := map[string]json.RawMessage{}
+		if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+			return nil, &Error{Message: "The request body is not a valid JSON object"}
+		}
+		return &Body{json: fields}, nil
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		if err := r.ParseMultipartForm(maxMemory); err != nil && err != http.ErrNotMultipart {
+			return nil, &Error{Message: "The request body is not a valid form"}
+		}
+		return &Body{form: r.PostForm}, nil
+	default:
+		return nil, ErrUnsupportedMediaType
+	}
+}
+
+// StatusCode will return the status answered to a request rejected by Decode or Bind
+func StatusCode(err error) int {
+	if errors.Is(err, ErrUnsupportedMediaType) {
+		return http.StatusUnsupportedMediaType
+	}
+	return http.StatusBadRequest
+}
+
+// Has will tell whether the field was sent, even empty
+func (b *Body) Has(name string) bool {
+	if b.json != nil {
+		_, ok := b.json[name]
+		return ok
+	}
+	_, ok := b.form[name]
+	return ok
+}
+
+// Bind will set the fields of the struct pointed by dst from the body, by the name of their json tag, and check their
+// validate tags. A field that is unknown, sent twice in a form or of the wrong type is reported as an *Error
+// instead of being left at its zero value.
+func (b *Body) Bind(dst interface{}) error {
+	val := reflect.ValueOf(dst).Elem()
+	typ := val.Type()
+
+	var fields []FieldError
+	known := map[string]bool{}
+	for i := 0; i < typ.NumField(); i++ {
+		name := fieldName(typ.Field(i))
+		if name == "" || typ.Field(i).PkgPath != "" {
+			continue
+		}
+		known[name] = true
+		if fe := b.set(name, val.Field(i)); fe != nil {
+			fields = append(fields, *fe)
+		}
+	}
+	for _, name := range b.names() {
+		if !known[name] {
+			fields = append(fields, FieldError{Field: name, Rule: "unknown", Message: "is not a known field"})
+		}
+	}
+	if len(fields) > 0 {
+		return invalid(fields)
+	}
+	return Struct(dst)
+}
+
+// names will return the names of the fields sent, sorted
+func (b *Body) names() []string {
+	var names []string
+	if b.json != nil {
+		for name := range b.json {
+			names = append(names, name)
+		}
+	} else {
+		for name := range b.form {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// set will decode the field name of the body into v, a missing field leaves v as is
+func (b *Body) set(name string, v reflect.Value) *FieldError {
+	if b.json != nil {
+		raw, ok := b.json[name]
+		if !ok {
+			return nil
+		}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		if err := decoder.Decode(v.Addr().Interface()); err != nil {
+			return typeError(name, v.Kind())
+		}
+		return nil
+	}
+
+	values, ok := b.form[name]
+	if !ok {
+		return nil
+	}
+	if len(values) > 1 {
+		return &FieldError{Field: name, Rule: "single", Message: "must be given once"}
+	}
+	if !setString(values[0], v) {
+		return typeError(name, v.Kind())
+	}
+	return nil
+}
+
+// setString will parse a form value into v by its kind
+func setString(s string, v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetUint(n)
+	case reflect.Bool:
+		bv, err := strconv.ParseBool(s)
+		if err != nil {
+			return false
+		}
+		v.SetBool(bv)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetFloat(f)
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if !setString(s, elem.Elem()) {
+			return false
+		}
+		v.Set(elem)
+	default:
+		return false
+	}
+	return true
+}
+
+func typeError(name string, kind reflect.Kind) *FieldError {
+	expected := "a valid value"
+	switch kind {
+	case reflect.String:
+		expected = "a string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		expected = "an integer"
+	case reflect.Float32, reflect.Float64:
+		expected = "a number"
+	case reflect.Bool:
+		expected = "a boolean"
+	}
+	return &FieldError{Field: name, Rule: "type", Message: "must be " + expected}
+}
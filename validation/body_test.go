@@ -0,0 +1,109 @@
+package validation_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/models"
+	"github.com/validation"
+)
+
+type command struct {
+	Name    string `json:"name" validate:"required"`
+	Email   string `json:"email" validate:"required,email"`
+	Phone   string `json:"phone" validate:"omitempty,e164"`
+	Age     int    `json:"age" validate:"min=17"`
+	Born    string `json:"born" validate:"omitempty,datetime=2006-01-02"`
+	Kind    string `json:"kind" validate:"omitempty,oneof=user merchant"`
+	Tag     string `json:"tag" validate:"omitempty,slug"`
+	Ignored string `json:"-"`
+}
+
+func request(contentType string, body string) *http.Request {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	return req
+}
+
+func bind(t *testing.T, req *http.Request) (*command, error) {
+	body, err := validation.Decode(req)
+	require.NoError(t, err)
+	var cmd command
+	return &cmd, body.Bind(&cmd)
+}
+
+func fieldErrors(t *testing.T, err error) []validation.FieldError {
+	var validationErr *validation.Error
+	require.True(t, errors.As(err, &validationErr), "%v", err)
+	assert.True(t, errors.Is(err, models.ErrBadParamInput))
+	return validationErr.Errors
+}
+
+func TestBindJSON(t *testing.T) {
+	cmd, err := bind(t, request("application/json; charset=utf-8",
+		`{"name": "John", "email": "john@mail.com", "phone": "+6281234567890", "age": 30, "born": "1990-01-02", "kind": "user"}`))
+	require.NoError(t, err)
+	assert.Equal(t, command{Name: "John", Email: "john@mail.com", Phone: "+6281234567890", Age: 30, Born: "1990-01-02", Kind: "user"}, *cmd)
+}
+
+func TestBindForm(t *testing.T) {
+	form := url.Values{"name": {"John"}, "email": {"john@mail.com"}, "age": {"30"}}
+	cmd, err := bind(t, request("application/x-www-form-urlencoded", form.Encode()))
+	require.NoError(t, err)
+	assert.Equal(t, command{Name: "John", Email: "john@mail.com", Age: 30}, *cmd)
+}
+
+func TestBindTypeErrors(t *testing.T) {
+	_, err := bind(t, request("application/json", `{"name": "John", "email": "john@mail.com", "phone": 6281234567890, "age": "thirty"}`))
+	assert.Equal(t, []validation.FieldError{
+		{Field: "phone", Rule: "type", Message: "must be a string"},
+		{Field: "age", Rule: "type", Message: "must be an integer"},
+	}, fieldErrors(t, err))
+
+	form := url.Values{"name": {"John", "Johnny"}, "email": {"john@mail.com"}, "age": {"abc"}, "points": {"10"}}
+	_, err = bind(t, request("application/x-www-form-urlencoded", form.Encode()))
+	assert.Equal(t, []validation.FieldError{
+		{Field: "name", Rule: "single", Message: "must be given once"},
+		{Field: "age", Rule: "type", Message: "must be an integer"},
+		{Field: "points", Rule: "unknown", Message: "is not a known field"},
+	}, fieldErrors(t, err))
+}
+
+func TestBindRules(t *testing.T) {
+	_, err := bind(t, request("application/json", `{"email": "john", "phone": "081234567890", "age": 12, "born": "02/01/1990", "kind": "admin", "tag": "Street Food"}`))
+	assert.Equal(t, []validation.FieldError{
+		{Field: "name", Rule: "required", Message: "is required"},
+		{Field: "email", Rule: "email", Message: "must be an email address"},
+		{Field: "phone", Rule: "e164", Message: "must be a phone number in the E.164 format, e.g. +6281234567890"},
+		{Field: "age", Rule: "min", Message: "must be at least 17"},
+		{Field: "born", Rule: "datetime", Message: "must be a date formatted as 2006-01-02"},
+		{Field: "kind", Rule: "oneof", Message: "must be one of user, merchant"},
+		{Field: "tag", Rule: "slug", Message: "must be lowercase letters and digits joined by dashes, e.g. street-food"},
+	}, fieldErrors(t, err))
+}
+
+func TestDecode(t *testing.T) {
+	body, err := validation.Decode(request("application/json", `{"points": 10}`))
+	require.NoError(t, err)
+	assert.True(t, body.Has("points"))
+	assert.False(t, body.Has("name"))
+
+	_, err = validation.Decode(request("application/json", `["not", "an", "object"]`))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, validation.StatusCode(err))
+
+	_, err = validation.Decode(request("text/plain", `name=John`))
+	assert.Equal(t, validation.ErrUnsupportedMediaType, err)
+	assert.Equal(t, http.StatusUnsupportedMediaType, validation.StatusCode(err))
+
+	body, err = validation.Decode(httptest.NewRequest("PUT", "/", nil))
+	require.NoError(t, err)
+	assert.False(t, body.Has("name"))
+}
@@ -0,0 +1,132 @@
+// Package validation decodes the JSON and form request bodies into the commands and checks their validate tags.
+// A rejected request is reported field by field instead of as a single message.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/models"
+)
+
+// e164 matches a phone number in the E.164 format, a + followed by the country code and at most 15 digits
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// slug matches lowercase words joined by dashes, as used in the urls
+var slug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// validate is shared by the requests, a validator caches the rules of the structs it has seen
+var validate = newValidator()
+
+// FieldError represent a field of the request that is malformed or breaks one of its rules
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error represent a request rejected because of its fields, it matches models.ErrBadParamInput with errors.Is
+type Error struct {
+	models.BadParam
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors"`
+}
+
+func (e *Error) Error() string {
+	if len(e.Errors) == 0 {
+		return e.Message
+	}
+	fields := make([]string, len(e.Errors))
+	for i, f := range e.Errors {
+		fields[i] = f.Field + " " + f.Message
+	}
+	return e.Message + ": " + strings.Join(fields, ", ")
+}
+
+// invalid will build the Error of the given field errors
+func invalid(fields []FieldError) *Error {
+	return &Error{Message: "The request is not valid", Errors: fields}
+}
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// the errors name the fields as the clients send them
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return fieldName(field)
+	})
+	v.RegisterValidation("e164", func(fl validator.FieldLevel) bool {
+		return e164.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slug.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("datetime", func(fl validator.FieldLevel) bool {
+		_, err := time.Parse(fl.Param(), fl.Field().String())
+		return err == nil
+	})
+	return v
+}
+
+// Struct will check the validate tags of the struct s, the broken rules are returned as an *Error
+func Struct(s interface{}) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+	fields := make([]FieldError, len(validationErrors))
+	for i, fe := range validationErrors {
+		fields[i] = FieldError{Field: fe.Field(), Rule: fe.Tag(), Message: message(fe)}
+	}
+	return invalid(fields)
+}
+
+// message will describe the rule broken by a field
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be an email address"
+	case "e164":
+		return "must be a phone number in the E.164 format, e.g. +6281234567890"
+	case "slug":
+		return "must be lowercase letters and digits joined by dashes, e.g. street-food"
+	case "datetime":
+		return "must be a date formatted as " + fe.Param()
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "min", "max":
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		if fe.Kind() == reflect.String {
+			return fmt.Sprintf("must be %s %s characters long", bound, fe.Param())
+		}
+		return fmt.Sprintf("must be %s %s", bound, fe.Param())
+	default:
+		return "must satisfy the " + fe.Tag() + " rule"
+	}
+}
+
+// fieldName will return the name of the field in the request, the name of its json tag
+func fieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
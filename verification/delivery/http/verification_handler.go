@@ -0,0 +1,119 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/sirupsen/logrus"
+	validator "gopkg.in/go-playground/validator.v9"
+
+	"github.com/middleware"
+	"github.com/models"
+	"github.com/verification"
+)
+
+// ResponseError represent the reseponse error struct
+type ResponseError struct {
+	Message string `json:"message"`
+}
+
+// verificationHandler  represent the httphandler for the verification codes
+type verificationHandler struct {
+	VerificationUsecase verification.Usecase
+}
+
+// NewverificationHandler will initialize the verification resources endpoint
+func NewverificationHandler(e *echo.Echo, us verification.Usecase, mw *middleware.GoMiddleware) {
+	handler := &verificationHandler{
+		VerificationUsecase: us,
+	}
+	ownerOrAdmin := mw.Authorize(middleware.Policy{
+		Roles:      []string{models.RoleUser, models.RoleAdmin},
+		OwnerParam: "id",
+	})
+	userOnly := mw.Authorize(middleware.Policy{Roles: []string{models.RoleUser}})
+	e.POST("/users/:id/verification", handler.Send, mw.Authenticate, ownerOrAdmin)
+	e.POST("/users/verify", handler.Verify, mw.Authenticate, userOnly)
+}
+
+func isRequestValid(m interface{}) (bool, error) {
+	validate := validator.New()
+	err := validate.Struct(m)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Send will send a new verification code to the email address or the phone number of the user
+func (a *verificationHandler) Send(c echo.Context) error {
+	var command models.NewCommandVerification
+	err := c.Bind(&command)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+	}
+
+	if ok, err := isRequestValid(&command); !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = a.VerificationUsecase.Send(ctx, c.Param("id"), command.Channel)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusAccepted)
+}
+
+// Verify will confirm the code sent to the authenticated user
+func (a *verificationHandler) Verify(c echo.Context) error {
+	var command models.VerifyUser
+	err := c.Bind(&command)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, ResponseError{Message: err.Error()})
+	}
+
+	if ok, err := isRequestValid(&command); !ok {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	ctx := c.Request().Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	err = a.VerificationUsecase.Verify(ctx, middleware.GetPrincipal(c).Id, &command)
+	if err != nil {
+		return c.JSON(getStatusCode(err), ResponseError{Message: err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func getStatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	logrus.Error(err)
+	switch {
+	case errors.Is(err, verification.ErrTooManyAttempts), errors.Is(err, verification.ErrResendTooSoon):
+		return http.StatusTooManyRequests
+	case errors.Is(err, models.ErrInternalServerError):
+		return http.StatusInternalServerError
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrUnAuthorize):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrBadParamInput):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,70 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/middleware/middlewaretest"
+	"github.com/models"
+	"github.com/verification"
+	verificationHttp "github.com/verification/delivery/http"
+	"github.com/verification/mocks"
+)
+
+func newServer(mockUCase *mocks.Usecase) *echo.Echo {
+	e := echo.New()
+	verificationHttp.NewverificationHandler(e, mockUCase, middlewaretest.New())
+	return e
+}
+
+func serve(e *echo.Echo, method string, path string, body string, token string) *httptest.ResponseRecorder {
+	return middlewaretest.Serve(e, middlewaretest.NewRequest(method, path, body), token)
+}
+
+func TestSend(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Send", mock.Anything, "u1", models.VerificationSMS).Return(nil).Once()
+	mockUCase.On("Send", mock.Anything, "u1", models.VerificationEmail).Return(verification.ErrResendTooSoon).Once()
+
+	rec := serve(e, echo.POST, "/users/u1/verification", `{"channel":"sms"}`, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	rec = serve(e, echo.POST, "/users/u1/verification", `{"channel":"email"}`, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	rec = serve(e, echo.POST, "/users/u1/verification", `{"channel":"fax"}`, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(e, echo.POST, "/users/u2/verification", `{"channel":"sms"}`, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
+
+func TestVerify(t *testing.T) {
+	mockUCase := new(mocks.Usecase)
+	e := newServer(mockUCase)
+	mockUCase.On("Verify", mock.Anything, "u1", &models.VerifyUser{Channel: models.VerificationEmail, Code: "123456"}).Return(nil).Once()
+	mockUCase.On("Verify", mock.Anything, "u1", &models.VerifyUser{Channel: models.VerificationEmail, Code: "000000"}).
+		Return(verification.ErrInvalidCode).Once()
+	mockUCase.On("Verify", mock.Anything, "u1", &models.VerifyUser{Channel: models.VerificationEmail, Code: "111111"}).
+		Return(verification.ErrTooManyAttempts).Once()
+
+	rec := serve(e, echo.POST, "/users/verify", `{"channel":"email","code":"123456"}`, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = serve(e, echo.POST, "/users/verify", `{"channel":"email","code":"000000"}`, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(e, echo.POST, "/users/verify", `{"channel":"email","code":"111111"}`, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	rec = serve(e, echo.POST, "/users/verify", `{"channel":"email","code":"12ab"}`, middlewaretest.UserToken)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUCase.AssertExpectations(t)
+}
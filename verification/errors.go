@@ -0,0 +1,21 @@
+package verification
+
+import (
+	"errors"
+
+	"github.com/models"
+)
+
+var (
+	// ErrNoPendingCode will throw if no code was sent to the user through the given channel
+	ErrNoPendingCode = models.NewBadParam("No verification code was sent through this channel")
+	// ErrCodeExpired will throw if the code was sent longer ago than the configured lifetime
+	ErrCodeExpired = models.NewBadParam("The verification code has expired")
+	// ErrInvalidCode will throw if the given code is not the one that was sent
+	ErrInvalidCode = models.NewBadParam("Invalid verification code")
+
+	// ErrTooManyAttempts will throw if the user used every attempt at the pending code, a new code must be sent
+	ErrTooManyAttempts = errors.New("Too many attempts, request a new verification code")
+	// ErrResendTooSoon will throw if a code is requested again before the resend interval is over
+	ErrResendTooSoon = errors.New("A verification code was sent recently, try again later")
+)
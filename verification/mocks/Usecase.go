@@ -0,0 +1,39 @@
+// Code generated by mockery v1.0.0
+package mocks
+
+import context "context"
+import mock "github.com/stretchr/testify/mock"
+import models "github.com/models"
+
+// Usecase is an autogenerated mock type for the Usecase type
+type Usecase struct {
+	mock.Mock
+}
+
+// Send provides a mock function with given fields: ctx, userId, channel
+func (_m *Usecase) Send(ctx context.Context, userId string, channel string) error {
+	ret := _m.Called(ctx, userId, channel)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userId, channel)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Verify provides a mock function with given fields: ctx, userId, v
+func (_m *Usecase) Verify(ctx context.Context, userId string, v *models.VerifyUser) error {
+	ret := _m.Called(ctx, userId, v)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *models.VerifyUser) error); ok {
+		r0 = rf(ctx, userId, v)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
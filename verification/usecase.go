@@ -0,0 +1,13 @@
+package verification
+
+import (
+	"context"
+
+	"github.com/models"
+)
+
+// Usecase represent the verification of the email address and the phone number of the users
+type Usecase interface {
+	Send(ctx context.Context, userId string, channel string) error
+	Verify(ctx context.Context, userId string, v *models.VerifyUser) error
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/identityserver"
+	"github.com/models"
+	"github.com/notifier"
+	"github.com/referral"
+	"github.com/user"
+	"github.com/verification"
+)
+
+// codeSpace is the number of distinct 6 digit codes
+var codeSpace = big.NewInt(1000000)
+
+// Options represent the lifetime of the codes and how often they can be tried and sent
+type Options struct {
+	CodeTTL        time.Duration
+	MaxAttempts    int
+	ResendInterval time.Duration
+}
+
+type verificationUsecase struct {
+	userRepo         user.Repository
+	identityServerUc identityserver.Usecase
+	referralUc       referral.Usecase
+	email            notifier.Notifier
+	sms              notifier.Notifier
+	opts             Options
+	contextTimeout   time.Duration
+}
+
+// NewverificationUsecase will create new an verificationUsecase object representation of verification.Usecase interface.
+// The codes are sent through email or sms depending on the channel the user asks for.
+func NewverificationUsecase(ur user.Repository, is identityserver.Usecase, referralUc referral.Usecase, email notifier.Notifier, sms notifier.Notifier, opts Options, timeout time.Duration) verification.Usecase {
+	return &verificationUsecase{
+		userRepo:         ur,
+		identityServerUc: is,
+		referralUc:       referralUc,
+		email:            email,
+		sms:              sms,
+		opts:             opts,
+		contextTimeout:   timeout,
+	}
+}
+
+// Send will generate a new code for the channel, replacing the pending one, and send it to the user.
+// A code can only be sent again once the resend interval is over.
+func (m verificationUsecase) Send(c context.Context, userId string, channel string) error {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	existedUser, err := m.userRepo.GetByID(ctx, userId)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if existedUser.VerificationSendDate != nil && now.Sub(*existedUser.VerificationSendDate) < m.opts.ResendInterval {
+		return verification.ErrResendTooSoon
+	}
+
+	n, err := rand.Int(rand.Reader, codeSpace)
+	if err != nil {
+		return err
+	}
+	code := int(n.Int64())
+	if err := m.userRepo.SetVerificationCode(ctx, userId, channel, code, now); err != nil {
+		return err
+	}
+
+	msg := &notifier.Message{
+		Subject: "Your verification code",
+		Body:    fmt.Sprintf("Your verification code is %06d. It expires in %d minutes.", code, int(m.opts.CodeTTL.Minutes())),
+	}
+	if channel == models.VerificationSMS {
+		msg.To = existedUser.PhoneNumber
+		return m.sms.Send(ctx, msg)
+	}
+	msg.To = existedUser.UserEmail
+	return m.email.Send(ctx, msg)
+}
+
+// Verify will check the code against the pending one of the channel and mark the email or the phone number as verified.
+// Every try counts as an attempt, once the attempts are used a new code must be sent.
+// A verified email is also recorded at the identity server and the first verification rewards a pending referral.
+func (m verificationUsecase) Verify(c context.Context, userId string, v *models.VerifyUser) error {
+	ctx, cancel := context.WithTimeout(c, m.contextTimeout)
+	defer cancel()
+
+	existedUser, err := m.userRepo.GetByID(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if existedUser.VerificationChannel != v.Channel || existedUser.VerificationSendDate == nil {
+		return verification.ErrNoPendingCode
+	}
+	if time.Since(*existedUser.VerificationSendDate) > m.opts.CodeTTL {
+		return verification.ErrCodeExpired
+	}
+	err = m.userRepo.IncrementVerificationAttempts(ctx, userId, m.opts.MaxAttempts)
+	if errors.Is(err, models.ErrConflict) {
+		return verification.ErrTooManyAttempts
+	}
+	if err != nil {
+		return err
+	}
+
+	expected := fmt.Sprintf("%06d", existedUser.VerificationCode)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(v.Code)) != 1 {
+		return verification.ErrInvalidCode
+	}
+
+	if v.Channel == models.VerificationEmail {
+		updateUser := models.RegisterAndUpdateUser{
+			Id:            existedUser.Id,
+			Username:      existedUser.UserEmail,
+			Name:          existedUser.FullName,
+			Email:         existedUser.UserEmail,
+			EmailVerified: true,
+		}
+		if _, err := m.identityServerUc.UpdateUser(ctx, &updateUser); err != nil {
+			return err
+		}
+	}
+	if err := m.userRepo.MarkVerified(ctx, userId, v.Channel); err != nil {
+		return err
+	}
+
+	_, err = m.referralUc.Reward(ctx, userId)
+	if err != nil && !errors.Is(err, models.ErrNotFound) {
+		// the verification stands, the referral can still be rewarded by an admin
+		logrus.Errorf("reward referral of user %s: %v", userId, err)
+	}
+	return nil
+}
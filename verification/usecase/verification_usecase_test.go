@@ -0,0 +1,154 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	_isMock "github.com/identityserver/mocks"
+	"github.com/models"
+	"github.com/notifier/memory"
+	_referralMock "github.com/referral/mocks"
+	_userMock "github.com/user/mocks"
+	"github.com/verification"
+	ucase "github.com/verification/usecase"
+)
+
+var opts = ucase.Options{CodeTTL: 15 * time.Minute, MaxAttempts: 5, ResendInterval: time.Minute}
+
+func pendingUser(channel string, code int, sentAt time.Time) *models.User {
+	return &models.User{
+		Id:                   "u1",
+		UserEmail:            "john@mail.com",
+		FullName:             "John",
+		PhoneNumber:          "+6281234567890",
+		VerificationChannel:  channel,
+		VerificationCode:     code,
+		VerificationSendDate: &sentAt,
+	}
+}
+
+func TestSend(t *testing.T) {
+	t.Run("email", func(t *testing.T) {
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(&models.User{Id: "u1", UserEmail: "john@mail.com"}, nil).Once()
+		var code int
+		mockUserRepo.On("SetVerificationCode", mock.Anything, "u1", models.VerificationEmail, mock.AnythingOfType("int"), mock.Anything).
+			Run(func(args mock.Arguments) { code = args.Int(3) }).Return(nil).Once()
+		email, sms := memory.New(), memory.New()
+
+		u := ucase.NewverificationUsecase(mockUserRepo, new(_isMock.Usecase), new(_referralMock.Usecase), email, sms, opts, time.Second*2)
+		err := u.Send(context.TODO(), "u1", models.VerificationEmail)
+		require.NoError(t, err)
+
+		msg := email.Last("john@mail.com")
+		require.NotNil(t, msg)
+		assert.Contains(t, msg.Body, fmt.Sprintf("%06d", code))
+		assert.Empty(t, sms.Messages())
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("sms", func(t *testing.T) {
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(&models.User{Id: "u1", PhoneNumber: "+6281234567890"}, nil).Once()
+		mockUserRepo.On("SetVerificationCode", mock.Anything, "u1", models.VerificationSMS, mock.AnythingOfType("int"), mock.Anything).Return(nil).Once()
+		email, sms := memory.New(), memory.New()
+
+		u := ucase.NewverificationUsecase(mockUserRepo, new(_isMock.Usecase), new(_referralMock.Usecase), email, sms, opts, time.Second*2)
+		require.NoError(t, u.Send(context.TODO(), "u1", models.VerificationSMS))
+		assert.NotNil(t, sms.Last("+6281234567890"))
+		assert.Empty(t, email.Messages())
+	})
+
+	t.Run("too-soon", func(t *testing.T) {
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(pendingUser(models.VerificationEmail, 123456, time.Now().Add(-10*time.Second)), nil).Once()
+
+		u := ucase.NewverificationUsecase(mockUserRepo, new(_isMock.Usecase), new(_referralMock.Usecase), memory.New(), memory.New(), opts, time.Second*2)
+		err := u.Send(context.TODO(), "u1", models.VerificationEmail)
+		assert.Equal(t, verification.ErrResendTooSoon, err)
+		mockUserRepo.AssertNotCalled(t, "SetVerificationCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestVerify(t *testing.T) {
+	t.Run("email", func(t *testing.T) {
+		mockUserRepo := new(_userMock.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockReferral := new(_referralMock.Usecase)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(pendingUser(models.VerificationEmail, 42, time.Now()), nil).Once()
+		mockUserRepo.On("IncrementVerificationAttempts", mock.Anything, "u1", 5).Return(nil).Once()
+		mockIs.On("UpdateUser", mock.Anything, mock.MatchedBy(func(ar *models.RegisterAndUpdateUser) bool {
+			return ar.Id == "u1" && ar.Email == "john@mail.com" && ar.EmailVerified
+		})).Return(&models.RegisterAndUpdateUser{}, nil).Once()
+		mockUserRepo.On("MarkVerified", mock.Anything, "u1", models.VerificationEmail).Return(nil).Once()
+		mockReferral.On("Reward", mock.Anything, "u1").Return(nil, models.ErrNotFound).Once()
+
+		u := ucase.NewverificationUsecase(mockUserRepo, mockIs, mockReferral, memory.New(), memory.New(), opts, time.Second*2)
+		err := u.Verify(context.TODO(), "u1", &models.VerifyUser{Channel: models.VerificationEmail, Code: "000042"})
+		require.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockIs.AssertExpectations(t)
+		mockReferral.AssertExpectations(t)
+	})
+
+	t.Run("sms", func(t *testing.T) {
+		mockUserRepo := new(_userMock.Repository)
+		mockIs := new(_isMock.Usecase)
+		mockReferral := new(_referralMock.Usecase)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(pendingUser(models.VerificationSMS, 654321, time.Now()), nil).Once()
+		mockUserRepo.On("IncrementVerificationAttempts", mock.Anything, "u1", 5).Return(nil).Once()
+		mockUserRepo.On("MarkVerified", mock.Anything, "u1", models.VerificationSMS).Return(nil).Once()
+		mockReferral.On("Reward", mock.Anything, "u1").Return(&models.Referral{Status: models.ReferralRewarded}, nil).Once()
+
+		u := ucase.NewverificationUsecase(mockUserRepo, mockIs, mockReferral, memory.New(), memory.New(), opts, time.Second*2)
+		err := u.Verify(context.TODO(), "u1", &models.VerifyUser{Channel: models.VerificationSMS, Code: "654321"})
+		require.NoError(t, err)
+		mockIs.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+		mockReferral.AssertExpectations(t)
+	})
+
+	t.Run("wrong-code", func(t *testing.T) {
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(pendingUser(models.VerificationEmail, 123456, time.Now()), nil).Once()
+		mockUserRepo.On("IncrementVerificationAttempts", mock.Anything, "u1", 5).Return(nil).Once()
+
+		u := ucase.NewverificationUsecase(mockUserRepo, new(_isMock.Usecase), new(_referralMock.Usecase), memory.New(), memory.New(), opts, time.Second*2)
+		err := u.Verify(context.TODO(), "u1", &models.VerifyUser{Channel: models.VerificationEmail, Code: "654321"})
+		assert.Equal(t, verification.ErrInvalidCode, err)
+		mockUserRepo.AssertNotCalled(t, "MarkVerified", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("too-many-attempts", func(t *testing.T) {
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(pendingUser(models.VerificationEmail, 123456, time.Now()), nil).Once()
+		mockUserRepo.On("IncrementVerificationAttempts", mock.Anything, "u1", 5).Return(models.ErrConflict).Once()
+
+		u := ucase.NewverificationUsecase(mockUserRepo, new(_isMock.Usecase), new(_referralMock.Usecase), memory.New(), memory.New(), opts, time.Second*2)
+		err := u.Verify(context.TODO(), "u1", &models.VerifyUser{Channel: models.VerificationEmail, Code: "123456"})
+		assert.Equal(t, verification.ErrTooManyAttempts, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(pendingUser(models.VerificationEmail, 123456, time.Now().Add(-time.Hour)), nil).Once()
+
+		u := ucase.NewverificationUsecase(mockUserRepo, new(_isMock.Usecase), new(_referralMock.Usecase), memory.New(), memory.New(), opts, time.Second*2)
+		err := u.Verify(context.TODO(), "u1", &models.VerifyUser{Channel: models.VerificationEmail, Code: "123456"})
+		assert.Equal(t, verification.ErrCodeExpired, err)
+	})
+
+	t.Run("other-channel", func(t *testing.T) {
+		mockUserRepo := new(_userMock.Repository)
+		mockUserRepo.On("GetByID", mock.Anything, "u1").Return(pendingUser(models.VerificationSMS, 123456, time.Now()), nil).Once()
+
+		u := ucase.NewverificationUsecase(mockUserRepo, new(_isMock.Usecase), new(_referralMock.Usecase), memory.New(), memory.New(), opts, time.Second*2)
+		err := u.Verify(context.TODO(), "u1", &models.VerifyUser{Channel: models.VerificationEmail, Code: "123456"})
+		assert.Equal(t, verification.ErrNoPendingCode, err)
+	})
+}